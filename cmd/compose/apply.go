@@ -0,0 +1,68 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type applyOptions struct {
+	replan bool
+}
+
+func applyCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := applyOptions{}
+	cmd := &cobra.Command{
+		Use:   "apply [OPTIONS] PLAN_FILE",
+		Short: "Apply a plan saved by `up --plan-out`",
+		Args:  cli.ExactArgs(1),
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runApply(ctx, dockerCli, backendOptions, opts, args[0])
+		}),
+	}
+	cmd.Flags().BoolVar(&opts.replan, "replan", false, "Apply an updated plan if project resources changed since the plan was saved, instead of failing")
+	return cmd
+}
+
+func runApply(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts applyOptions, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	plan, err := compose.LoadPlan(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	return backend.Apply(ctx, plan, api.ApplyOptions{
+		Replan: opts.replan,
+	})
+}
@@ -40,6 +40,7 @@ type buildOptions struct {
 	push       bool
 	args       []string
 	noCache    bool
+	force      bool
 	memory     cliopts.MemBytes
 	ssh        string
 	builder    string
@@ -48,6 +49,7 @@ type buildOptions struct {
 	check      bool
 	sbom       string
 	provenance string
+	report     string
 }
 
 func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions, error) {
@@ -78,6 +80,7 @@ func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions,
 		Progress:   uiMode,
 		Args:       types.NewMappingWithEquals(opts.args),
 		NoCache:    opts.noCache,
+		Force:      opts.force,
 		Quiet:      opts.quiet,
 		Services:   services,
 		Deps:       opts.deps,
@@ -88,6 +91,7 @@ func (opts buildOptions) toAPIBuildOptions(services []string) (api.BuildOptions,
 		Builder:    builderName,
 		SBOM:       opts.sbom,
 		Provenance: opts.provenance,
+		Report:     opts.report,
 	}, nil
 }
 
@@ -138,6 +142,7 @@ func buildCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Back
 	flags.Bool("force-rm", true, "Always remove intermediate containers. DEPRECATED")
 	flags.MarkHidden("force-rm") //nolint:errcheck
 	flags.BoolVar(&opts.noCache, "no-cache", false, "Do not use cache when building the image")
+	flags.BoolVar(&opts.force, "force", false, "Build the images even if they were built from the same inputs")
 	flags.Bool("no-rm", false, "Do not remove intermediate containers after a successful build. DEPRECATED")
 	flags.MarkHidden("no-rm") //nolint:errcheck
 	flags.VarP(&opts.memory, "memory", "m", "Set memory limit for the build container. Not supported by BuildKit.")
@@ -145,6 +150,7 @@ func buildCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Back
 	flags.MarkHidden("progress") //nolint:errcheck
 	flags.BoolVar(&opts.print, "print", false, "Print equivalent bake file")
 	flags.BoolVar(&opts.check, "check", false, "Check build configuration")
+	flags.StringVar(&opts.report, "report", "", "Write a JSON report of the build to this file")
 
 	return cmd
 }
@@ -0,0 +1,129 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+func bundleCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "bundle CMD [OPTIONS]",
+		Short:            "Save and load a compose application with its images, for offline use",
+		TraverseChildren: true,
+	}
+	cmd.AddCommand(
+		bundleSaveCommand(p, dockerCli, backendOptions),
+		bundleLoadCommand(dockerCli, backendOptions),
+	)
+	return cmd
+}
+
+type bundleSaveOptions struct {
+	*ProjectOptions
+	output   string
+	platform string
+}
+
+func bundleSaveCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := bundleSaveOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "save [OPTIONS]",
+		Short: "Save the project and the images it runs to an archive",
+		Args:  cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runBundleSave(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVar(&opts.platform, "platform", "", "Save the images for this platform")
+	return cmd
+}
+
+func runBundleSave(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts bundleSaveOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	project, metrics, err := opts.ToProject(ctx, dockerCli, backend, nil)
+	if err != nil {
+		return err
+	}
+
+	if metrics.CountIncludesLocal > 0 {
+		return errors.New("cannot bundle compose file with local includes")
+	}
+
+	platform := opts.platform
+	if platform == "" {
+		platform = project.Environment["DOCKER_DEFAULT_PLATFORM"]
+	}
+	return backend.SaveBundle(ctx, project, api.BundleSaveOptions{
+		Output:   opts.output,
+		Platform: platform,
+	})
+}
+
+type bundleLoadOptions struct {
+	input     string
+	directory string
+}
+
+func bundleLoadCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := bundleLoadOptions{}
+	cmd := &cobra.Command{
+		Use:   "load [OPTIONS]",
+		Short: "Load the images of a bundle and extract its project",
+		Args:  cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runBundleLoad(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.input, "input", "i", "", "Read from a file, instead of STDIN")
+	flags.StringVar(&opts.directory, "directory", "", "Directory to extract the project to (default: a directory named after the project)")
+	return cmd
+}
+
+func runBundleLoad(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts bundleLoadOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	dir, err := backend.LoadBundle(ctx, api.BundleLoadOptions{
+		Input:     opts.input,
+		Directory: opts.directory,
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Err(), "Project extracted to %s, run `docker compose --project-directory %s up` to start it\n", dir, dir)
+	return nil
+}
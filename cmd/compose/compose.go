@@ -28,6 +28,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/cli"
 	"github.com/compose-spec/compose-go/v2/dotenv"
@@ -70,6 +71,12 @@ const (
 	ComposeMenu = "COMPOSE_MENU"
 	// ComposeProgress defines type of progress output, if --progress isn't used
 	ComposeProgress = "COMPOSE_PROGRESS"
+	// ComposeRetryAttempts enables retrying operations failing with transient engine errors, up to this number of attempts
+	ComposeRetryAttempts = "COMPOSE_RETRY_ATTEMPTS"
+	// ComposeRetryBackoff defines the delay before the first retry, doubled for each next one
+	ComposeRetryBackoff = "COMPOSE_RETRY_BACKOFF"
+	// ComposeRetryOn defines the comma-separated classes of errors to retry, instead of the ones each operation is known to fail with
+	ComposeRetryOn = "COMPOSE_RETRY_ON"
 )
 
 // rawEnv load a dot env file using docker/cli key=value parser, without attempt to interpolate or evaluate values
@@ -150,6 +157,9 @@ type ProjectOptions struct {
 	All                   bool
 	insecureRegistries    []string
 	remoteLoadersOverride []loader.ResourceLoader
+	// includedFiles lists the local files included by the compose files the
+	// project was last loaded from, see IncludedFiles
+	includedFiles []string
 }
 
 // ProjectFunc does stuff within a types.Project
@@ -312,13 +322,16 @@ func (o *ProjectOptions) ToProject(ctx context.Context, dockerCli command.Cli, b
 	var metrics tracing.Metrics
 	remotes := o.remoteLoaders(dockerCli)
 
-	// Setup metrics listener to collect project data
+	// Setup metrics listener to collect project data, and the local included
+	// files
+	var included []string
 	metricsListener := func(event string, metadata map[string]any) {
 		switch event {
 		case "extends":
 			metrics.CountExtends++
 		case "include":
 			paths := metadata["path"].(types.StringList)
+			workingDir, _ := metadata["workingdir"].(string)
 			for _, path := range paths {
 				var isRemote bool
 				for _, r := range remotes {
@@ -331,6 +344,10 @@ func (o *ProjectOptions) ToProject(ctx context.Context, dockerCli command.Cli, b
 					metrics.CountIncludesRemote++
 				} else {
 					metrics.CountIncludesLocal++
+					if !filepath.IsAbs(path) {
+						path = filepath.Join(workingDir, path)
+					}
+					included = append(included, path)
 				}
 			}
 		}
@@ -355,10 +372,18 @@ func (o *ProjectOptions) ToProject(ctx context.Context, dockerCli command.Cli, b
 	if err != nil {
 		return nil, metrics, err
 	}
+	o.includedFiles = included
 
 	return project, metrics, nil
 }
 
+// IncludedFiles returns the local files included by the compose files the
+// project was last loaded from. Only the files the loaded compose files
+// include are reported by the loader, not those their included files do.
+func (o *ProjectOptions) IncludedFiles() []string {
+	return o.includedFiles
+}
+
 func (o *ProjectOptions) remoteLoaders(dockerCli command.Cli) []loader.ResourceLoader {
 	if o.remoteLoadersOverride != nil {
 		return o.remoteLoadersOverride
@@ -504,6 +529,14 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 				backendOptions.Add(compose.WithMaxConcurrency(parallel))
 			}
 
+			retryPolicies, err := resolveRetryPolicies()
+			if err != nil {
+				return err
+			}
+			if retryPolicies != nil {
+				backendOptions.Add(compose.WithRetryPolicies(retryPolicies))
+			}
+
 			// dry run detection
 			if dryRun {
 				backendOptions.Add(compose.WithDryRun)
@@ -539,13 +572,18 @@ func RootCommand(dockerCli command.Cli, backendOptions *BackendOptions) *cobra.C
 		buildCommand(&opts, dockerCli, backendOptions),
 		pushCommand(&opts, dockerCli, backendOptions),
 		pullCommand(&opts, dockerCli, backendOptions),
+		lockCommand(&opts, dockerCli, backendOptions),
 		createCommand(&opts, dockerCli, backendOptions),
+		planCommand(&opts, dockerCli, backendOptions),
+		applyCommand(dockerCli, backendOptions),
+		driftCommand(&opts, dockerCli, backendOptions),
 		copyCommand(&opts, dockerCli, backendOptions),
 		waitCommand(&opts, dockerCli, backendOptions),
 		scaleCommand(&opts, dockerCli, backendOptions),
 		statsCommand(&opts, dockerCli),
 		watchCommand(&opts, dockerCli, backendOptions),
 		publishCommand(&opts, dockerCli, backendOptions),
+		bundleCommand(&opts, dockerCli, backendOptions),
 		alphaCommand(&opts, dockerCli, backendOptions),
 		bridgeCommand(&opts, dockerCli),
 		volumesCommand(&opts, dockerCli, backendOptions),
@@ -676,6 +714,39 @@ func resolveMaxConcurrency(cmd *cobra.Command, parallel int) (int, error) {
 	return parallel, nil
 }
 
+// resolveRetryPolicies returns the retry policies of the operations converging
+// the project, configured by COMPOSE_RETRY_ATTEMPTS, COMPOSE_RETRY_BACKOFF and
+// COMPOSE_RETRY_ON. Retries are disabled unless COMPOSE_RETRY_ATTEMPTS is set.
+func resolveRetryPolicies() (map[compose.OperationType]compose.RetryPolicy, error) {
+	v, ok := os.LookupEnv(ComposeRetryAttempts)
+	if !ok {
+		return nil, nil
+	}
+	attempts, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer (found: %q)", ComposeRetryAttempts, v)
+	}
+	backoff := time.Second
+	if v, ok := os.LookupEnv(ComposeRetryBackoff); ok {
+		backoff, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a duration (found: %q)", ComposeRetryBackoff, v)
+		}
+	}
+	policies := compose.DefaultRetryPolicies(attempts, backoff)
+	if v, ok := os.LookupEnv(ComposeRetryOn); ok {
+		var classes []string
+		for class := range strings.SplitSeq(v, ",") {
+			classes = append(classes, strings.TrimSpace(class))
+		}
+		for op, policy := range policies {
+			policy.Retryable = classes
+			policies[op] = policy
+		}
+	}
+	return policies, nil
+}
+
 func stdinfo(dockerCli command.Cli) io.Writer {
 	if stdioToStdout {
 		return dockerCli.Out()
@@ -17,10 +17,17 @@
 package compose
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"go.uber.org/mock/gomock"
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/compose"
+	"github.com/docker/compose/v5/pkg/mocks"
 )
 
 func TestFilterServices(t *testing.T) {
@@ -53,3 +60,60 @@ func TestFilterServices(t *testing.T) {
 	_, err = p.GetService("zot")
 	assert.NilError(t, err)
 }
+
+func TestResolveRetryPolicies(t *testing.T) {
+	policies, err := resolveRetryPolicies()
+	assert.NilError(t, err)
+	assert.Assert(t, policies == nil)
+
+	t.Setenv(ComposeRetryAttempts, "5")
+	t.Setenv(ComposeRetryBackoff, "500ms")
+	policies, err = resolveRetryPolicies()
+	assert.NilError(t, err)
+	policy := policies[compose.OpRemoveVolume]
+	assert.Equal(t, policy.MaxAttempts, 5)
+	assert.Equal(t, policy.Backoff, 500*time.Millisecond)
+	assert.Assert(t, len(policy.Retryable) > 1)
+
+	t.Setenv(ComposeRetryOn, "busy, timeout")
+	policies, err = resolveRetryPolicies()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, policies[compose.OpCreateNetwork].Retryable, []string{compose.RetryOnBusy, compose.RetryOnTimeout})
+
+	t.Setenv(ComposeRetryAttempts, "many")
+	_, err = resolveRetryPolicies()
+	assert.ErrorContains(t, err, "COMPOSE_RETRY_ATTEMPTS must be an integer")
+}
+
+func TestToProjectCollectsIncludedFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		assert.NilError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	write("compose.yaml", `
+include:
+  - db/compose.yaml
+  - path: [api/compose.yaml, api/compose.override.yaml]
+services:
+  web:
+    image: nginx
+`)
+	write("db/compose.yaml", "services: {db: {image: postgres}}")
+	write("api/compose.yaml", "services: {api: {image: api}}")
+	write("api/compose.override.yaml", "services: {api: {image: api:dev}}")
+
+	ctrl := gomock.NewController(t)
+	cli := mocks.NewMockCli(ctrl)
+	backend, err := compose.NewComposeService(cli)
+	assert.NilError(t, err)
+	opts := &ProjectOptions{ConfigPaths: []string{filepath.Join(dir, "compose.yaml")}, Offline: true}
+
+	_, _, err = opts.ToProject(t.Context(), cli, backend, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, opts.IncludedFiles(), []string{
+		filepath.Join(dir, "db/compose.yaml"),
+		filepath.Join(dir, "api/compose.yaml"),
+		filepath.Join(dir, "api/compose.override.yaml"),
+	})
+}
@@ -50,6 +50,7 @@ type createOptions struct {
 	quietPull     bool
 	scale         []string
 	AssumeYes     bool
+	locked        bool
 }
 
 func createCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -78,6 +79,7 @@ func createCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.Build, "build", false, "Build images before starting containers")
 	flags.BoolVar(&opts.noBuild, "no-build", false, "Don't build an image, even if it's policy")
+	flags.BoolVar(&buildOpts.force, "force", false, "Build the images even if they were built from the same inputs")
 	flags.StringVar(&opts.Pull, "pull", "policy", `Pull image before running ("always"|"missing"|"never"|"build")`)
 	flags.BoolVar(&opts.quietPull, "quiet-pull", false, "Pull without printing progress information")
 	flags.BoolVar(&opts.forceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed")
@@ -85,6 +87,7 @@ func createCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Bac
 	flags.BoolVar(&opts.removeOrphans, "remove-orphans", false, "Remove containers for services not defined in the Compose file")
 	flags.StringArrayVar(&opts.scale, "scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
 	flags.BoolVarP(&opts.AssumeYes, "yes", "y", false, `Assume "yes" as answer to all prompts and run non-interactively`)
+	flags.BoolVar(&opts.locked, "locked", false, "Fail if an image is not locked by the lock file, or resolves to another digest")
 	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		// assumeYes was introduced by mistake as `--y`
 		if name == "y" {
@@ -101,6 +104,11 @@ func runCreate(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 		return err
 	}
 
+	project, err := applyImageLock(ctx, dockerCli, project, createOpts.locked)
+	if err != nil {
+		return err
+	}
+
 	var build *api.BuildOptions
 	if !createOpts.noBuild {
 		bo, err := buildOpts.toAPIBuildOptions(services)
@@ -128,6 +136,7 @@ func runCreate(ctx context.Context, dockerCli command.Cli, backendOptions *Backe
 		Inherit:              !createOpts.noInherit,
 		Timeout:              createOpts.GetTimeout(),
 		QuietPull:            createOpts.quietPull,
+		NoStart:              true,
 	})
 }
 
@@ -0,0 +1,84 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type driftOptions struct {
+	*ProjectOptions
+	Format string
+}
+
+func driftCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := driftOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "drift [OPTIONS] [SERVICE...]",
+		Short: "Report project resources which diverged from the Compose model",
+		RunE: p.WithServices(dockerCli, func(ctx context.Context, project *types.Project, services []string) error {
+			return runDrift(ctx, dockerCli, backendOptions, opts, project, services)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "Format the output. Values: [table | json]")
+	return cmd
+}
+
+func runDrift(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts driftOptions, project *types.Project, services []string) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	drifts, err := backend.Drift(ctx, project, api.DriftOptions{
+		Services: services,
+	})
+	if err != nil {
+		return err
+	}
+	if drifts == nil {
+		drifts = []api.Drift{}
+	}
+
+	err = formatter.Print(drifts, opts.Format, dockerCli.Out(),
+		func(w io.Writer) {
+			for _, drift := range drifts {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", drift.Resource, drift.Kind, drift.Details)
+			}
+		},
+		"RESOURCE", "KIND", "DETAILS")
+	if err != nil {
+		return err
+	}
+	if len(drifts) > 0 {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
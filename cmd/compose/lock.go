@@ -0,0 +1,120 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type lockOptions struct {
+	*ProjectOptions
+	check bool
+}
+
+func lockCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := lockOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "lock [OPTIONS]",
+		Short: "Pin the images of the project to their registry digest in a lock file",
+		Args:  cobra.NoArgs,
+		RunE: Adapt(func(ctx context.Context, args []string) error {
+			return runLock(ctx, dockerCli, backendOptions, opts)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.check, "check", false, "Check the lock file is up to date instead of writing it")
+	return cmd
+}
+
+func runLock(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts lockOptions) error {
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+
+	// lock the images of all the services, whatever the profiles enabled
+	opts.Profiles = []string{"*"}
+	project, _, err := opts.ToProject(ctx, dockerCli, backend, nil, cli.WithoutEnvironmentResolution)
+	if err != nil {
+		return err
+	}
+
+	resolve := compose.ImageDigestResolver(ctx, dockerCli.ConfigFile(), dockerCli.Client())
+	path := compose.LockFilePath(project)
+	if opts.check {
+		lock, err := compose.LoadImageLock(path)
+		if err != nil {
+			return err
+		}
+		return compose.VerifyImageLock(project, lock, resolve)
+	}
+
+	lock, err := compose.ResolveImageLock(project, resolve)
+	if err != nil {
+		return err
+	}
+	if err := lock.Write(path); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Err(), "%d image(s) locked in %s\n", len(lock.Images), path)
+	return nil
+}
+
+// applyImageLock pins the images of the project to the digest locked by its
+// lock file, if it has one. With locked, the lock file must exist and lock
+// the images to their current registry digest.
+func applyImageLock(ctx context.Context, dockerCli command.Cli, project *types.Project, locked bool) (*types.Project, error) {
+	path := compose.LockFilePath(project)
+	lock, err := compose.LoadImageLock(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if locked {
+			return nil, fmt.Errorf("--locked requires a lock file, run `docker compose lock` to create %s", path)
+		}
+		return project, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if locked {
+		err := compose.VerifyImageLock(project, lock, compose.ImageDigestResolver(ctx, dockerCli.ConfigFile(), dockerCli.Client()))
+		if err != nil {
+			return nil, fmt.Errorf("%s is outdated, run `docker compose lock` to update it: %w", path, err)
+		}
+	}
+	project, unlocked, err := compose.ApplyImageLock(project, lock)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range unlocked {
+		logrus.Warnf("image %s is not locked by %s, run `docker compose lock` to update it", image, path)
+	}
+	return project, nil
+}
@@ -0,0 +1,124 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose/v5/cmd/formatter"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/compose"
+)
+
+type planOptions struct {
+	*ProjectOptions
+	createOptions
+	Format            string
+	rollbackOnFailure bool
+	explain           bool
+}
+
+func planCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
+	opts := planOptions{
+		ProjectOptions: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "plan [OPTIONS] [SERVICE...]",
+		Short: "Show the operations `up` would perform to converge the project",
+		PreRunE: AdaptCmd(func(ctx context.Context, cmd *cobra.Command, args []string) error {
+			opts.timeChanged = cmd.Flags().Changed("timeout")
+			if opts.forceRecreate && opts.noRecreate {
+				return fmt.Errorf("--force-recreate and --no-recreate are incompatible")
+			}
+			if opts.recreateDeps && opts.noRecreate {
+				return fmt.Errorf("--always-recreate-deps and --no-recreate are incompatible")
+			}
+			return nil
+		}),
+		RunE: p.WithServices(dockerCli, func(ctx context.Context, project *types.Project, services []string) error {
+			return runPlan(ctx, dockerCli, backendOptions, opts, project, services)
+		}),
+		ValidArgsFunction: completeServiceNames(dockerCli, p),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Format, "format", "table", "Format the output. Values: [table | json]")
+	flags.BoolVar(&opts.forceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed")
+	flags.BoolVar(&opts.noRecreate, "no-recreate", false, "If containers already exist, don't recreate them. Incompatible with --force-recreate.")
+	flags.BoolVar(&opts.recreateDeps, "always-recreate-deps", false, "Recreate dependent containers. Incompatible with --no-recreate.")
+	flags.BoolVarP(&opts.noInherit, "renew-anon-volumes", "V", false, "Recreate anonymous volumes instead of retrieving data from the previous containers")
+	flags.BoolVar(&opts.removeOrphans, "remove-orphans", false, "Remove containers for services not defined in the Compose file")
+	flags.StringArrayVar(&opts.scale, "scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
+	flags.IntVarP(&opts.timeout, "timeout", "t", 0, "Use this timeout in seconds for container shutdown")
+	flags.BoolVar(&opts.rollbackOnFailure, "rollback-on-failure", false, "Plan the recreation of running containers as `up --rollback-on-failure` does")
+	flags.BoolVar(&opts.explain, "explain", false, "Report why containers are recreated or updated, field by field")
+	return cmd
+}
+
+func runPlan(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, opts planOptions, project *types.Project, services []string) error {
+	if err := opts.Apply(project); err != nil {
+		return err
+	}
+
+	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
+	if err != nil {
+		return err
+	}
+	plan, err := backend.Plan(ctx, project, api.PlanOptions{
+		Create: api.CreateOptions{
+			Services:             services,
+			RemoveOrphans:        opts.removeOrphans,
+			Recreate:             opts.recreateStrategy(),
+			RecreateDependencies: opts.dependenciesRecreateStrategy(),
+			Inherit:              !opts.noInherit,
+			Timeout:              opts.GetTimeout(),
+			RollbackOnFailure:    opts.rollbackOnFailure,
+			Explain:              opts.explain,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return printPlan(dockerCli.Out(), plan, opts.Format)
+}
+
+func printPlan(out io.Writer, plan *api.Plan, format string) error {
+	return formatter.Print(plan, format, out,
+		func(w io.Writer) {
+			for _, node := range plan.Nodes {
+				deps := make([]string, len(node.DependsOn))
+				for i, id := range node.DependsOn {
+					deps[i] = strconv.Itoa(id)
+				}
+				cause := node.Cause
+				if len(node.Divergences) > 0 {
+					cause += ": " + strings.Join(node.Divergences, ", ")
+				}
+				_, _ = fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%s\t%s\n",
+					node.ID, strings.Join(deps, ","), node.ResourceID, node.Operation, cause, node.Group)
+			}
+		},
+		"ID", "DEPENDS ON", "RESOURCE", "OPERATION", "CAUSE", "GROUP")
+}
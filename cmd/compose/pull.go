@@ -41,6 +41,7 @@ type pullOptions struct {
 	ignorePullFailures bool
 	noBuildable        bool
 	policy             string
+	locked             bool
 }
 
 func pullCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -74,6 +75,7 @@ func pullCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backe
 	cmd.Flags().BoolVar(&opts.ignorePullFailures, "ignore-pull-failures", false, "Pull what it can and ignores images with pull failures")
 	cmd.Flags().BoolVar(&opts.noBuildable, "ignore-buildable", false, "Ignore images that can be built")
 	cmd.Flags().StringVar(&opts.policy, "policy", "", `Apply pull policy ("missing"|"always")`)
+	cmd.Flags().BoolVar(&opts.locked, "locked", false, "Fail if an image is not locked by the lock file, or resolves to another digest")
 	return cmd
 }
 
@@ -114,6 +116,11 @@ func runPull(ctx context.Context, dockerCli command.Cli, backendOptions *Backend
 		return err
 	}
 
+	project, err = applyImageLock(ctx, dockerCli, project, opts.locked)
+	if err != nil {
+		return err
+	}
+
 	return backend.Pull(ctx, project, api.PullOptions{
 		Quiet:           opts.quiet,
 		IgnoreFailures:  opts.ignorePullFailures,
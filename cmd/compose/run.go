@@ -242,6 +242,7 @@ func runCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backen
 	flags.BoolVar(&options.quietPull, "quiet-pull", false, "Pull without printing progress information")
 	flags.BoolVar(&createOpts.Build, "build", false, "Build image before starting container")
 	flags.BoolVar(&options.removeOrphans, "remove-orphans", false, "Remove containers for services not defined in the Compose file")
+	flags.BoolVar(&createOpts.locked, "locked", false, "Fail if an image is not locked by the lock file, or resolves to another digest")
 
 	cmd.Flags().BoolVarP(&options.interactive, "interactive", "i", true, "Keep STDIN open even if not attached")
 	cmd.Flags().BoolVarP(&ttyFlag, "tty", "t", true, "Allocate a pseudo-TTY")
@@ -295,6 +296,11 @@ func runRun(ctx context.Context, backend api.Compose, project *types.Project, op
 		return err
 	}
 
+	project, err = applyImageLock(ctx, dockerCli, project, createOpts.locked)
+	if err != nil {
+		return err
+	}
+
 	if err := checksForRemoteStack(ctx, dockerCli, project, buildOpts, createOpts.AssumeYes, []string{}); err != nil {
 		return err
 	}
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/compose-spec/compose-go/v2/cli"
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	xprogress "github.com/moby/buildkit/util/progress/progressui"
@@ -36,6 +37,7 @@ import (
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/compose/v5/pkg/compose"
 	"github.com/docker/compose/v5/pkg/utils"
+	"github.com/docker/compose/v5/pkg/watch"
 )
 
 // composeOptions hold options common to `up` and `run` to run compose project
@@ -60,8 +62,16 @@ type upOptions struct {
 	wait                  bool
 	waitTimeout           int
 	watch                 bool
+	quietPeriod           time.Duration
+	rebuildInterval       time.Duration
+	images                bool
+	imagesInterval        time.Duration
 	navigationMenu        bool
 	navigationMenuChanged bool
+	planOut               string
+	rollbackOnFailure     bool
+	explain               bool
+	resume                bool
 }
 
 func (opts upOptions) apply(project *types.Project, services []string) (*types.Project, error) {
@@ -167,14 +177,24 @@ func upCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Backend
 	flags.BoolVarP(&create.noInherit, "renew-anon-volumes", "V", false, "Recreate anonymous volumes instead of retrieving data from the previous containers")
 	flags.BoolVar(&create.quietPull, "quiet-pull", false, "Pull without printing progress information")
 	flags.BoolVar(&build.quiet, "quiet-build", false, "Suppress the build output")
+	flags.BoolVar(&build.force, "force", false, "Build the images even if they were built from the same inputs")
 	flags.StringArrayVar(&up.attach, "attach", []string{}, "Restrict attaching to the specified services. Incompatible with --attach-dependencies.")
 	flags.StringArrayVar(&up.noAttach, "no-attach", []string{}, "Do not attach (stream logs) to the specified services")
 	flags.BoolVar(&up.attachDependencies, "attach-dependencies", false, "Automatically attach to log output of dependent services")
 	flags.BoolVar(&up.wait, "wait", false, "Wait for services to be running|healthy. Implies detached mode.")
 	flags.IntVar(&up.waitTimeout, "wait-timeout", 0, "Maximum duration in seconds to wait for the project to be running|healthy")
 	flags.BoolVarP(&up.watch, "watch", "w", false, "Watch source code and rebuild/refresh containers when files are updated.")
+	flags.DurationVar(&up.quietPeriod, "quiet-period", watch.QuietPeriod, "Time without file changes before applying the watch actions. Requires --watch.")
+	flags.DurationVar(&up.rebuildInterval, "rebuild-interval", 0, "Minimum time between two rebuilds of a service. Requires --watch.")
+	flags.BoolVar(&up.images, "images", false, "Pull the service images updated in the registry and recreate their containers. Requires --watch.")
+	flags.DurationVar(&up.imagesInterval, "images-interval", time.Minute, "Time between two checks of the registry for image updates. Requires --watch.")
 	flags.BoolVar(&up.navigationMenu, "menu", false, "Enable interactive shortcuts when running attached. Incompatible with --detach. Can also be enable/disable by setting COMPOSE_MENU environment var.")
 	flags.BoolVarP(&create.AssumeYes, "yes", "y", false, `Assume "yes" as answer to all prompts and run non-interactively`)
+	flags.StringVar(&up.planOut, "plan-out", "", "Save the plan to converge the project to a file for `compose apply`, instead of running it")
+	flags.BoolVar(&up.rollbackOnFailure, "rollback-on-failure", false, "Keep running containers being recreated until their replacement is healthy, and restore them if it fails")
+	flags.BoolVar(&up.explain, "explain", false, "Report why containers are recreated or updated, field by field, as --dry-run does")
+	flags.BoolVar(&up.resume, "resume", false, "Complete or revert the container recreations left half done by an interrupted `up` before converging the project")
+	flags.BoolVar(&create.locked, "locked", false, "Fail if an image is not locked by the lock file, or resolves to another digest")
 	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		// assumeYes was introduced by mistake as `--y`
 		if name == "y" {
@@ -224,6 +244,21 @@ func validateFlags(up *upOptions, create *createOptions) error {
 	if create.noBuild && up.watch {
 		return fmt.Errorf("--no-build and --watch are incompatible")
 	}
+	if up.quietPeriod < 0 || up.rebuildInterval < 0 {
+		return fmt.Errorf("--quiet-period and --rebuild-interval can't be negative")
+	}
+	if up.images && up.imagesInterval <= 0 {
+		return fmt.Errorf("--images-interval must be positive")
+	}
+	if up.rollbackOnFailure && up.noStart {
+		return fmt.Errorf("--rollback-on-failure and --no-start are incompatible")
+	}
+	if up.planOut != "" && (up.watch || up.noStart) {
+		return fmt.Errorf("--plan-out cannot be combined with --watch or --no-start")
+	}
+	if up.planOut != "" && up.resume {
+		return fmt.Errorf("--plan-out and --resume are incompatible")
+	}
 	return nil
 }
 
@@ -251,6 +286,11 @@ func runUp(
 		return err
 	}
 
+	project, err = applyImageLock(ctx, dockerCli, project, createOptions.locked)
+	if err != nil {
+		return err
+	}
+
 	var build *api.BuildOptions
 	if !createOptions.noBuild {
 		if createOptions.quietPull {
@@ -279,6 +319,10 @@ func runUp(
 		Inherit:              !createOptions.noInherit,
 		Timeout:              createOptions.GetTimeout(),
 		QuietPull:            createOptions.quietPull,
+		RollbackOnFailure:    upOptions.rollbackOnFailure,
+		Explain:              upOptions.explain,
+		Resume:               upOptions.resume,
+		NoStart:              upOptions.noStart,
 	}
 
 	if createOptions.AssumeYes {
@@ -290,6 +334,10 @@ func runUp(
 		return err
 	}
 
+	if upOptions.planOut != "" {
+		return savePlan(ctx, dockerCli, backend, project, create, upOptions.planOut)
+	}
+
 	if upOptions.noStart {
 		return backend.Create(ctx, project, create)
 	}
@@ -329,6 +377,30 @@ func runUp(
 		attach = attachSet.Elements()
 	}
 
+	var reload func(ctx context.Context) (*types.Project, error)
+	if upOptions.watch {
+		// load the project as the up command did when its configuration changes
+		reload = func(ctx context.Context) (*types.Project, error) {
+			project, _, err := buildOptions.ToProject(ctx, dockerCli, backend, services, cli.WithoutEnvironmentResolution)
+			if err != nil {
+				return nil, err
+			}
+			project, err = project.WithServicesEnvironmentResolved(true)
+			if err != nil {
+				return nil, err
+			}
+			if err := createOptions.Apply(project); err != nil {
+				return nil, err
+			}
+			return upOptions.apply(project, services)
+		}
+	}
+
+	var imagesInterval time.Duration
+	if upOptions.images {
+		imagesInterval = upOptions.imagesInterval
+	}
+
 	var timeout time.Duration
 	if upOptions.waitTimeout > 0 {
 		timeout = time.Duration(upOptions.waitTimeout) * time.Second
@@ -336,16 +408,51 @@ func runUp(
 	return backend.Up(ctx, project, api.UpOptions{
 		Create: create,
 		Start: api.StartOptions{
-			Project:        project,
-			Attach:         consumer,
-			AttachTo:       attach,
-			ExitCodeFrom:   upOptions.exitCodeFrom,
-			OnExit:         upOptions.OnExit(),
-			Wait:           upOptions.wait,
-			WaitTimeout:    timeout,
-			Watch:          upOptions.watch,
-			Services:       services,
-			NavigationMenu: upOptions.navigationMenu && display.Mode != display.ModePlain && dockerCli.In().IsTerminal(),
+			Project:              project,
+			Attach:               consumer,
+			AttachTo:             attach,
+			ExitCodeFrom:         upOptions.exitCodeFrom,
+			OnExit:               upOptions.OnExit(),
+			Wait:                 upOptions.wait,
+			WaitTimeout:          timeout,
+			Watch:                upOptions.watch,
+			ReloadProject:        reload,
+			IncludedFiles:        buildOptions.IncludedFiles,
+			WatchQuietPeriod:     upOptions.quietPeriod,
+			WatchRebuildInterval: upOptions.rebuildInterval,
+			WatchImagesInterval:  imagesInterval,
+			Services:             services,
+			NavigationMenu:       upOptions.navigationMenu && display.Mode != display.ModePlain && dockerCli.In().IsTerminal(),
 		},
 	})
 }
+
+// savePlan computes the plan `up` would execute and saves it to path, for a
+// later `compose apply` once reviewed.
+func savePlan(ctx context.Context, dockerCli command.Cli, backend api.Compose, project *types.Project, create api.CreateOptions, path string) error {
+	plan, err := backend.Plan(ctx, project, api.PlanOptions{
+		Create:       create,
+		EnsureImages: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := compose.SavePlan(f, plan); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := printPlan(dockerCli.Out(), plan, formatter.TABLE); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(dockerCli.Out(), "\nPlan saved to %s, run `docker compose apply %s` to apply it\n", path, path)
+	return err
+}
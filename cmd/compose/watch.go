@@ -18,7 +18,11 @@ package compose
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"sync"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/cli"
 	"github.com/compose-spec/compose-go/v2/types"
@@ -30,12 +34,18 @@ import (
 	"github.com/docker/compose/v5/internal/locker"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/compose/v5/pkg/compose"
+	"github.com/docker/compose/v5/pkg/watch"
 )
 
 type watchOptions struct {
 	*ProjectOptions
-	prune bool
-	noUp  bool
+	prune           bool
+	noUp            bool
+	format          string
+	quietPeriod     time.Duration
+	rebuildInterval time.Duration
+	images          bool
+	imagesInterval  time.Duration
 }
 
 func watchCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *BackendOptions) *cobra.Command {
@@ -63,28 +73,49 @@ func watchCommand(p *ProjectOptions, dockerCli command.Cli, backendOptions *Back
 	cmd.Flags().BoolVar(&buildOpts.quiet, "quiet", false, "hide build output")
 	cmd.Flags().BoolVar(&watchOpts.prune, "prune", true, "Prune dangling images on rebuild")
 	cmd.Flags().BoolVar(&watchOpts.noUp, "no-up", false, "Do not build & start services before watching")
+	cmd.Flags().StringVar(&watchOpts.format, "format", "text", "Format the watch events. Values: [text | json]")
+	cmd.Flags().DurationVar(&watchOpts.quietPeriod, "quiet-period", watch.QuietPeriod, "Time without file changes before applying the watch actions")
+	cmd.Flags().DurationVar(&watchOpts.rebuildInterval, "rebuild-interval", 0, "Minimum time between two rebuilds of a service")
+	cmd.Flags().BoolVar(&watchOpts.images, "images", false, "Pull the service images updated in the registry and recreate their containers")
+	cmd.Flags().DurationVar(&watchOpts.imagesInterval, "images-interval", time.Minute, "Time between two checks of the registry for image updates")
 	return cmd
 }
 
 func runWatch(ctx context.Context, dockerCli command.Cli, backendOptions *BackendOptions, watchOpts watchOptions, buildOpts buildOptions, services []string) error {
+	if watchOpts.format != "text" && watchOpts.format != "json" {
+		return fmt.Errorf("unsupported format %q, expected text or json", watchOpts.format)
+	}
+	if watchOpts.quietPeriod < 0 || watchOpts.rebuildInterval < 0 {
+		return fmt.Errorf("--quiet-period and --rebuild-interval can't be negative")
+	}
+	if watchOpts.images && watchOpts.imagesInterval <= 0 {
+		return fmt.Errorf("--images-interval must be positive")
+	}
 	backend, err := compose.NewComposeService(dockerCli, backendOptions.Options...)
 	if err != nil {
 		return err
 	}
 
-	project, _, err := watchOpts.ToProject(ctx, dockerCli, backend, services, cli.WithoutEnvironmentResolution)
-	if err != nil {
-		return err
-	}
+	loadProject := func(ctx context.Context) (*types.Project, error) {
+		project, _, err := watchOpts.ToProject(ctx, dockerCli, backend, services, cli.WithoutEnvironmentResolution)
+		if err != nil {
+			return nil, err
+		}
 
-	// resolve environment after the project has been reduced to selected services,
-	// so env_file declared by unrelated services doesn't need to exist
-	project, err = project.WithServicesEnvironmentResolved(true)
-	if err != nil {
-		return err
-	}
+		// resolve environment after the project has been reduced to selected services,
+		// so env_file declared by unrelated services doesn't need to exist
+		project, err = project.WithServicesEnvironmentResolved(true)
+		if err != nil {
+			return nil, err
+		}
 
-	if err := applyPlatforms(project, true); err != nil {
+		if err := applyPlatforms(project, true); err != nil {
+			return nil, err
+		}
+		return project, nil
+	}
+	project, err := loadProject(ctx)
+	if err != nil {
 		return err
 	}
 
@@ -130,11 +161,39 @@ func runWatch(ctx context.Context, dockerCli command.Cli, backendOptions *Backen
 		}
 	}
 
-	consumer := formatter.NewLogConsumer(ctx, dockerCli.Out(), dockerCli.Err(), false, false, false)
-	return backend.Watch(ctx, project, api.WatchOptions{
-		Build:    &build,
-		LogTo:    consumer,
-		Prune:    watchOpts.prune,
-		Services: services,
-	})
+	options := api.WatchOptions{
+		Build:           &build,
+		LogTo:           formatter.NewLogConsumer(ctx, dockerCli.Out(), dockerCli.Err(), false, false, false),
+		Prune:           watchOpts.prune,
+		Services:        services,
+		ReloadProject:   loadProject,
+		IncludedFiles:   watchOpts.IncludedFiles,
+		QuietPeriod:     watchOpts.quietPeriod,
+		RebuildInterval: watchOpts.rebuildInterval,
+	}
+	if watchOpts.images {
+		options.ImagesInterval = watchOpts.imagesInterval
+	}
+	if watchOpts.format == "json" {
+		// keep the standard output for the events stream
+		options.LogTo = formatter.NewLogConsumer(ctx, dockerCli.Err(), dockerCli.Err(), false, false, false)
+		options.OnEvent = watchEventsWriter(dockerCli.Out())
+	}
+	return backend.Watch(ctx, project, options)
+}
+
+// watchEventsWriter writes the watch events to out as a stream of json
+// objects.
+func watchEventsWriter(out io.Writer) func(event api.WatchEvent) {
+	var mu sync.Mutex
+	return func(event api.WatchEvent) {
+		marshal, err := json.Marshal(event)
+		if err != nil {
+			logrus.Debugf("cannot marshal watch event: %v", err)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintln(out, string(marshal))
+	}
 }
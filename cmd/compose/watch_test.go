@@ -0,0 +1,49 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestWatchEventsWriter(t *testing.T) {
+	var out bytes.Buffer
+	write := watchEventsWriter(&out)
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	write(api.WatchEvent{Time: now, Type: api.WatchEventBatch, Files: []string{"/src/main.go"}})
+	write(api.WatchEvent{Time: now, Type: api.WatchEventMatch, Services: []string{"web"}, Path: "/src", Action: "sync+restart", Files: []string{"/src/main.go"}})
+	write(api.WatchEvent{Time: now, Type: api.WatchEventAction, Services: []string{"web"}, Action: "sync", Files: []string{"/src/main.go"}, Duration: 42 * time.Millisecond})
+	write(api.WatchEvent{Time: now, Type: api.WatchEventAction, Services: []string{"web"}, Action: "restart", Duration: time.Second, Error: errors.New("no such container")})
+	write(api.WatchEvent{Time: now, Type: api.WatchEventBatchDone, Duration: 1042 * time.Millisecond, Error: errors.New("no such container")})
+
+	assert.Equal(t, out.String(), strings.TrimSpace(`
+{"time":"2026-01-02T15:04:05Z","type":"batch","files":["/src/main.go"]}
+{"time":"2026-01-02T15:04:05Z","type":"match","services":["web"],"path":"/src","action":"sync+restart","files":["/src/main.go"]}
+{"time":"2026-01-02T15:04:05Z","type":"action","services":["web"],"action":"sync","files":["/src/main.go"],"duration_ms":42,"status":"success"}
+{"time":"2026-01-02T15:04:05Z","type":"action","services":["web"],"action":"restart","duration_ms":1000,"status":"failure","error":"no such container"}
+{"time":"2026-01-02T15:04:05Z","type":"batch_done","duration_ms":1042,"status":"failure","error":"no such container"}
+`)+"\n")
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package locker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StateFile is a per-project file stored alongside the Pidfile, recording
+// state which must survive the process writing it.
+type StateFile struct {
+	path string
+}
+
+// NewStateFile returns the state file called name for the project. The file is
+// not created until it is written.
+func NewStateFile(projectName, name string) (*StateFile, error) {
+	run, err := runDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(run, fmt.Sprintf("%s.%s", projectName, name))
+	return &StateFile{path: path}, nil
+}
+
+// Read returns the file content, or nil if the file does not exist.
+func (f *StateFile) Read() ([]byte, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write replaces the file content atomically, so that an interrupted write
+// leaves the previous content.
+func (f *StateFile) Write(data []byte) error {
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// Remove deletes the file. Removing a file which does not exist is not an
+// error.
+func (f *StateFile) Remove() error {
+	err := os.Remove(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -151,6 +151,18 @@ func createAndPushManifest(ctx context.Context, resolver remotes.Resolver, named
 	return descriptor, nil
 }
 
+// ComposeManifest generates the OCI 1.1 image manifest of a compose project
+// artifact made of layers, for storage outside a registry. It returns the
+// manifest descriptor, and the blobs the artifact is made of, the manifest
+// included.
+func ComposeManifest(layers []v1.Descriptor) (v1.Descriptor, []v1.Descriptor, error) {
+	descriptor, blobs, err := generateManifest(layers, api.OCIVersion1_1)
+	if err != nil {
+		return v1.Descriptor{}, nil, err
+	}
+	return descriptor, append(blobs, layers...), nil
+}
+
 func isNonAuthClientError(statusCode int) bool {
 	if statusCode < 400 || statusCode >= 500 {
 		// not a client error
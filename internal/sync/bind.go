@@ -0,0 +1,31 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+)
+
+// Bind is a Syncer for the services bind mounting the synced paths: their
+// containers already see the host files, nothing is transferred.
+type Bind struct{}
+
+var _ Syncer = Bind{}
+
+func (Bind) Sync(context.Context, string, []*PathMapping) error {
+	return nil
+}
@@ -0,0 +1,382 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// ManifestClient is a LowLevelClient which can also capture the output of a
+// command run in a container.
+type ManifestClient interface {
+	LowLevelClient
+
+	// ExecOutput runs cmd in the container and returns its standard output
+	ExecOutput(ctx context.Context, containerID string, cmd []string) ([]byte, error)
+}
+
+// Manifest is a Syncer which only transfers the files whose content,
+// permissions or owner differ from the copy in the container, the permissions
+// and owner being those the Permissions of their PathMapping give them. It
+// keeps a manifest of the files hashes, permissions and owner per container, seeded by hashing the synced
+// paths inside the container the first time they are synced to it, then
+// updated with every transfer. Containers lacking the find, sha256sum and stat
+// commands get all the files once, as with Tar.
+type Manifest struct {
+	client ManifestClient
+	tar    *Tar
+
+	mu        sync.Mutex
+	manifests map[string]*containerManifest // container ID → manifest
+}
+
+var _ Syncer = &Manifest{}
+
+// containerManifest holds the state of the files known to be in a container.
+type containerManifest struct {
+	mu sync.Mutex
+	// seeded lists the paths whose files were hashed in the container
+	seeded []string
+	// files holds the state of the files, by path in the container
+	files map[string]fileState
+}
+
+// fileState is what tells two copies of a file apart.
+type fileState struct {
+	// hash is the sha256 of the content
+	hash string
+	// mode holds the permissions in octal, "" if unknown
+	mode string
+	// owner holds the "uid:gid" owner, "" if unknown or, for a file to sync,
+	// left to the container
+	owner string
+}
+
+// matches tells whether a file to sync is up to date with the known state of
+// its copy in a container.
+func (f fileState) matches(known fileState) bool {
+	return f.hash == known.hash && f.mode == known.mode && (f.owner == "" || f.owner == known.owner)
+}
+
+// localFile is a regular file to sync, with its state.
+type localFile struct {
+	mapping PathMapping
+	state   fileState
+}
+
+func NewManifest(projectName string, client ManifestClient) *Manifest {
+	return &Manifest{
+		client:    client,
+		tar:       NewTar(projectName, client),
+		manifests: map[string]*containerManifest{},
+	}
+}
+
+func (m *Manifest) Sync(ctx context.Context, service string, paths []*PathMapping) error {
+	containers, err := m.client.ContainersForService(ctx, m.tar.projectName, service)
+	if err != nil {
+		return err
+	}
+
+	var (
+		files         []localFile
+		others        []PathMapping // symlinks and empty directories, always copied
+		roots         []string      // container paths of the synced files
+		pathsToDelete []string
+	)
+	for _, p := range paths {
+		if _, err := os.Lstat(p.HostPath); errors.Is(err, fs.ErrNotExist) {
+			pathsToDelete = append(pathsToDelete, p.ContainerPath)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("stat %q: %w", p.HostPath, err)
+		}
+		f, o, err := localFiles(*p)
+		if err != nil {
+			return err
+		}
+		files = append(files, f...)
+		others = append(others, o...)
+		if len(f) == 0 {
+			continue
+		}
+		root := path.Clean(p.ContainerPath)
+		if len(f) == 1 && f[0].mapping.HostPath == p.HostPath {
+			// a single file, possibly copied into a container directory
+			root = f[0].mapping.ContainerPath
+		}
+		roots = append(roots, root)
+	}
+
+	var (
+		eg    errgroup.Group
+		errMu sync.Mutex
+		errs  []error
+	)
+	eg.SetLimit(16)
+	for _, ctr := range containers {
+		manifest := m.manifest(ctr.ID)
+		eg.Go(func() error {
+			if err := m.syncContainer(ctx, ctr.ID, manifest, files, others, roots, pathsToDelete); err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+			}
+			return nil // don't fail-fast; collect all errors
+		})
+	}
+	_ = eg.Wait()
+	m.prune(containers)
+	return errors.Join(errs...)
+}
+
+// syncContainer transfers to a container the files whose hash differs from
+// its manifest, and deletes pathsToDelete.
+func (m *Manifest) syncContainer(ctx context.Context, containerID string, manifest *containerManifest, files []localFile, others []PathMapping, roots, pathsToDelete []string) error {
+	manifest.mu.Lock()
+	defer manifest.mu.Unlock()
+
+	manifest.seed(ctx, m.client, containerID, roots)
+
+	pathsToCopy := slices.Clone(others)
+	var copied []localFile
+	for _, f := range files {
+		if f.state.matches(manifest.files[f.mapping.ContainerPath]) {
+			continue
+		}
+		pathsToCopy = append(pathsToCopy, f.mapping)
+		copied = append(copied, f)
+	}
+	logrus.Debugf("syncing %d files to %s, %d unchanged", len(pathsToCopy), containerID, len(files)-len(copied))
+
+	if err := m.tar.syncContainer(ctx, containerID, pathsToCopy, pathsToDelete); err != nil {
+		// the container state is unknown, hash the directories again next time
+		manifest.seeded = nil
+		manifest.files = map[string]fileState{}
+		return err
+	}
+	for _, p := range pathsToDelete {
+		for name := range manifest.files {
+			if isChildPath(p, name) {
+				delete(manifest.files, name)
+			}
+		}
+	}
+	for _, f := range copied {
+		manifest.files[f.mapping.ContainerPath] = f.state
+	}
+	return nil
+}
+
+// manifest returns the manifest of a container, an empty one the first time.
+func (m *Manifest) manifest(containerID string) *containerManifest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	manifest, ok := m.manifests[containerID]
+	if !ok {
+		manifest = &containerManifest{files: map[string]fileState{}}
+		m.manifests[containerID] = manifest
+	}
+	return manifest
+}
+
+// prune forgets the manifests of the containers which are gone, typically
+// recreated.
+func (m *Manifest) prune(containers []container.Summary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id := range m.manifests {
+		if !slices.ContainsFunc(containers, func(c container.Summary) bool { return c.ID == id }) {
+			delete(m.manifests, id)
+		}
+	}
+}
+
+// seed hashes the files the synced paths hold in the container, unless they
+// were already. Only those paths are hashed: a rule target may be a large
+// directory of the container, of which a few files are synced. A container
+// which can't hash its files gets them all transferred.
+func (manifest *containerManifest) seed(ctx context.Context, client ManifestClient, containerID string, roots []string) {
+	var paths []string
+	for _, root := range roots {
+		if root == "/" {
+			// never hash the whole container filesystem
+			logrus.Debugf("not hashing files in %s: / is synced", containerID)
+			continue
+		}
+		if slices.ContainsFunc(manifest.seeded, func(seeded string) bool { return isChildPath(seeded, root) }) ||
+			slices.ContainsFunc(paths, func(p string) bool { return isChildPath(p, root) }) {
+			continue
+		}
+		paths = append(slices.DeleteFunc(paths, func(p string) bool { return isChildPath(root, p) }), root)
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	manifest.seeded = append(manifest.seeded, paths...)
+	cmd := append(append([]string{"find"}, paths...), "-type", "f",
+		"-exec", "sha256sum", "{}", "+",
+		"-exec", "stat", "-c", "%a %u:%g %n", "{}", "+")
+	out, err := client.ExecOutput(ctx, containerID, cmd)
+	if err != nil {
+		// paths which don't exist yet make find fail, the files it found are
+		// still hashed
+		logrus.Debugf("cannot hash all files in %s:%s, syncing the others: %v", containerID, strings.Join(paths, ","), err)
+	}
+	maps.Copy(manifest.files, parseFileStates(out))
+}
+
+// parseFileStates parses the output of sha256sum and of stat printing the
+// permissions, owner and name of the files, skipping the lines of files whose
+// name sha256sum had to escape.
+func parseFileStates(out []byte) map[string]fileState {
+	states := map[string]fileState{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if hash, name, ok := strings.Cut(line, "  "); ok && len(hash) == sha256.Size*2 && !strings.HasPrefix(hash, "\\") {
+			state := states[name]
+			state.hash = hash
+			states[name] = state
+			continue
+		}
+		mode, rest, _ := strings.Cut(line, " ")
+		owner, name, ok := strings.Cut(rest, " ")
+		if !ok {
+			continue
+		}
+		perm, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			continue
+		}
+		state := states[name]
+		state.mode = formatMode(fs.FileMode(perm))
+		state.owner = owner
+		states[name] = state
+	}
+	return states
+}
+
+// formatMode formats the permissions of a file as stat does.
+func formatMode(mode fs.FileMode) string {
+	return strconv.FormatUint(uint64(mode.Perm()), 8)
+}
+
+// localFiles lists the regular files of a path mapping with their hash, and
+// the symlinks and empty directories which are copied as is.
+func localFiles(p PathMapping) ([]localFile, []PathMapping, error) {
+	var (
+		files  []localFile
+		others []PathMapping
+	)
+	root := p.HostPath
+	err := filepath.WalkDir(root, func(hostPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return fmt.Errorf("walking %q: %w", hostPath, err)
+		}
+		containerPath := p.ContainerPath
+		if hostPath != root {
+			rel, err := filepath.Rel(root, hostPath)
+			if err != nil {
+				return err
+			}
+			containerPath = path.Join(containerPath, filepath.ToSlash(rel))
+		} else if !entry.IsDir() && strings.HasSuffix(containerPath, "/") {
+			containerPath += filepath.Base(hostPath)
+		}
+		mapping := PathMapping{HostPath: hostPath, ContainerPath: path.Clean(containerPath), Permissions: p.Permissions}
+
+		switch {
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			hash, err := hashFile(hostPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			files = append(files, localFile{mapping: mapping, state: fileState{
+				hash:  hash,
+				mode:  formatMode(p.Permissions.fileMode(info.Mode())),
+				owner: p.Permissions.owner(),
+			}})
+		case entry.IsDir():
+			children, err := os.ReadDir(hostPath)
+			if err != nil {
+				return err
+			}
+			if len(children) == 0 {
+				others = append(others, mapping)
+			}
+		default:
+			others = append(others, mapping)
+		}
+		return nil
+	})
+	return files, others, err
+}
+
+func hashFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isChildPath tells whether name is dir or one of its descendants, both
+// being container paths.
+func isChildPath(dir, name string) bool {
+	dir, name = path.Clean(dir), path.Clean(name)
+	return dir == name || dir == "/" || strings.HasPrefix(name, dir+"/")
+}
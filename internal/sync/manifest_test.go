@@ -0,0 +1,253 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/moby/moby/api/types/container"
+	"gotest.tools/v3/assert"
+)
+
+// fakeManifestClient answers ExecOutput with the checksums and permissions of
+// the files in the container.
+type fakeManifestClient struct {
+	fakeLowLevelClient
+	checksums string
+	hashErr   error
+	hashCmds  [][]string
+}
+
+func (f *fakeManifestClient) ExecOutput(_ context.Context, _ string, cmd []string) ([]byte, error) {
+	f.hashCmds = append(f.hashCmds, cmd)
+	return []byte(f.checksums), f.hashErr
+}
+
+// copied lists the files the last Untar call received.
+func (f *fakeManifestClient) copied() []string {
+	if len(f.untarHeaders) == 0 {
+		return nil
+	}
+	var names []string
+	for name := range f.untarHeaders[len(f.untarHeaders)-1] {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// hashCmd is the command hashing the files of paths in the container.
+func hashCmd(paths ...string) []string {
+	return append(append([]string{"find"}, paths...), "-type", "f",
+		"-exec", "sha256sum", "{}", "+",
+		"-exec", "stat", "-c", "%a %u:%g %n", "{}", "+")
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		assert.NilError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestManifestSync(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.go":     "package main",
+		"lib/util.go": "package lib",
+		"README.md":   "# readme",
+	})
+	client := &fakeManifestClient{
+		fakeLowLevelClient: fakeLowLevelClient{containers: []container.Summary{{ID: "ctr1"}}},
+		checksums: sha256Hex("package main") + "  /app/main.go\n" +
+			sha256Hex("outdated") + "  /app/lib/util.go\n" +
+			"644 0:0 /app/main.go\n" +
+			"644 0:0 /app/lib/util.go\n",
+	}
+	syncer := NewManifest("proj", client)
+
+	err := syncer.Sync(t.Context(), "svc", []*PathMapping{{HostPath: dir, ContainerPath: "/app"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, client.hashCmds, [][]string{hashCmd("/app")})
+	assert.DeepEqual(t, client.copied(), []string{"app/README.md", "app/lib/util.go"})
+
+	// the manifest is up to date: only the modified file is copied, without
+	// hashing the container files again
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // changed"), 0o644))
+	err = syncer.Sync(t.Context(), "svc", []*PathMapping{
+		{HostPath: filepath.Join(dir, "main.go"), ContainerPath: "/app/main.go"},
+		{HostPath: filepath.Join(dir, "lib/util.go"), ContainerPath: "/app/lib/util.go"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(client.hashCmds), 1)
+	assert.DeepEqual(t, client.copied(), []string{"app/main.go"})
+	assert.Equal(t, client.untarCount, 2)
+
+	// nothing changed, nothing to copy
+	err = syncer.Sync(t.Context(), "svc", []*PathMapping{{HostPath: dir, ContainerPath: "/app"}})
+	assert.NilError(t, err)
+	assert.Equal(t, client.untarCount, 2)
+
+	// a file whose permissions changed is copied
+	assert.NilError(t, os.Chmod(filepath.Join(dir, "README.md"), 0o600))
+	err = syncer.Sync(t.Context(), "svc", []*PathMapping{{HostPath: dir, ContainerPath: "/app"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, client.copied(), []string{"app/README.md"})
+	assert.Equal(t, client.untarCount, 3)
+
+	// deleted files are removed from the container and the manifest
+	assert.NilError(t, os.RemoveAll(filepath.Join(dir, "lib")))
+	err = syncer.Sync(t.Context(), "svc", []*PathMapping{{HostPath: filepath.Join(dir, "lib"), ContainerPath: "/app/lib"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, client.execCmds, [][]string{{"rm", "-rf", "/app/lib"}})
+	_, known := syncer.manifests["ctr1"].files["/app/lib/util.go"]
+	assert.Assert(t, !known)
+}
+
+func TestManifestSyncWithoutChecksums(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"main.go": "package main"})
+	client := &fakeManifestClient{
+		fakeLowLevelClient: fakeLowLevelClient{containers: []container.Summary{{ID: "ctr1"}}},
+		hashErr:            errors.New("exec: \"sha256sum\": executable file not found in $PATH"),
+	}
+	syncer := NewManifest("proj", client)
+	mapping := []*PathMapping{{HostPath: dir, ContainerPath: "/app"}}
+
+	// all files are copied once, then recorded in the manifest
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping))
+	assert.DeepEqual(t, client.copied(), []string{"app/main.go"})
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping))
+	assert.Equal(t, client.untarCount, 1)
+	assert.Equal(t, len(client.hashCmds), 1)
+}
+
+func TestManifestSyncCopyFailure(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"main.go": "package main"})
+	client := &fakeManifestClient{
+		fakeLowLevelClient: fakeLowLevelClient{
+			containers: []container.Summary{{ID: "ctr1"}},
+			untarErrs:  []error{errors.New("no space left on device")},
+		},
+	}
+	syncer := NewManifest("proj", client)
+	mapping := []*PathMapping{{HostPath: dir, ContainerPath: "/app"}}
+
+	assert.ErrorContains(t, syncer.Sync(t.Context(), "svc", mapping), "no space left on device")
+	// the container state is unknown: its files are hashed again
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping))
+	assert.Equal(t, client.untarCount, 2)
+	assert.Equal(t, len(client.hashCmds), 2)
+}
+
+func TestManifestSyncForgetsRemovedContainers(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"main.go": "package main"})
+	client := &fakeManifestClient{
+		fakeLowLevelClient: fakeLowLevelClient{containers: []container.Summary{{ID: "ctr1"}}},
+	}
+	syncer := NewManifest("proj", client)
+	mapping := []*PathMapping{{HostPath: dir, ContainerPath: "/app"}}
+
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping))
+	client.containers = []container.Summary{{ID: "ctr2"}}
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping))
+	assert.Equal(t, client.untarCount, 2)
+	_, known := syncer.manifests["ctr1"]
+	assert.Assert(t, !known)
+}
+
+func TestManifestSyncHashesSyncedPaths(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"src/main.go": "package main",
+		"config.yaml": "debug: true",
+	})
+	client := &fakeManifestClient{
+		fakeLowLevelClient: fakeLowLevelClient{containers: []container.Summary{{ID: "ctr1"}}},
+	}
+	syncer := NewManifest("proj", client)
+
+	// each synced path is hashed, rather than their common parent
+	err := syncer.Sync(t.Context(), "svc", []*PathMapping{
+		{HostPath: filepath.Join(dir, "src"), ContainerPath: "/app/src"},
+		{HostPath: filepath.Join(dir, "config.yaml"), ContainerPath: "/etc/app/"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, client.hashCmds, [][]string{
+		hashCmd("/app/src", "/etc/app/config.yaml"),
+	})
+
+	// only the paths which were not seeded yet are hashed
+	err = syncer.Sync(t.Context(), "svc", []*PathMapping{
+		{HostPath: filepath.Join(dir, "src", "main.go"), ContainerPath: "/app/src/main.go"},
+		{HostPath: dir, ContainerPath: "/srv"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, client.hashCmds[1], hashCmd("/srv"))
+}
+
+func TestParseFileStates(t *testing.T) {
+	hash := sha256Hex("data")
+	states := parseFileStates([]byte(hash + "  /app/a b.txt\n" +
+		"\\" + hash + "  /app/new\\nline\n" +
+		"find: /app/private: Permission denied\n" +
+		"4755 0:0 /app/a b.txt\n" +
+		"640 1000:1000 /app/unhashed\n"))
+	assert.DeepEqual(t, states, map[string]fileState{
+		"/app/a b.txt":  {hash: hash, mode: "755", owner: "0:0"},
+		"/app/unhashed": {mode: "640", owner: "1000:1000"},
+	}, cmp.AllowUnexported(fileState{}))
+}
+
+func TestManifestSyncPermissions(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"main.go": "package main"})
+	client := &fakeManifestClient{
+		fakeLowLevelClient: fakeLowLevelClient{containers: []container.Summary{{ID: "ctr1"}}},
+		checksums:          sha256Hex("package main") + "  /app/main.go\n" + "644 0:0 /app/main.go\n",
+	}
+	syncer := NewManifest("proj", client)
+	mapping := func(permissions *Permissions) []*PathMapping {
+		return []*PathMapping{{HostPath: dir, ContainerPath: "/app", Permissions: permissions}}
+	}
+
+	// the owner left to the container doesn't matter
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping(nil)))
+	assert.Equal(t, client.untarCount, 0)
+
+	// the files are copied again when the permissions or owner they get change
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping(&Permissions{FileMode: 0o600})))
+	assert.DeepEqual(t, client.copied(), []string{"app/main.go"})
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping(&Permissions{FileMode: 0o600})))
+	assert.Equal(t, client.untarCount, 1)
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping(&Permissions{FileMode: 0o600, SetOwner: true, UID: 1000, GID: 1000})))
+	assert.Equal(t, client.untarCount, 2)
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", mapping(&Permissions{FileMode: 0o600, SetOwner: true, UID: 1000, GID: 1000})))
+	assert.Equal(t, client.untarCount, 2)
+}
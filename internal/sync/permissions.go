@@ -0,0 +1,89 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"archive/tar"
+	"fmt"
+	"io/fs"
+)
+
+// Permissions overrides the owner and permissions the synced files get in
+// the containers.
+type Permissions struct {
+	// SetOwner makes UID and GID own the files and directories
+	SetOwner bool
+	UID      int
+	GID      int
+	// FileMode sets the read and write permissions of the regular files if
+	// not zero, their execute permissions follow the host file: granted
+	// wherever FileMode grants read if it is executable
+	FileMode fs.FileMode
+	// DirMode sets the permissions of the directories if not zero
+	DirMode fs.FileMode
+	// StripExec removes the execute permissions of the regular files
+	StripExec bool
+}
+
+// Apply overrides the owner and permissions of a tar entry, nothing is
+// changed if p is nil.
+func (p *Permissions) Apply(header *tar.Header) {
+	if p == nil {
+		return
+	}
+	if p.SetOwner {
+		// the names would take precedence over the ids when extracted
+		header.Uid, header.Gid = p.UID, p.GID
+		header.Uname, header.Gname = "", ""
+	}
+
+	perm := header.Mode & 0o777
+	switch header.Typeflag {
+	case tar.TypeReg:
+		executable := perm&0o111 != 0
+		if p.FileMode != 0 {
+			perm = int64(p.FileMode.Perm()) &^ 0o111
+			if executable {
+				perm |= (perm & 0o444) >> 2
+			}
+		}
+		if p.StripExec {
+			perm &^= 0o111
+		}
+	case tar.TypeDir:
+		if p.DirMode != 0 {
+			perm = int64(p.DirMode.Perm())
+		}
+	}
+	header.Mode = header.Mode&^0o777 | perm
+}
+
+// fileMode returns the permissions a regular file with the given mode gets.
+func (p *Permissions) fileMode(mode fs.FileMode) fs.FileMode {
+	header := &tar.Header{Typeflag: tar.TypeReg, Mode: int64(mode.Perm())}
+	p.Apply(header)
+	return fs.FileMode(header.Mode).Perm()
+}
+
+// owner returns the "uid:gid" owner the files get, "" if it is left to the
+// containers.
+func (p *Permissions) owner() string {
+	if p == nil || !p.SetOwner {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", p.UID, p.GID)
+}
@@ -0,0 +1,94 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// archiveHeaders archives paths and returns the headers, by entry name.
+func archiveHeaders(t *testing.T, paths []PathMapping) map[string]tar.Header {
+	t.Helper()
+	var buf bytes.Buffer
+	ab := NewArchiveBuilder(&buf)
+	assert.NilError(t, ab.ArchivePathsIfExist(paths, keepImpliedDirectories))
+	assert.NilError(t, ab.Close())
+	headers := map[string]tar.Header{}
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return headers
+		}
+		assert.NilError(t, err)
+		headers[header.Name] = *header
+	}
+}
+
+func TestArchivePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("files have no execute permission on Windows")
+	}
+	dir := writeFiles(t, map[string]string{
+		"bin/run.sh":  "#!/bin/sh",
+		"config.yaml": "debug: true",
+	})
+	assert.NilError(t, os.Chmod(filepath.Join(dir, "bin", "run.sh"), 0o700))
+	assert.NilError(t, os.Chmod(filepath.Join(dir, "config.yaml"), 0o600))
+	assert.NilError(t, os.Chmod(filepath.Join(dir, "bin"), 0o700))
+
+	mode := func(headers map[string]tar.Header, name string) int64 {
+		t.Helper()
+		header, ok := headers[name]
+		assert.Assert(t, ok, name)
+		return header.Mode & 0o777
+	}
+
+	// host attributes are kept by default
+	headers := archiveHeaders(t, []PathMapping{{HostPath: dir, ContainerPath: "/app"}})
+	assert.Equal(t, mode(headers, "app/bin/run.sh"), int64(0o700))
+	assert.Equal(t, mode(headers, "app/config.yaml"), int64(0o600))
+
+	headers = archiveHeaders(t, []PathMapping{{HostPath: dir, ContainerPath: "/app", Permissions: &Permissions{
+		SetOwner: true,
+		UID:      1000,
+		GID:      1001,
+		FileMode: 0o644,
+		DirMode:  0o755,
+	}}})
+	for name, header := range headers {
+		assert.Equal(t, header.Uid, 1000, name)
+		assert.Equal(t, header.Gid, 1001, name)
+		assert.Equal(t, header.Uname, "", name)
+	}
+	assert.Equal(t, mode(headers, "app/bin/run.sh"), int64(0o755), "executable files stay executable")
+	assert.Equal(t, mode(headers, "app/config.yaml"), int64(0o644))
+	assert.Equal(t, mode(headers, "app/bin/"), int64(0o755))
+
+	headers = archiveHeaders(t, []PathMapping{{HostPath: dir, ContainerPath: "/app", Permissions: &Permissions{StripExec: true}}})
+	assert.Equal(t, mode(headers, "app/bin/run.sh"), int64(0o600))
+	assert.Equal(t, mode(headers, "app/bin/"), int64(0o700), "directories keep their execute permissions")
+}
@@ -33,6 +33,9 @@ type PathMapping struct {
 	//	- /workdir/main.go
 	//  - /workdir/subdir
 	ContainerPath string
+	// Permissions overrides the owner and permissions of the copied files,
+	// which keep the host ones if nil.
+	Permissions *Permissions
 }
 
 type Syncer interface {
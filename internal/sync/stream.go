@@ -0,0 +1,308 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// StreamClient is a LowLevelClient which can also run a long-lived command in
+// a container and talk to it over its standard input and output.
+type StreamClient interface {
+	LowLevelClient
+
+	// ExecStream starts cmd as root in the container and returns its standard
+	// input and output. Closing the standard input ends the command, closing
+	// its output releases the connection.
+	ExecStream(ctx context.Context, containerID string, cmd []string) (io.WriteCloser, io.ReadCloser, error)
+}
+
+// streamHelper runs in the containers to apply the operations streamed to
+// its standard input: "d <size>" followed by size bytes of NUL separated paths
+// to delete, or "x" followed by a tar archive to extract at the root, sent in
+// "<size>" prefixed chunks up to a "0" line. Whatever tar leaves of the
+// archive is drained so the next operation is read in sync. Each operation is
+// answered by "ok" or "error <status>". The helper first answers "ready", once
+// it checked the commands it relies on are available.
+const streamHelper = `for c in head tar xargs rm cat; do
+  command -v "$c" >/dev/null || exit 127
+done
+echo ready
+while read -r op size; do
+  case "$op" in
+    x) while read -r n && [ "$n" -gt 0 ]; do head -c "$n"; done |
+       (tar -x -C / >&2; status=$?; cat >/dev/null; exit $status) ;;
+    d) head -c "$size" | xargs -0 rm -rf -- >&2 ;;
+    *) false ;;
+  esac
+  status=$?
+  if [ "$status" -eq 0 ]; then echo ok; else echo "error $status"; fi
+done`
+
+// ExecStream is a Syncer which keeps a helper process running in each
+// container and streams the files to copy and the paths to delete to it,
+// saving the creation of an exec and a copy request per batch of changes.
+// The helper runs as root, as the engine extracts the archives Tar copies.
+// An operation the helper fails or doesn't answer within streamReplyTimeout
+// is applied again as Tar does, and a new helper is started for the next
+// batch. Containers lacking sh, head, tar, xargs or cat are synced as with
+// Tar.
+type ExecStream struct {
+	client StreamClient
+	tar    *Tar
+
+	mu       sync.Mutex
+	sessions map[string]*streamSession // container ID → helper
+}
+
+var _ Syncer = &ExecStream{}
+
+// streamSession is the helper process running in a container.
+type streamSession struct {
+	mu          sync.Mutex
+	unsupported bool
+	stdin       io.WriteCloser
+	stdout      io.ReadCloser
+	replies     *bufio.Reader
+}
+
+func NewExecStream(projectName string, client StreamClient) *ExecStream {
+	return &ExecStream{
+		client:   client,
+		tar:      NewTar(projectName, client),
+		sessions: map[string]*streamSession{},
+	}
+}
+
+func (e *ExecStream) Sync(ctx context.Context, service string, paths []*PathMapping) error {
+	containers, err := e.client.ContainersForService(ctx, e.tar.projectName, service)
+	if err != nil {
+		return err
+	}
+	pathsToCopy, pathsToDelete, err := splitPaths(paths)
+	if err != nil {
+		return err
+	}
+
+	var (
+		eg    errgroup.Group
+		errMu sync.Mutex
+		errs  []error
+	)
+	eg.SetLimit(16)
+	for _, ctr := range containers {
+		session := e.session(ctr.ID)
+		eg.Go(func() error {
+			if err := e.syncContainer(ctx, ctr.ID, session, pathsToCopy, pathsToDelete); err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+			}
+			return nil // don't fail-fast; collect all errors
+		})
+	}
+	_ = eg.Wait()
+	e.prune(containers)
+	return errors.Join(errs...)
+}
+
+// syncContainer streams the operations to the helper of a container, falling
+// back to Tar when the container can't run it or it fails.
+func (e *ExecStream) syncContainer(ctx context.Context, containerID string, session *streamSession, pathsToCopy []PathMapping, pathsToDelete []string) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.stdin == nil && !session.unsupported {
+		if err := session.start(ctx, e.client, containerID); err != nil {
+			logrus.Debugf("cannot start sync helper in %s, syncing with tar: %v", containerID, err)
+			session.unsupported = true
+		}
+	}
+	if session.unsupported {
+		return e.tar.syncContainer(ctx, containerID, pathsToCopy, pathsToDelete)
+	}
+
+	err := session.apply(pathsToCopy, pathsToDelete)
+	if err == nil {
+		return nil
+	}
+	// the helper may have stopped reading mid-operation, start a new one next time
+	logrus.Debugf("sync helper in %s failed, syncing with tar: %v", containerID, err)
+	session.close()
+	return e.tar.syncContainer(ctx, containerID, pathsToCopy, pathsToDelete)
+}
+
+// session returns the helper of a container, not started the first time.
+func (e *ExecStream) session(containerID string) *streamSession {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	session, ok := e.sessions[containerID]
+	if !ok {
+		session = &streamSession{}
+		e.sessions[containerID] = session
+	}
+	return session
+}
+
+// prune stops the helpers of the containers which are gone, typically
+// recreated.
+func (e *ExecStream) prune(containers []container.Summary) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, session := range e.sessions {
+		if !slices.ContainsFunc(containers, func(c container.Summary) bool { return c.ID == id }) {
+			delete(e.sessions, id)
+			go func() {
+				session.mu.Lock()
+				defer session.mu.Unlock()
+				session.close()
+			}()
+		}
+	}
+}
+
+func (session *streamSession) start(ctx context.Context, client StreamClient, containerID string) error {
+	stdin, stdout, err := client.ExecStream(ctx, containerID, []string{"sh", "-c", streamHelper})
+	if err != nil {
+		return err
+	}
+	session.stdin, session.stdout = stdin, stdout
+	session.replies = bufio.NewReader(stdout)
+	if err := session.reply("ready"); err != nil {
+		session.close()
+		return err
+	}
+	return nil
+}
+
+// apply deletes pathsToDelete then copies pathsToCopy.
+func (session *streamSession) apply(pathsToCopy []PathMapping, pathsToDelete []string) error {
+	if len(pathsToDelete) > 0 {
+		if err := session.send("d", []byte(strings.Join(pathsToDelete, "\x00"))); err != nil {
+			return fmt.Errorf("deleting paths: %w", err)
+		}
+	}
+	if len(pathsToCopy) == 0 {
+		return nil
+	}
+	if err := session.extract(pathsToCopy); err != nil {
+		return fmt.Errorf("copying files: %w", err)
+	}
+	return nil
+}
+
+// send writes an operation to the helper and waits for its answer.
+func (session *streamSession) send(op string, payload []byte) error {
+	if _, err := fmt.Fprintf(session.stdin, "%s %d\n", op, len(payload)); err != nil {
+		return err
+	}
+	if _, err := session.stdin.Write(payload); err != nil {
+		return err
+	}
+	return session.reply("ok")
+}
+
+// extract streams the archive of pathsToCopy to the helper and waits for its
+// answer.
+func (session *streamSession) extract(pathsToCopy []PathMapping) error {
+	if _, err := fmt.Fprintln(session.stdin, "x"); err != nil {
+		return err
+	}
+	chunks := bufio.NewWriterSize(chunkWriter{session.stdin}, 64*1024)
+	ab := NewArchiveBuilder(chunks)
+	if err := ab.ArchivePathsIfExist(pathsToCopy, keepImpliedDirectories); err != nil {
+		return fmt.Errorf("adding files to tar: %w", err)
+	}
+	if err := ab.Close(); err != nil {
+		return fmt.Errorf("closing tar: %w", err)
+	}
+	if err := chunks.Flush(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(session.stdin, "0"); err != nil {
+		return err
+	}
+	return session.reply("ok")
+}
+
+// chunkWriter writes each buffer to the helper as a "<size>" prefixed chunk.
+type chunkWriter struct {
+	w io.Writer
+}
+
+func (c chunkWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if _, err := fmt.Fprintln(c.w, len(p)); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
+
+// streamReplyTimeout bounds the wait for an answer of the helper.
+var streamReplyTimeout = 30 * time.Second
+
+// reply reads the next answer of the helper, expected to be want. The session
+// must be closed on error, to release the pending read after a timeout.
+func (session *streamSession) reply(want string) error {
+	type answer struct {
+		line string
+		err  error
+	}
+	answers := make(chan answer, 1)
+	replies := session.replies
+	go func() {
+		line, err := replies.ReadString('\n')
+		answers <- answer{line, err}
+	}()
+
+	var a answer
+	select {
+	case a = <-answers:
+	case <-time.After(streamReplyTimeout):
+		return fmt.Errorf("sync helper didn't answer within %s", streamReplyTimeout)
+	}
+	if a.err != nil {
+		return fmt.Errorf("reading sync helper answer: %w", a.err)
+	}
+	if line := strings.TrimSpace(a.line); line != want {
+		return fmt.Errorf("sync helper answered %q", line)
+	}
+	return nil
+}
+
+func (session *streamSession) close() {
+	if session.stdin == nil {
+		return
+	}
+	_ = session.stdin.Close()
+	_ = session.stdout.Close()
+	session.stdin, session.stdout, session.replies = nil, nil, nil
+}
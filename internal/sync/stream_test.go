@@ -0,0 +1,221 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sync
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"gotest.tools/v3/assert"
+)
+
+// fakeStreamClient runs a helper speaking the stream protocol, recording the
+// operations it receives.
+type fakeStreamClient struct {
+	fakeLowLevelClient
+	startErr error
+	// failOp makes the helper fail the operations of this kind
+	failOp string
+	// mute makes the helper leave the operations unanswered
+	mute   atomic.Bool
+	starts int
+	mu     sync.Mutex
+	ops    []string
+}
+
+func (f *fakeStreamClient) ExecStream(_ context.Context, _ string, cmd []string) (io.WriteCloser, io.ReadCloser, error) {
+	if f.startErr != nil {
+		return nil, nil, f.startErr
+	}
+	if !slices.Equal(cmd, []string{"sh", "-c", streamHelper}) {
+		return nil, nil, fmt.Errorf("unexpected command %q", cmd)
+	}
+	f.starts++
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	go func() {
+		defer func() { _ = stdoutW.Close() }()
+		_, _ = fmt.Fprintln(stdoutW, "ready")
+		in := bufio.NewReader(stdinR)
+		for {
+			line, err := in.ReadString('\n')
+			if err != nil {
+				return
+			}
+			op, size, _ := strings.Cut(strings.TrimSpace(line), " ")
+			var payload []byte
+			if op == "x" {
+				payload, err = readChunks(in)
+			} else {
+				payload, err = readPayload(in, size)
+			}
+			if err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.ops = append(f.ops, op+" "+describePayload(op, payload))
+			f.mu.Unlock()
+			switch {
+			case f.mute.Load():
+				continue
+			case op == f.failOp:
+				_, _ = fmt.Fprintln(stdoutW, "error 2")
+			default:
+				_, _ = fmt.Fprintln(stdoutW, "ok")
+			}
+		}
+	}()
+	return stdinW, stdoutR, nil
+}
+
+func readPayload(in *bufio.Reader, size string) ([]byte, error) {
+	n, err := strconv.Atoi(size)
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, n)
+	_, err = io.ReadFull(in, payload)
+	return payload, err
+}
+
+// readChunks reads the "<size>" prefixed chunks of an archive up to "0".
+func readChunks(in *bufio.Reader) ([]byte, error) {
+	var payload []byte
+	for {
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size := strings.TrimSpace(line)
+		if size == "0" {
+			return payload, nil
+		}
+		chunk, err := readPayload(in, size)
+		if err != nil {
+			return nil, err
+		}
+		payload = append(payload, chunk...)
+	}
+}
+
+// describePayload lists the paths to delete or the entries of the archive.
+func describePayload(op string, payload []byte) string {
+	if op == "d" {
+		return strings.ReplaceAll(string(payload), "\x00", ",")
+	}
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(payload))
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestExecStreamSync(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"main.go": "package main"})
+	client := &fakeStreamClient{fakeLowLevelClient: fakeLowLevelClient{
+		containers: []container.Summary{{ID: "ctr1"}},
+	}}
+	syncer := NewExecStream("proj", client)
+	paths := []*PathMapping{
+		{HostPath: filepath.Join(dir, "main.go"), ContainerPath: "/app/main.go"},
+		{HostPath: filepath.Join(dir, "gone.go"), ContainerPath: "/app/gone.go"},
+	}
+
+	// a single helper serves the batches
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths))
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths[:1]))
+	assert.Equal(t, client.starts, 1)
+	assert.DeepEqual(t, client.ops, []string{"d /app/gone.go", "x app/main.go", "x app/main.go"})
+	assert.Equal(t, client.untarCount, 0)
+	assert.Equal(t, len(client.execCmds), 0)
+
+	// a failed operation is applied again with tar, and a new helper started
+	client.failOp = "x"
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths[:1]))
+	assert.Equal(t, client.untarCount, 1)
+	client.failOp = ""
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths[:1]))
+	assert.Equal(t, client.starts, 2)
+	assert.Equal(t, client.untarCount, 1)
+
+	// the helpers of the containers which are gone are stopped
+	client.containers = []container.Summary{{ID: "ctr2"}}
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths[:1]))
+	assert.Equal(t, client.starts, 3)
+	syncer.mu.Lock()
+	defer syncer.mu.Unlock()
+	assert.Equal(t, len(syncer.sessions), 1)
+}
+
+func TestExecStreamUnsupported(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"main.go": "package main"})
+	client := &fakeStreamClient{
+		fakeLowLevelClient: fakeLowLevelClient{containers: []container.Summary{{ID: "ctr1"}}},
+		startErr:           errors.New("exec: \"sh\": executable file not found in $PATH"),
+	}
+	syncer := NewExecStream("proj", client)
+	paths := []*PathMapping{{HostPath: filepath.Join(dir, "main.go"), ContainerPath: "/app/main.go"}}
+
+	// the container is synced with tar, without trying to start the helper again
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths))
+	client.startErr = nil
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths))
+	assert.Equal(t, client.untarCount, 2)
+	assert.Equal(t, client.starts, 0)
+}
+
+func TestExecStreamUnanswered(t *testing.T) {
+	defer func(timeout time.Duration) { streamReplyTimeout = timeout }(streamReplyTimeout)
+	streamReplyTimeout = 10 * time.Millisecond
+
+	dir := writeFiles(t, map[string]string{"main.go": "package main"})
+	client := &fakeStreamClient{fakeLowLevelClient: fakeLowLevelClient{
+		containers: []container.Summary{{ID: "ctr1"}},
+	}}
+	syncer := NewExecStream("proj", client)
+	paths := []*PathMapping{{HostPath: filepath.Join(dir, "main.go"), ContainerPath: "/app/main.go"}}
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths))
+
+	// the helper hangs: the operation is applied with tar, and a new helper
+	// started for the next batch
+	client.mute.Store(true)
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths))
+	assert.Equal(t, client.untarCount, 1)
+	client.mute.Store(false)
+	assert.NilError(t, syncer.Sync(t.Context(), "svc", paths))
+	assert.Equal(t, client.starts, 2)
+	assert.Equal(t, client.untarCount, 1)
+}
@@ -70,21 +70,9 @@ func (t *Tar) Sync(ctx context.Context, service string, paths []*PathMapping) er
 		return err
 	}
 
-	var pathsToCopy []PathMapping
-	var pathsToDelete []string
-	for _, p := range paths {
-		if _, err := os.Stat(p.HostPath); err == nil {
-			pathsToCopy = append(pathsToCopy, *p)
-		} else if errors.Is(err, fs.ErrNotExist) {
-			pathsToDelete = append(pathsToDelete, p.ContainerPath)
-		} else {
-			return fmt.Errorf("stat %q: %w", p.HostPath, err)
-		}
-	}
-
-	var deleteCmd []string
-	if len(pathsToDelete) != 0 {
-		deleteCmd = append([]string{"rm", "-rf"}, pathsToDelete...)
+	pathsToCopy, pathsToDelete, err := splitPaths(paths)
+	if err != nil {
+		return err
 	}
 
 	var (
@@ -96,36 +84,11 @@ func (t *Tar) Sync(ctx context.Context, service string, paths []*PathMapping) er
 	eg.SetLimit(16) // arbitrary limit, adjust to taste :D
 	for i := range containers {
 		containerID := containers[i].ID
-		tarReader := tarArchive(pathsToCopy, keepImpliedDirectories)
-
 		eg.Go(func() error {
-			if len(deleteCmd) != 0 {
-				if err := t.client.Exec(ctx, containerID, deleteCmd, nil); err != nil {
-					errMu.Lock()
-					errs = append(errs, fmt.Errorf("deleting paths in %s: %w", containerID, err))
-					errMu.Unlock()
-				}
-			}
-
-			if err := t.client.Untar(ctx, containerID, tarReader); err != nil {
-				// The engine answers with a plain 500, so the message is the only discriminator.
-				if !strings.Contains(err.Error(), "cannot overwrite non-directory") {
-					errMu.Lock()
-					errs = append(errs, fmt.Errorf("copying files to %s: %w", containerID, err))
-					errMu.Unlock()
-					return nil
-				}
-
-				// A directory header for a path the container resolves through a symlink makes
-				// the engine reject the whole copy, so retry without the headers the archive's
-				// own entries already imply. That archive is a strict subset of the one that
-				// just failed, so sending it again is safe.
-				retry := tarArchive(pathsToCopy, dropImpliedDirectories)
-				if retryErr := t.client.Untar(ctx, containerID, retry); retryErr != nil {
-					errMu.Lock()
-					errs = append(errs, fmt.Errorf("copying files to %s: %w", containerID, errors.Join(err, retryErr)))
-					errMu.Unlock()
-				}
+			if err := t.syncContainer(ctx, containerID, pathsToCopy, pathsToDelete); err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
 			}
 			return nil // don't fail-fast; collect all errors
 		})
@@ -135,6 +98,55 @@ func (t *Tar) Sync(ctx context.Context, service string, paths []*PathMapping) er
 	return errors.Join(errs...)
 }
 
+// splitPaths separates the paths to copy to the containers from the
+// container paths to delete, whose host path doesn't exist anymore.
+func splitPaths(paths []*PathMapping) ([]PathMapping, []string, error) {
+	var pathsToCopy []PathMapping
+	var pathsToDelete []string
+	for _, p := range paths {
+		if _, err := os.Stat(p.HostPath); err == nil {
+			pathsToCopy = append(pathsToCopy, *p)
+		} else if errors.Is(err, fs.ErrNotExist) {
+			pathsToDelete = append(pathsToDelete, p.ContainerPath)
+		} else {
+			return nil, nil, fmt.Errorf("stat %q: %w", p.HostPath, err)
+		}
+	}
+	return pathsToCopy, pathsToDelete, nil
+}
+
+// syncContainer deletes pathsToDelete from a container then copies
+// pathsToCopy to it, returning the errors of both steps.
+func (t *Tar) syncContainer(ctx context.Context, containerID string, pathsToCopy []PathMapping, pathsToDelete []string) error {
+	var errs []error
+	if len(pathsToDelete) != 0 {
+		deleteCmd := append([]string{"rm", "-rf"}, pathsToDelete...)
+		if err := t.client.Exec(ctx, containerID, deleteCmd, nil); err != nil {
+			errs = append(errs, fmt.Errorf("deleting paths in %s: %w", containerID, err))
+		}
+	}
+	if len(pathsToCopy) == 0 {
+		return errors.Join(errs...)
+	}
+
+	if err := t.client.Untar(ctx, containerID, tarArchive(pathsToCopy, keepImpliedDirectories)); err != nil {
+		// The engine answers with a plain 500, so the message is the only discriminator.
+		if !strings.Contains(err.Error(), "cannot overwrite non-directory") {
+			return errors.Join(append(errs, fmt.Errorf("copying files to %s: %w", containerID, err))...)
+		}
+
+		// A directory header for a path the container resolves through a symlink makes
+		// the engine reject the whole copy, so retry without the headers the archive's
+		// own entries already imply. That archive is a strict subset of the one that
+		// just failed, so sending it again is safe.
+		retry := tarArchive(pathsToCopy, dropImpliedDirectories)
+		if retryErr := t.client.Untar(ctx, containerID, retry); retryErr != nil {
+			errs = append(errs, fmt.Errorf("copying files to %s: %w", containerID, errors.Join(err, retryErr)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 type ArchiveBuilder struct {
 	tw *tar.Writer
 	// A shared I/O buffer to help with file copying.
@@ -171,6 +183,9 @@ func (a *ArchiveBuilder) ArchivePathsIfExist(paths []PathMapping, dropImpliedDir
 		if err != nil {
 			return fmt.Errorf("inspecting %q: %w", p.HostPath, err)
 		}
+		for _, entry := range newEntries {
+			p.Permissions.Apply(entry.header)
+		}
 
 		entries = append(entries, newEntries...)
 	}
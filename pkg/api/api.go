@@ -18,6 +18,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"slices"
@@ -100,6 +101,12 @@ type Compose interface {
 	Pull(ctx context.Context, project *types.Project, options PullOptions) error
 	// Create executes the equivalent to a `compose create`
 	Create(ctx context.Context, project *types.Project, options CreateOptions) error
+	// Plan computes the operations `compose up` would perform to converge the project, without applying them
+	Plan(ctx context.Context, project *types.Project, options PlanOptions) (*Plan, error)
+	// Apply executes a plan computed by Plan, refusing to if the project resources changed since
+	Apply(ctx context.Context, plan *Plan, options ApplyOptions) error
+	// Drift reports the project resources which diverged from the project model, without changing them
+	Drift(ctx context.Context, project *types.Project, options DriftOptions) ([]Drift, error)
 	// Start executes the equivalent to a `compose start`
 	Start(ctx context.Context, projectName string, options StartOptions) error
 	// Restart restarts containers
@@ -140,6 +147,10 @@ type Compose interface {
 	Port(ctx context.Context, projectName string, service string, port uint16, options PortOptions) (string, int, error)
 	// Publish executes the equivalent to a `compose publish`
 	Publish(ctx context.Context, project *types.Project, repository string, options PublishOptions) error
+	// SaveBundle executes the equivalent to a `compose bundle save`
+	SaveBundle(ctx context.Context, project *types.Project, options BundleSaveOptions) error
+	// LoadBundle executes the equivalent to a `compose bundle load`, and returns the directory the project was extracted to
+	LoadBundle(ctx context.Context, options BundleLoadOptions) (string, error)
 	// Images executes the equivalent of a `compose images`
 	Images(ctx context.Context, projectName string, options ImagesOptions) (map[string]ImageSummary, error)
 	// Watch services' development context and sync/notify/rebuild/restart on changes
@@ -208,6 +219,95 @@ type WatchOptions struct {
 	LogTo    LogConsumer
 	Prune    bool
 	Services []string
+	// ReloadProject loads the project again when one of its configuration
+	// files (compose files, included files, env files) changes, so that the
+	// services are reconciled with it. Configuration files are not watched
+	// if nil.
+	ReloadProject func(ctx context.Context) (*types.Project, error)
+	// IncludedFiles returns the local files included by the compose files the
+	// project was last loaded from, as reported by the "include" LoadListener
+	// events. They are watched along with the other configuration files.
+	IncludedFiles func() []string
+	// OnEvent receives the watch activity as it happens, it may be called
+	// concurrently
+	OnEvent func(event WatchEvent)
+	// QuietPeriod is the time without file changes closing a batch of
+	// changes, 500ms if zero. Watch rules may override it.
+	QuietPeriod time.Duration
+	// RebuildInterval is the minimum time between two rebuilds of a service,
+	// a rebuild requested sooner is delayed. Watch rules may override it.
+	RebuildInterval time.Duration
+	// ImagesInterval is the delay between two checks of the registry for
+	// updates of the service images, the updated images are pulled and
+	// their services recreated. Images are not checked if zero.
+	ImagesInterval time.Duration
+}
+
+const (
+	// WatchEventBatch reports a batch of file changes was detected
+	WatchEventBatch = "batch"
+	// WatchEventMatch reports files of a batch matched a watch rule
+	WatchEventMatch = "match"
+	// WatchEventAction reports an action was applied to services
+	WatchEventAction = "action"
+	// WatchEventBatchDone reports all the actions of a batch completed
+	WatchEventBatchDone = "batch_done"
+)
+
+const (
+	// WatchActionExec runs the exec hook of a sync+exec watch rule
+	WatchActionExec = "exec"
+	// WatchActionReload reloads the project after its configuration changed
+	WatchActionReload = "reload"
+	// WatchActionReverseSync copies the files modified in a container back
+	// to the host
+	WatchActionReverseSync = "reverse_sync"
+	// WatchActionPull pulls the images updated in the registry and recreates
+	// their services
+	WatchActionPull = "pull"
+)
+
+// WatchEvent is an activity of watch mode, served by WatchOptions.OnEvent
+type WatchEvent struct {
+	Time time.Time `json:"time"`
+	// Type is one of WatchEventBatch, WatchEventMatch, WatchEventAction or
+	// WatchEventBatchDone
+	Type     string   `json:"type"`
+	Services []string `json:"services,omitempty"`
+	// Path is the path of the matched watch rule
+	Path string `json:"path,omitempty"`
+	// Action is the matched watch rule action, or the action applied: sync,
+	// restart, rebuild, WatchActionExec, WatchActionReload,
+	// WatchActionReverseSync or WatchActionPull
+	Action string `json:"action,omitempty"`
+	// Files lists the changed files, or the files synced
+	Files []string `json:"files,omitempty"`
+	// Duration is the time spent applying the action or the batch
+	Duration time.Duration `json:"-"`
+	// Error is the failure of the action or the batch
+	Error error `json:"-"`
+}
+
+// MarshalJSON renders the duration of WatchEventAction and WatchEventBatchDone
+// events in milliseconds, with their status and error message.
+func (e WatchEvent) MarshalJSON() ([]byte, error) {
+	type event WatchEvent
+	out := struct {
+		event
+		Duration *int64 `json:"duration_ms,omitempty"`
+		Status   string `json:"status,omitempty"`
+		Error    string `json:"error,omitempty"`
+	}{event: event(e)}
+	if e.Type == WatchEventAction || e.Type == WatchEventBatchDone {
+		ms := e.Duration.Milliseconds()
+		out.Duration = &ms
+		out.Status = "success"
+		if e.Error != nil {
+			out.Status = "failure"
+			out.Error = e.Error.Error()
+		}
+	}
+	return json.Marshal(out)
 }
 
 // BuildOptions group options of the Build API
@@ -222,6 +322,9 @@ type BuildOptions struct {
 	Args types.MappingWithEquals
 	// NoCache disables cache use
 	NoCache bool
+	// Force runs the builds whose image was built from the same inputs, see
+	// ImageFingerprintLabel
+	Force bool
 	// Quiet make the build process not output to the console
 	Quiet bool
 	// Services passed in the command line to be built
@@ -246,6 +349,9 @@ type BuildOptions struct {
 	SBOM string
 	// Out is the stream to write build progress
 	Out io.Writer
+	// Report is the path of a file to write a BuildReport to, once the
+	// build completes
+	Report string
 }
 
 // Apply mutates project according to build options
@@ -279,6 +385,49 @@ func (o BuildOptions) Apply(project *types.Project) error {
 	return nil
 }
 
+// BuildReport reports about the images built by the Build API
+type BuildReport struct {
+	// Builder is the builder which ran the builds, "bake" or "classic"
+	Builder string `json:",omitempty"`
+	// Duration is the time spent building the services, in seconds
+	Duration float64
+	// Services reports about the build of each service, by name
+	Services map[string]ServiceBuildReport
+	// Error is the error the build failed with
+	Error string `json:",omitempty"`
+}
+
+// ServiceBuildReport reports about the build of a service image
+type ServiceBuildReport struct {
+	// Image is the name of the image
+	Image string
+	// ImageID is the content digest of the image in the local image store
+	ImageID string `json:",omitempty"`
+	// Digest is the digest of the image reported by the builder, which
+	// differs from ImageID for attested or multi-platform images
+	Digest string `json:",omitempty"`
+	// Platforms lists the platforms the image was built for
+	Platforms []string `json:",omitempty"`
+	// UpToDate reports the build was skipped, its inputs being unchanged
+	UpToDate bool `json:",omitempty"`
+	// Failed reports the build started but produced no image, as it failed
+	// or was interrupted by the failure of another one
+	Failed bool `json:",omitempty"`
+	// Duration is the build time of the image, in seconds
+	Duration float64
+	// CachedSteps counts the build steps resolved from the build cache. Only
+	// reported by BuildKit builds.
+	CachedSteps int
+	// ExecutedSteps counts the build steps which were run. Only reported by
+	// BuildKit builds.
+	ExecutedSteps int
+	// CacheHitRatio is the ratio of the steps resolved from the build cache
+	CacheHitRatio float64
+	// Warnings lists the warnings of the build, including the checks of the
+	// build configuration
+	Warnings []string `json:",omitempty"`
+}
+
 // CreateOptions group options of the Create API
 type CreateOptions struct {
 	Build *BuildOptions
@@ -304,6 +453,117 @@ type CreateOptions struct {
 	QuietPull bool
 	// SkipProviders skips provider services during convergence (e.g. watch rebuild)
 	SkipProviders bool
+	// RollbackOnFailure keeps the running containers being recreated until
+	// their replacement is running or healthy, and restores them if the
+	// convergence fails. It forces update_config.failure_action to rollback.
+	RollbackOnFailure bool
+	// Explain records why containers are recreated or updated in place, field
+	// by field, and reports it along with the progress events and plan
+	Explain bool
+	// Resume completes or reverts the container recreations left half done
+	// by an interrupted execution, before converging the project. Without it,
+	// such an interrupted execution is an error.
+	Resume bool
+	// NoStart tells the containers are not started once created, as with
+	// `compose create`: running containers are recreated without starting
+	// their replacement, ignoring deploy.update_config
+	NoStart bool
+}
+
+// PlanOptions group options of the Plan API
+type PlanOptions struct {
+	// Create holds the options the plan is computed for, as `compose up` or
+	// `compose create` would receive them. Build is ignored unless
+	// EnsureImages is set.
+	Create CreateOptions
+	// EnsureImages pulls and builds missing images before computing the plan,
+	// as `compose up` would. Otherwise containers are compared with the
+	// images available locally.
+	EnsureImages bool
+}
+
+// ApplyOptions group options of the Apply API
+type ApplyOptions struct {
+	// Replan applies a plan computed against the current project resources
+	// when they changed since the plan was computed, instead of refusing to
+	// apply it.
+	Replan bool
+}
+
+// Plan is the dependency graph of operations required to converge a project,
+// with nodes listed in topological order (dependencies before dependents).
+type Plan struct {
+	Nodes []PlanNode
+	// ObservedState fingerprints the project resources the plan was computed
+	// against, so that Apply detects they changed in between
+	ObservedState string `json:",omitempty"`
+	// Project is the model the plan converges the project to
+	Project *types.Project `json:"-"`
+	// Options are the options the plan was computed with
+	Options CreateOptions `json:"-"`
+}
+
+// PlanNode is a single atomic operation of a Plan
+type PlanNode struct {
+	// ID identifies the node within the plan
+	ID int
+	// Operation is the kind of operation, e.g. "CreateContainer"
+	Operation string
+	// ResourceID identifies the resource the operation applies to, e.g.
+	// "service:web:1", "network:backend" or "volume:data"
+	ResourceID string
+	// Cause explains why the operation is required
+	Cause string
+	// Divergences lists the differences between a container and its expected
+	// configuration which require it to be recreated or updated, e.g.
+	// "environment.FOO changed". Only set with CreateOptions.Explain.
+	Divergences []string `json:",omitempty"`
+	// DependsOn lists the IDs of the nodes that must complete first
+	DependsOn []int
+	// Group relates the nodes of a composite operation, e.g. "recreate:web:1"
+	Group string `json:",omitempty"`
+}
+
+// DriftOptions group options of the Drift API
+type DriftOptions struct {
+	// Services restricts the report to the containers of these services
+	// (empty = all). Networks and volumes are always reported, orphan
+	// containers only without a service filter.
+	Services []string
+}
+
+// Drift kinds
+const (
+	// DriftOutdated reports a container to be recreated to match the model
+	DriftOutdated = "outdated"
+	// DriftResources reports a container whose resource limits or restart
+	// policy differ from the model, and can be updated in place
+	DriftResources = "resources"
+	// DriftNetworks reports a container whose network attachments differ
+	// from the model, and can be connected and disconnected in place
+	DriftNetworks = "networks"
+	// DriftReplicas reports a service running more or fewer replicas than
+	// the model declares
+	DriftReplicas = "replicas"
+	// DriftOrphan reports a container of a service the model does not declare
+	DriftOrphan = "orphan"
+	// DriftDiverged reports a network or volume whose configuration differs
+	// from the model
+	DriftDiverged = "diverged"
+	// DriftMissing reports a network or volume which does not exist,
+	// including external ones
+	DriftMissing = "missing"
+)
+
+// Drift is a divergence between a project resource and the project model
+type Drift struct {
+	// Resource identifies the resource, e.g. "service:web", "container:myproject-web-1",
+	// "network:backend" or "volume:data"
+	Resource string
+	// Kind classifies the divergence, see the Drift* constants
+	Kind string
+	// Details explains the divergence, e.g. "environment.FOO changed"
+	Details string `json:",omitempty"`
 }
 
 // StartOptions group options of the Start API
@@ -336,6 +596,20 @@ type StartOptions struct {
 	// Watch enables watch mode during Up's foreground session; ignored by
 	// Start.
 	Watch bool
+	// ReloadProject is used by watch mode to reload the project when its
+	// configuration files change, see WatchOptions; ignored by Start.
+	ReloadProject func(ctx context.Context) (*types.Project, error)
+	// IncludedFiles is used by watch mode to watch the files included by the
+	// compose files, see WatchOptions; ignored by Start.
+	IncludedFiles func() []string
+	// WatchQuietPeriod and WatchRebuildInterval are used by watch mode, see
+	// WatchOptions.QuietPeriod and WatchOptions.RebuildInterval; ignored by
+	// Start.
+	WatchQuietPeriod     time.Duration
+	WatchRebuildInterval time.Duration
+	// WatchImagesInterval is used by watch mode, see
+	// WatchOptions.ImagesInterval; ignored by Start.
+	WatchImagesInterval time.Duration
 	// NavigationMenu enables the keyboard menu of Up's foreground session;
 	// ignored by Start.
 	NavigationMenu bool
@@ -543,6 +817,24 @@ type PublishOptions struct {
 	InsecureRegistry bool
 }
 
+// BundleSaveOptions group options of the SaveBundle API
+type BundleSaveOptions struct {
+	// Output is the path of the bundle archive, the standard output when empty
+	Output string
+	// Platform restricts the saved images to a platform, all the platforms
+	// available locally are saved otherwise
+	Platform string
+}
+
+// BundleLoadOptions group options of the LoadBundle API
+type BundleLoadOptions struct {
+	// Input is the path of the bundle archive, the standard input when empty
+	Input string
+	// Directory the project is extracted to, a directory named after the
+	// project in the current one by default
+	Directory string
+}
+
 func (e Event) String() string {
 	t := e.Timestamp.Format("2006-01-02 15:04:05.000000")
 	var attr []string
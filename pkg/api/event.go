@@ -57,6 +57,7 @@ const (
 	StatusRemoved          = "Removed"
 	StatusBuilding         = "Building"
 	StatusBuilt            = "Built"
+	StatusUpToDate         = "Up to date"
 	StatusPulling          = "Pulling"
 	StatusPulled           = "Pulled"
 	StatusCommitting       = "Committing"
@@ -65,10 +66,16 @@ const (
 	StatusCopied           = "Copied"
 	StatusExporting        = "Exporting"
 	StatusExported         = "Exported"
+	StatusSaving           = "Saving"
+	StatusSaved            = "Saved"
+	StatusLoading          = "Loading"
+	StatusLoaded           = "Loaded"
 	StatusDownloading      = "Downloading"
 	StatusDownloadComplete = "Download complete"
 	StatusConfiguring      = "Configuring"
 	StatusConfigured       = "Configured"
+	StatusUpdating         = "Updating"
+	StatusUpdated          = "Updated"
 )
 
 // Resource represents status change and progress for a compose resource.
@@ -29,6 +29,14 @@ const (
 	ServiceLabel = "com.docker.compose.service"
 	// ConfigHashLabel stores configuration hash for a compose service
 	ConfigHashLabel = "com.docker.compose.config-hash"
+	// ImmutableConfigHashLabel stores configuration hash for a compose service,
+	// leaving out the resource limits and restart policy which can be updated
+	// on a live container
+	ImmutableConfigHashLabel = "com.docker.compose.immutable-config-hash"
+	// ConfigFieldHashesLabel stores the hash of each field of the configuration
+	// of a compose service, but the environment values, to explain why a
+	// container diverged from it
+	ConfigFieldHashesLabel = "com.docker.compose.config-field-hashes"
 	// ContainerNumberLabel stores the container index of a replicated service
 	ContainerNumberLabel = "com.docker.compose.container-number"
 	// VolumeLabel allow to track resource related to a compose volume
@@ -58,6 +66,9 @@ const (
 	VersionLabel = "com.docker.compose.version"
 	// ImageBuilderLabel stores the builder (classic or BuildKit) used to produce the image.
 	ImageBuilderLabel = "com.docker.compose.image.builder"
+	// ImageFingerprintLabel stores the fingerprint of the build inputs of the
+	// image, so that the build is skipped while they are unchanged
+	ImageFingerprintLabel = "com.docker.compose.image.fingerprint"
 	// ContainerReplaceLabel is set when container is created to replace another container (recreated)
 	ContainerReplaceLabel = "com.docker.compose.replace"
 	// ContainerEngineLabel stores the name of the engine that runs the container
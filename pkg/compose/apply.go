@@ -0,0 +1,123 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func (s *composeService) Apply(ctx context.Context, plan *api.Plan, options api.ApplyOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.apply(ctx, plan, options)
+	}, "apply", s.events)
+}
+
+// apply executes a plan computed by Plan. The plan is not replayed as saved:
+// it is computed again from the saved model against the current project
+// resources, and only executed if it matches the saved one and the resources
+// did not change since — the executor then performs exactly the reviewed
+// operations. Services are then started, as `up --detach` does.
+func (s *composeService) apply(ctx context.Context, saved *api.Plan, options api.ApplyOptions) error {
+	if saved.Project == nil {
+		return errors.New("plan has no project model")
+	}
+	project, err := cloneProject(saved.Project)
+	if err != nil {
+		return err
+	}
+
+	if err := s.labelLocalImageDigests(ctx, project); err != nil {
+		return err
+	}
+
+	if err := s.ensureModels(ctx, project, saved.Options.QuietPull); err != nil {
+		return err
+	}
+
+	project, err = s.useAPISocket(project)
+	if err != nil {
+		return err
+	}
+
+	if err := s.resumeInterruptedPlan(ctx, project.Name, false); err != nil {
+		return err
+	}
+
+	observed, plan, err := s.observeAndReconcile(ctx, project, saved.Options, AlwaysOkPrompt())
+	if err != nil {
+		return err
+	}
+	fingerprint, err := observed.fingerprint()
+	if err != nil {
+		return err
+	}
+
+	current := plan.toAPIPlan()
+	if fingerprint != saved.ObservedState || !slices.EqualFunc(saved.Nodes, current.Nodes, func(a, b api.PlanNode) bool {
+		return formatPlanNode(a) == formatPlanNode(b)
+	}) {
+		diff := diffPlanNodes(saved.Nodes, current.Nodes)
+		if !options.Replan {
+			return fmt.Errorf("project resources changed since the plan was computed, compute and review a new plan:\n%s", diff)
+		}
+		logrus.Warnf("project resources changed since the plan was computed, applying an updated plan:\n%s", diff)
+	}
+
+	emitRunningEvents(project, observed, plan, s.events)
+
+	if err := s.executePlan(ctx, project, observed, plan); err != nil {
+		return err
+	}
+	return s.start(ctx, project.Name, api.StartOptions{Project: project}, nil)
+}
+
+// diffPlanNodes lists the operations only found in the saved plan (prefixed
+// with "-") and those only found in the current one (prefixed with "+").
+func diffPlanNodes(saved, current []api.PlanNode) string {
+	savedLines := make([]string, len(saved))
+	for i, node := range saved {
+		savedLines[i] = formatPlanNode(node)
+	}
+	currentLines := make([]string, len(current))
+	for i, node := range current {
+		currentLines[i] = formatPlanNode(node)
+	}
+
+	var sb strings.Builder
+	for _, line := range savedLines {
+		if !slices.Contains(currentLines, line) {
+			fmt.Fprintf(&sb, "- %s\n", line)
+		}
+	}
+	for _, line := range currentLines {
+		if !slices.Contains(savedLines, line) {
+			fmt.Fprintf(&sb, "+ %s\n", line)
+		}
+	}
+	if sb.Len() == 0 {
+		return "planned operations are unchanged, but the resources they apply to were modified\n"
+	}
+	return sb.String()
+}
@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 	"time"
@@ -62,7 +63,8 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 	}
 
 	// also include services used as additional_contexts with service: prefix
-	options.Services = addBuildDependencies(options.Services, project)
+	bases := projectBaseImages(project)
+	options.Services = addBuildDependencies(options.Services, project, bases)
 
 	// Some build dependencies we just introduced may not be enabled
 	var err error
@@ -96,14 +98,35 @@ func (s *composeService) build(ctx context.Context, project *types.Project, opti
 		return imageIDs, nil
 	}
 
-	bake, err := buildWithBake(s.dockerCli)
+	recorder := newBuildRecorder(s.clock, options)
+	skipped, err := s.skipUnchangedBuilds(ctx, project, serviceToBuild, options, bases, recorder)
 	if err != nil {
 		return nil, err
 	}
-	if bake {
-		return s.doBuildBake(ctx, project, serviceToBuild, options)
+	if len(serviceToBuild) > 0 {
+		bake, err := buildWithBake(s.dockerCli)
+		if err != nil {
+			return nil, err
+		}
+		if bake {
+			imageIDs, err = s.doBuildBake(ctx, project, serviceToBuild, options, bases, recorder)
+		} else {
+			imageIDs, err = s.doBuildClassic(ctx, project, serviceToBuild, options, bases, recorder)
+		}
+		if err != nil {
+			// the report tells which builds failed
+			recorder.failed(err)
+			if reportErr := recorder.write(options.Report); reportErr != nil {
+				logrus.Warn(reportErr.Error())
+			}
+			return imageIDs, err
+		}
 	}
-	return s.doBuildClassic(ctx, project, serviceToBuild, options)
+	maps.Copy(imageIDs, skipped)
+	if options.Print || s.dryRun {
+		return imageIDs, nil
+	}
+	return imageIDs, recorder.write(options.Report)
 }
 
 func (s *composeService) ensureImagesExists(ctx context.Context, project *types.Project, buildOpts *api.BuildOptions, quietPull bool) error {
@@ -150,9 +173,15 @@ func (s *composeService) ensureImagesExists(ctx context.Context, project *types.
 		}
 	}
 
-	// set digest as com.docker.compose.image label so we can detect outdated
-	// containers — the single writer of that label, so the platform-pinned
-	// resolution below can't be overwritten by another code path
+	s.labelImageDigests(ctx, project, images, pinnedDigests)
+	return nil
+}
+
+// labelImageDigests sets the digest of each service image as
+// com.docker.compose.image label so we can detect outdated containers. It is
+// the single writer of that label, so the platform-pinned resolution can't be
+// overwritten by another code path
+func (s *composeService) labelImageDigests(ctx context.Context, project *types.Project, images map[string]api.ImageSummary, pinnedDigests map[string]pinnedImageDigest) {
 	for name, service := range project.Services {
 		image := api.GetImageNameOrDefault(service, project.Name)
 		img, ok := images[image]
@@ -167,7 +196,6 @@ func (s *composeService) ensureImagesExists(ctx context.Context, project *types.
 
 		project.Services[name] = service
 	}
-	return nil
 }
 
 func resolveImageVolumes(service *types.ServiceConfig, images map[string]api.ImageSummary, projectName string) {
@@ -316,7 +344,7 @@ func getImageBuildLabels(project *types.Project, service types.ServiceConfig) ty
 	return ret
 }
 
-func addBuildDependencies(services []string, project *types.Project) []string {
+func addBuildDependencies(services []string, project *types.Project, bases dockerfileBases) []string {
 	servicesWithDependencies := utils.NewSet(services...)
 	for _, service := range services {
 		s, ok := project.Services[service]
@@ -330,10 +358,13 @@ func addBuildDependencies(services []string, project *types.Project) []string {
 					servicesWithDependencies.Add(s)
 				}
 			}
+			for _, base := range bases.services(service) {
+				servicesWithDependencies.Add(base)
+			}
 		}
 	}
 	if len(servicesWithDependencies) > len(services) {
-		return addBuildDependencies(servicesWithDependencies.Elements(), project)
+		return addBuildDependencies(servicesWithDependencies.Elements(), project, bases)
 	}
 	return servicesWithDependencies.Elements()
 }
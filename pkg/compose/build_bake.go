@@ -114,6 +114,15 @@ type bakeMetadata map[string]buildStatus
 type buildStatus struct {
 	Digest string `json:"containerimage.digest"`
 	Image  string `json:"image.name"`
+	// Lint is the result of the build checks, with `--check`
+	Lint *bakeLintResult `json:"result.json,omitempty"`
+}
+
+type bakeLintResult struct {
+	Warnings []struct {
+		RuleName string `json:"ruleName"`
+		Detail   string `json:"detail,omitempty"`
+	} `json:"warnings"`
 }
 
 // bakeBuild is everything derived from the project that doBuildBake needs to
@@ -128,7 +137,7 @@ type bakeBuild struct {
 	secretsEnv     []string
 }
 
-func (s *composeService) doBuildBake(ctx context.Context, project *types.Project, serviceToBeBuild types.Services, options api.BuildOptions) (map[string]string, error) {
+func (s *composeService) doBuildBake(ctx context.Context, project *types.Project, serviceToBeBuild types.Services, options api.BuildOptions, bases dockerfileBases, recorder *buildRecorder) (map[string]string, error) {
 	eg := errgroup.Group{}
 	ch := make(chan *client.SolveStatus)
 	displayMode := progressui.DisplayMode(options.Progress)
@@ -148,7 +157,7 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 		return err
 	})
 
-	bake := s.prepareBakeBuild(project, serviceToBeBuild, options)
+	bake := s.prepareBakeBuild(project, serviceToBeBuild, options, bases)
 
 	cfgJSON, err := json.MarshalIndent(bake.cfg, "", "  ")
 	if err != nil {
@@ -192,6 +201,10 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 	cmd.Env = append(cmd.Env, endpoint...)
 	cmd.Env = append(cmd.Env, bake.secretsEnv...)
 
+	for name, service := range serviceToBeBuild {
+		recorder.building("bake", service, bake.expectedImages[name])
+	}
+
 	cmd.Stdout = s.stdout()
 	cmd.Stdin = bytes.NewBuffer(cfgJSON)
 	pipe, err := cmd.StderrPipe()
@@ -205,7 +218,7 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 	}
 	eg.Go(cmd.Wait)
 
-	errMessage, err := forwardBakeStatus(pipe, ch)
+	errMessage, err := forwardBakeStatus(pipe, ch, recorder)
 	if err != nil {
 		return nil, err
 	}
@@ -213,18 +226,20 @@ func (s *composeService) doBuildBake(ctx context.Context, project *types.Project
 
 	err = eg.Wait()
 	if err != nil {
+		recorder.attributeSteps(bake.stepService())
 		if len(errMessage) > 0 {
 			return nil, errors.New(strings.Join(errMessage, "\n"))
 		}
 		return nil, fmt.Errorf("failed to execute bake: %w", err)
 	}
 
-	return s.collectBakeResults(ctx, metadataFile, serviceToBeBuild, bake)
+	recorder.attributeSteps(bake.stepService())
+	return s.collectBakeResults(ctx, metadataFile, serviceToBeBuild, bake, recorder)
 }
 
 // prepareBakeBuild translates the project's build configuration into a bake
 // file definition and the side-band settings bake takes on its command line.
-func (s *composeService) prepareBakeBuild(project *types.Project, serviceToBeBuild types.Services, options api.BuildOptions) *bakeBuild {
+func (s *composeService) prepareBakeBuild(project *types.Project, serviceToBeBuild types.Services, options api.BuildOptions, bases dockerfileBases) *bakeBuild {
 	bake := &bakeBuild{
 		cfg: bakeConfig{
 			Groups:  map[string]bakeGroup{},
@@ -235,9 +250,10 @@ func (s *composeService) prepareBakeBuild(project *types.Project, serviceToBeBui
 	}
 
 	// project.Services lists every service (we still need their bake targets
-	// defined so additional_contexts: service:xxx references can resolve),
-	// but only emit "Building" progress and track expected images for
-	// services we actually plan to build.
+	// defined so additional_contexts: service:xxx references and the base
+	// images of the Dockerfiles can resolve, including those whose build was
+	// skipped as unchanged), but only emit "Building" progress and track
+	// expected images for services we actually plan to build.
 	for serviceName, service := range project.Services {
 		if service.Build == nil {
 			continue
@@ -262,10 +278,19 @@ func (s *composeService) prepareBakeBuild(project *types.Project, serviceToBeBui
 		secrets, env := toBakeSecrets(project, buildConfig.Secrets)
 		bake.secretsEnv = append(bake.secretsEnv, env...)
 
+		// build the images the Dockerfile is based on before the service
+		contexts := additionalContexts(buildConfig.AdditionalContexts, bake.targetNames)
+		for image, base := range bases.services(serviceName) {
+			target, ok := bake.targetNames[base]
+			if _, set := contexts[bakeContextName(image)]; ok && !set {
+				contexts[bakeContextName(image)] = "target:" + target
+			}
+		}
+
 		outputs, call := bakeOutputs(service, options)
 		bake.cfg.Targets[bake.targetNames[serviceName]] = bakeTarget{
 			Context:          buildConfig.Context,
-			Contexts:         additionalContexts(buildConfig.AdditionalContexts, bake.targetNames),
+			Contexts:         contexts,
 			Dockerfile:       dockerFilePath(buildConfig.Context, buildConfig.Dockerfile),
 			DockerfileInline: strings.ReplaceAll(buildConfig.DockerfileInline, "${", "$${"),
 			Args:             args,
@@ -306,6 +331,27 @@ func (s *composeService) prepareBakeBuild(project *types.Project, serviceToBeBui
 	return bake
 }
 
+// stepService returns a function telling the service a build step reported
+// by bake belongs to.
+func (bake *bakeBuild) stepService() func(step string) string {
+	targets := bake.cfg.Groups["default"].Targets
+	if len(targets) == 1 {
+		// bake doesn't prefix the steps of a single target
+		for name, target := range bake.targetNames {
+			if target == targets[0] {
+				return func(string) string { return name }
+			}
+		}
+	}
+	services := make(map[string]string, len(bake.targetNames))
+	for name, target := range bake.targetNames {
+		services[target] = name
+	}
+	return func(step string) string {
+		return services[bakeStepTarget(step)]
+	}
+}
+
 // bakeTargetNames produces a unique ID for each service, used as bake target.
 // Replacing dots can make distinct service names collide (`a.b` vs `a_b`), so
 // names are allocated in sorted service order — deterministic — and a
@@ -413,9 +459,9 @@ func bakeArgs(bake *bakeBuild, metadataFile string, options api.BuildOptions) []
 }
 
 // forwardBakeStatus reads bake's rawjson stderr stream, forwarding solve
-// statuses to the progress UI channel. Lines that are not solve statuses are
+// statuses to the progress UI channel and the recorder. Lines that are not solve statuses are
 // collected as error messages, to be reported if bake exits non-zero.
-func forwardBakeStatus(pipe io.Reader, ch chan<- *client.SolveStatus) ([]string, error) {
+func forwardBakeStatus(pipe io.Reader, ch chan<- *client.SolveStatus, recorder *buildRecorder) ([]string, error) {
 	var errMessage []string
 	reader := bufio.NewReader(pipe)
 	for {
@@ -436,13 +482,14 @@ func forwardBakeStatus(pipe io.Reader, ch chan<- *client.SolveStatus) ([]string,
 			errMessage = append(errMessage, strings.TrimPrefix(line, "ERROR: "))
 			continue
 		}
+		recorder.solveStatus(&status)
 		ch <- &status
 	}
 }
 
 // collectBakeResults reads bake's metadata file and maps each built image to
 // its canonical digest.
-func (s *composeService) collectBakeResults(ctx context.Context, metadataFile string, serviceToBeBuild types.Services, bake *bakeBuild) (map[string]string, error) {
+func (s *composeService) collectBakeResults(ctx context.Context, metadataFile string, serviceToBeBuild types.Services, bake *bakeBuild, recorder *buildRecorder) (map[string]string, error) {
 	raw, err := os.ReadFile(metadataFile)
 	if err != nil {
 		return nil, err
@@ -467,6 +514,12 @@ func (s *composeService) collectBakeResults(ctx context.Context, metadataFile st
 			return nil, fmt.Errorf("build result not found in Bake metadata for service %s", name)
 		}
 		results[image] = s.canonicalBuiltDigest(ctx, image, service.Platform, built.Digest)
+		recorder.built(name, results[image], built.Digest)
+		if built.Lint != nil {
+			for _, warning := range built.Lint.Warnings {
+				recorder.warn(name, fmt.Sprintf("%s: %s", warning.RuleName, warning.Detail))
+			}
+		}
 		s.events.On(builtEvent(image))
 	}
 
@@ -45,21 +45,32 @@ import (
 	"github.com/docker/compose/v5/pkg/api"
 )
 
-func (s *composeService) doBuildClassic(ctx context.Context, project *types.Project, serviceToBuild types.Services, options api.BuildOptions) (map[string]string, error) {
+func (s *composeService) doBuildClassic(ctx context.Context, project *types.Project, serviceToBuild types.Services, options api.BuildOptions, bases dockerfileBases, recorder *buildRecorder) (map[string]string, error) {
 	imageIDs := map[string]string{}
 
-	// Not using bake, additional_context: service:xx is implemented by building images in dependency order
+	// Not using bake, additional_context: service:xx and images based on the
+	// image of another service are implemented by building images in dependency order
 	project, err := project.WithServicesTransform(func(serviceName string, service types.ServiceConfig) (types.ServiceConfig, error) {
-		if service.Build != nil {
-			for _, additionalContext := range service.Build.AdditionalContexts {
-				if targetService, found := strings.CutPrefix(additionalContext, types.ServicePrefix); found {
-					if service.DependsOn == nil {
-						service.DependsOn = map[string]types.ServiceDependency{}
-					}
-					service.DependsOn[targetService] = types.ServiceDependency{
-						Condition: "build", // non-canonical, but will force dependency graph ordering
-					}
-				}
+		if service.Build == nil {
+			return service, nil
+		}
+		var dependencies []string
+		for _, additionalContext := range service.Build.AdditionalContexts {
+			if targetService, found := strings.CutPrefix(additionalContext, types.ServicePrefix); found {
+				dependencies = append(dependencies, targetService)
+			}
+		}
+		for _, base := range bases.services(serviceName) {
+			if _, ok := project.Services[base]; ok {
+				dependencies = append(dependencies, base)
+			}
+		}
+		for _, dependency := range dependencies {
+			if service.DependsOn == nil {
+				service.DependsOn = map[string]types.ServiceDependency{}
+			}
+			service.DependsOn[dependency] = types.ServiceDependency{
+				Condition: "build", // non-canonical, but will force dependency graph ordering
 			}
 		}
 		return service, nil
@@ -89,6 +100,7 @@ func (s *composeService) doBuildClassic(ctx context.Context, project *types.Proj
 
 		image := api.GetImageNameOrDefault(service, project.Name)
 		s.events.On(buildingEvent(image))
+		recorder.building("classic", service, image)
 		id, err := s.doBuildImage(ctx, project, service, options)
 		if err != nil {
 			return err
@@ -99,6 +111,7 @@ func (s *composeService) doBuildClassic(ctx context.Context, project *types.Proj
 		// identity matches what later runs compute for the same local image
 		// (resolved here to inherit the build traversal's concurrency)
 		builtDigests[getServiceIndex(name)] = s.canonicalBuiltDigest(ctx, image, service.Platform, id)
+		recorder.built(name, builtDigests[getServiceIndex(name)], id)
 
 		if options.Push {
 			return s.push(ctx, project, api.PushOptions{})
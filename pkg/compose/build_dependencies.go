@@ -0,0 +1,203 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/moby/buildkit/frontend/dockerfile/shell"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// dockerfileBases holds the images the Dockerfiles of the services of a
+// project are based on, by service name then image, with the service building
+// each image, "" for those no service builds. It is computed once per build,
+// see projectBaseImages.
+type dockerfileBases map[string]map[string]string
+
+// projectBaseImages parses the Dockerfiles of the services of a project,
+// disabled ones included, for the images they are based on. Dockerfiles which
+// can't be read, such as those of remote contexts, have none.
+func projectBaseImages(project *types.Project) dockerfileBases {
+	builtImages := map[string]string{} // normalized image name → service
+	for _, candidates := range []types.Services{project.Services, project.DisabledServices} {
+		for name, candidate := range candidates {
+			if candidate.Build == nil {
+				continue
+			}
+			if image := normalizedImageName(api.GetImageNameOrDefault(candidate, project.Name)); image != "" {
+				builtImages[image] = name
+			}
+		}
+	}
+
+	bases := dockerfileBases{}
+	for _, candidates := range []types.Services{project.Services, project.DisabledServices} {
+		for name, service := range candidates {
+			if service.Build == nil {
+				continue
+			}
+			images, err := dockerfileBaseImages(*service.Build)
+			if err != nil {
+				logrus.Debugf("cannot detect the base images of service %q: %v", name, err)
+				continue
+			}
+			if len(images) == 0 {
+				continue
+			}
+			bases[name] = map[string]string{}
+			for _, image := range images {
+				builder := builtImages[normalizedImageName(image)]
+				if builder == name {
+					// the previous image of the service itself
+					continue
+				}
+				bases[name][image] = builder
+			}
+		}
+	}
+	return bases
+}
+
+// services returns the services whose image the Dockerfile of a service is
+// based on, by the name the Dockerfile refers to it with.
+func (b dockerfileBases) services(service string) map[string]string {
+	services := map[string]string{}
+	for image, builder := range b[service] {
+		if builder != "" {
+			services[image] = builder
+		}
+	}
+	return services
+}
+
+// external returns the images the Dockerfile of a service is based on which
+// no service builds, other than scratch, sorted.
+func (b dockerfileBases) external(service string) []string {
+	var images []string
+	for image, builder := range b[service] {
+		if builder == "" && image != "scratch" && normalizedImageName(image) != "" {
+			images = append(images, image)
+		}
+	}
+	slices.Sort(images)
+	return images
+}
+
+// dockerfileBaseImages returns the images the stages of the Dockerfile of a
+// build are based on, with the build arguments substituted. The stages based
+// on another stage are skipped.
+func dockerfileBaseImages(build types.BuildConfig) ([]string, error) {
+	var dockerfile io.Reader
+	if build.DockerfileInline != "" {
+		dockerfile = strings.NewReader(build.DockerfileInline)
+	} else {
+		if !isLocalBuildContext(build.Context) {
+			return nil, nil
+		}
+		name := build.Dockerfile
+		if name == "" {
+			name = "Dockerfile"
+		}
+		f, err := os.Open(dockerFilePath(build.Context, name))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close() //nolint:errcheck
+		dockerfile = f
+	}
+	result, err := parser.Parse(dockerfile)
+	if err != nil {
+		return nil, err
+	}
+
+	lex := shell.NewLex(result.EscapeToken)
+	args := map[string]string{} // global arguments, usable in FROM
+	var stages, images []string
+	for _, node := range result.AST.Children {
+		switch strings.ToLower(node.Value) {
+		case "arg":
+			if len(stages) > 0 {
+				continue
+			}
+			for arg := node.Next; arg != nil; arg = arg.Next {
+				name, value, hasDefault := strings.Cut(arg.Value, "=")
+				if v, ok := build.Args[name]; ok && v != nil {
+					args[name] = *v
+				} else if hasDefault {
+					if value, _, err = lex.ProcessWord(value, buildArgsEnv(args)); err == nil {
+						args[name] = value
+					}
+				}
+			}
+		case "from":
+			if node.Next == nil {
+				continue
+			}
+			image, _, err := lex.ProcessWord(node.Next.Value, buildArgsEnv(args))
+			if err != nil {
+				return nil, err
+			}
+			if !slices.Contains(stages, strings.ToLower(image)) && !slices.Contains(images, image) {
+				images = append(images, image)
+			}
+			if as := node.Next.Next; as != nil && strings.EqualFold(as.Value, "as") && as.Next != nil {
+				stages = append(stages, strings.ToLower(as.Next.Value))
+			} else {
+				stages = append(stages, "")
+			}
+		}
+	}
+	return images, nil
+}
+
+func buildArgsEnv(args map[string]string) shell.EnvGetter {
+	var env []string
+	for name, value := range args {
+		env = append(env, name+"="+value)
+	}
+	return shell.EnvsFromSlice(env)
+}
+
+// normalizedImageName returns the fully qualified name of an image, "" when
+// it is invalid.
+func normalizedImageName(image string) string {
+	named, err := reference.ParseDockerRef(image)
+	if err != nil {
+		return ""
+	}
+	return named.String()
+}
+
+// bakeContextName returns the name of the bake context overriding an image
+// in the FROM instructions of a Dockerfile, as the Dockerfile frontend looks
+// it up.
+func bakeContextName(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	return strings.TrimSuffix(reference.FamiliarString(named), ":latest")
+}
@@ -0,0 +1,116 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestDockerfileBaseImages(t *testing.T) {
+	dir := writeBuildContext(t, map[string]string{
+		"build.Dockerfile": `ARG BASE=myproject-base
+ARG VERSION
+FROM ${BASE} AS builder
+RUN make
+FROM golang:${VERSION:-1.26} AS tools
+FROM builder
+FROM alpine
+COPY --from=builder /app /app
+`,
+	})
+	images, err := dockerfileBaseImages(types.BuildConfig{Context: dir, Dockerfile: "build.Dockerfile"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, images, []string{"myproject-base", "golang:1.26", "alpine"})
+
+	images, err = dockerfileBaseImages(types.BuildConfig{
+		Context:    dir,
+		Dockerfile: "build.Dockerfile",
+		Args: types.MappingWithEquals{
+			"BASE":    new("registry.example.com/base:1"),
+			"VERSION": new("1.25"),
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, images, []string{"registry.example.com/base:1", "golang:1.25", "alpine"})
+
+	images, err = dockerfileBaseImages(types.BuildConfig{DockerfileInline: "FROM base\n"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, images, []string{"base"})
+
+	images, err = dockerfileBaseImages(types.BuildConfig{Context: "https://github.com/docker/compose.git"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(images), 0)
+}
+
+func TestBaseImageServices(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"base": {Name: "base", Build: &types.BuildConfig{DockerfileInline: "FROM alpine\n"}},
+			"tools": {Name: "tools", Image: "tools", Build: &types.BuildConfig{
+				DockerfileInline: "FROM alpine\n",
+			}},
+			"redis": {Name: "redis", Image: "redis"},
+			"app": {Name: "app", Build: &types.BuildConfig{
+				DockerfileInline: "FROM myproject-base\nFROM docker.io/library/tools:latest\nFROM redis\n",
+			}},
+		},
+	}
+	bases := projectBaseImages(project)
+	assert.DeepEqual(t, bases.services("app"), map[string]string{
+		"myproject-base":                 "base",
+		"docker.io/library/tools:latest": "tools",
+	})
+	assert.DeepEqual(t, bases.external("app"), []string{"redis"})
+	assert.DeepEqual(t, bases.external("base"), []string{"alpine"})
+
+	services := addBuildDependencies([]string{"app"}, project, bases)
+	slices.Sort(services)
+	assert.DeepEqual(t, services, []string{"app", "base", "tools"})
+}
+
+func TestBakeBaseImageContexts(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"base": {Name: "base", Build: &types.BuildConfig{DockerfileInline: "FROM alpine\n"}},
+			"app": {Name: "app", Build: &types.BuildConfig{
+				DockerfileInline:   "FROM myproject-base\nFROM docker.io/library/alpine:latest\n",
+				AdditionalContexts: types.Mapping{"assets": "./assets"},
+			}},
+		},
+	}
+	tested := &composeService{events: &ignore{}, proxyConfig: map[string]string{}}
+	bake := tested.prepareBakeBuild(project, project.Services, api.BuildOptions{}, projectBaseImages(project))
+	assert.DeepEqual(t, bake.cfg.Targets["app"].Contexts, map[string]string{
+		"assets":         "./assets",
+		"myproject-base": "target:base",
+	})
+
+	// a base whose build is skipped as unchanged keeps its target
+	bake = tested.prepareBakeBuild(project, types.Services{"app": project.Services["app"]}, api.BuildOptions{}, projectBaseImages(project))
+	assert.Equal(t, bake.cfg.Targets["app"].Contexts["myproject-base"], "target:base")
+	_, ok := bake.cfg.Targets["base"]
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, bake.cfg.Groups["default"].Targets, []string{"app"})
+}
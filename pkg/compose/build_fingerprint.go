@@ -0,0 +1,417 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+// skipUnchangedBuilds removes from serviceToBuild the services whose image
+// was built from the same inputs, according to the fingerprint label of the
+// local image, and returns their image ID by image name. The services still
+// to build get the fingerprint of their inputs as a label of their image.
+func (s *composeService) skipUnchangedBuilds(ctx context.Context, project *types.Project, serviceToBuild types.Services, options api.BuildOptions, bases dockerfileBases, recorder *buildRecorder) (map[string]string, error) {
+	skipped := map[string]string{}
+	if options.Force || options.NoCache || options.Pull || options.Push || options.Print || options.Check {
+		// the build may produce a different image from the same inputs, or
+		// is expected to do more than producing the image
+		return skipped, nil
+	}
+
+	// other builders resolve the base images themselves, usually from their
+	// registry, which the local images don't tell about: the builds relying
+	// on such images have no fingerprint
+	var baseImageIDs map[string]string
+	engine, err := s.engineBuilder(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if engine {
+		baseImageIDs, err = s.externalBaseImageIDs(ctx, project, bases)
+		if err != nil {
+			return nil, err
+		}
+	}
+	f := newBuildFingerprinter(project, s.getProxyConfig(), options, bases, baseImageIDs)
+	var images []string
+	for _, service := range serviceToBuild {
+		images = append(images, api.GetImageNameOrDefault(service, project.Name))
+	}
+	inspections, err := s.inspectLocalImages(ctx, images)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, service := range serviceToBuild {
+		fingerprint, err := f.fingerprint(name)
+		if err != nil {
+			return nil, err
+		}
+		if fingerprint == "" {
+			continue
+		}
+		image := api.GetImageNameOrDefault(service, project.Name)
+		if inspect, ok := inspections[image]; ok && inspect.Config != nil &&
+			inspect.Config.Labels[api.ImageFingerprintLabel] == fingerprint &&
+			!service.Build.NoCache && !service.Build.Pull {
+			logrus.Debugf("skipping build of %s: build inputs unchanged (%s)", image, fingerprint)
+			s.events.On(upToDateEvent(image))
+			skipped[image] = imageSummary(image, inspect).ID
+			recorder.upToDate(service, image, skipped[image])
+			delete(serviceToBuild, name)
+			continue
+		}
+
+		build := *service.Build
+		build.Labels = maps.Clone(build.Labels).Add(api.ImageFingerprintLabel, fingerprint)
+		service.Build = &build
+		project.Services[name] = service
+		serviceToBuild[name] = service
+	}
+	return skipped, nil
+}
+
+// buildFingerprinter computes the fingerprint of the inputs of the service
+// builds: the build configuration, with the arguments resolved, the content
+// of the build context, honoring its .dockerignore file, the Dockerfile, the
+// additional contexts and the images the Dockerfile is based on: the
+// fingerprint of those built by other services, the local image ID of the
+// others. Builds relying on inputs which may change without notice, such as
+// remote contexts, or on base images without a local image ID, have no
+// fingerprint.
+type buildFingerprinter struct {
+	project *types.Project
+	proxy   map[string]string
+	options api.BuildOptions
+	bases   dockerfileBases
+	// baseImageIDs holds the local image ID of the base images which are not
+	// built by a service, by name
+	baseImageIDs map[string]string
+	// fingerprints holds the fingerprint of the services, by name, "" for
+	// the builds which have none
+	fingerprints map[string]string
+	// contexts holds the digest of the build contexts already hashed
+	contexts map[buildContextKey]string
+}
+
+// buildContextKey identifies a build context hashed with the patterns of a
+// .dockerignore file, none if empty.
+type buildContextKey struct {
+	context      string
+	dockerignore string
+}
+
+func newBuildFingerprinter(project *types.Project, proxy map[string]string, options api.BuildOptions, bases dockerfileBases, baseImageIDs map[string]string) *buildFingerprinter {
+	return &buildFingerprinter{
+		project:      project,
+		proxy:        proxy,
+		options:      options,
+		bases:        bases,
+		baseImageIDs: baseImageIDs,
+		fingerprints: map[string]string{},
+		contexts:     map[buildContextKey]string{},
+	}
+}
+
+// engineBuilder tells whether the builds run on the builder of the Engine,
+// which bases the images on the local images: the classic builder, or a
+// buildx builder using the docker driver.
+func (s *composeService) engineBuilder(ctx context.Context, options api.BuildOptions) (bool, error) {
+	bake, err := buildWithBake(s.dockerCli)
+	if err != nil || !bake {
+		return !bake, err
+	}
+	buildx, err := s.getBuildxPlugin()
+	if err != nil {
+		return false, err
+	}
+	args := []string{"inspect"}
+	if options.Builder != "" {
+		args = append(args, "--builder", options.Builder)
+	}
+	cmd := exec.CommandContext(ctx, buildx.Path, args...)
+	if err := s.prepareShellOut(ctx, types.NewMapping(os.Environ()), cmd); err != nil {
+		return false, err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		logrus.Debugf("cannot inspect the buildx builder, assuming it is not the Engine's: %v", err)
+		return false, nil
+	}
+	return buildxDriver(string(out)) == "docker", nil
+}
+
+// buildxDriver returns the driver of a builder from the output of buildx
+// inspect.
+func buildxDriver(inspect string) string {
+	for line := range strings.Lines(inspect) {
+		if driver, ok := strings.CutPrefix(line, "Driver:"); ok {
+			return strings.TrimSpace(driver)
+		}
+	}
+	return ""
+}
+
+// externalBaseImageIDs returns the local image ID of the images the
+// Dockerfiles of the project are based on, other than those built by a
+// service, by name. Images which are not pulled yet are missing.
+func (s *composeService) externalBaseImageIDs(ctx context.Context, project *types.Project, bases dockerfileBases) (map[string]string, error) {
+	var images []string
+	for name := range project.Services {
+		for _, image := range bases.external(name) {
+			if !slices.Contains(images, image) {
+				images = append(images, image)
+			}
+		}
+	}
+	inspections, err := s.inspectLocalImages(ctx, images)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]string{}
+	for image, inspect := range inspections {
+		ids[image] = imageSummary(image, inspect).ID
+	}
+	return ids, nil
+}
+
+func (f *buildFingerprinter) fingerprint(name string) (string, error) {
+	if fingerprint, ok := f.fingerprints[name]; ok {
+		return fingerprint, nil
+	}
+	// a dependency cycle gets no fingerprint
+	f.fingerprints[name] = ""
+	service, ok := f.project.Services[name]
+	if !ok || service.Build == nil {
+		return "", nil
+	}
+	fingerprint, err := f.compute(name, service)
+	if err != nil {
+		return "", fmt.Errorf("computing the build fingerprint of service %q: %w", name, err)
+	}
+	f.fingerprints[name] = fingerprint
+	return fingerprint, nil
+}
+
+func (f *buildFingerprinter) compute(name string, service types.ServiceConfig) (string, error) {
+	build := *service.Build
+	if !isLocalBuildContext(build.Context) {
+		return "", nil
+	}
+	h := sha256.New()
+
+	build.Args = resolveAndMergeBuildArgs(f.proxy, f.project, service, f.options)
+	build.Labels = maps.Clone(build.Labels)
+	delete(build.Labels, api.ImageBuilderLabel)
+	delete(build.Labels, api.ImageFingerprintLabel)
+	config, err := json.Marshal(build)
+	if err != nil {
+		return "", err
+	}
+	writeFingerprintField(h, "config", string(config))
+
+	for _, key := range slices.Sorted(maps.Keys(build.AdditionalContexts)) {
+		additionalContext := build.AdditionalContexts[key]
+		switch dependency, isService := strings.CutPrefix(additionalContext, types.ServicePrefix); {
+		case isService:
+			fingerprint, err := f.fingerprint(dependency)
+			if err != nil || fingerprint == "" {
+				return "", err
+			}
+			writeFingerprintField(h, "context "+key, fingerprint)
+		case isLocalBuildContext(additionalContext):
+			digest, err := f.contextDigest(buildContextKey{context: additionalContext}, watch.EmptyMatcher{})
+			if err != nil {
+				return "", err
+			}
+			writeFingerprintField(h, "context "+key, digest)
+		default:
+			// images and remote contexts may change without notice
+			return "", nil
+		}
+	}
+
+	// the images of other services the Dockerfile is based on are rebuilt
+	// when their inputs change
+	bases := f.bases.services(name)
+	for _, image := range slices.Sorted(maps.Keys(bases)) {
+		fingerprint, err := f.fingerprint(bases[image])
+		if err != nil || fingerprint == "" {
+			return "", err
+		}
+		writeFingerprintField(h, "base "+image, fingerprint)
+	}
+	// the other ones by their local image ID, which changes when they are
+	// pulled again
+	for _, image := range f.bases.external(name) {
+		id, ok := f.baseImageIDs[image]
+		if !ok {
+			return "", nil
+		}
+		writeFingerprintField(h, "base "+image, id)
+	}
+
+	if build.DockerfileInline == "" {
+		dockerfile := build.Dockerfile
+		if dockerfile == "" {
+			dockerfile = "Dockerfile"
+		}
+		content, err := os.ReadFile(dockerFilePath(build.Context, dockerfile))
+		if err != nil {
+			return "", err
+		}
+		writeFingerprintField(h, "dockerfile", sha256Hex(content))
+	}
+
+	for _, secret := range build.Secrets {
+		config := f.project.Secrets[secret.Source]
+		var content []byte
+		switch {
+		case config.File != "":
+			if content, err = os.ReadFile(config.File); err != nil {
+				return "", err
+			}
+		case config.Environment != "":
+			content = []byte(f.project.Environment[config.Environment])
+		}
+		sum := sha256.Sum256(content)
+		writeFingerprintField(h, "secret "+secret.Source, hex.EncodeToString(sum[:]))
+	}
+
+	key := buildContextKey{context: build.Context, dockerignore: dockerignoreFile(build)}
+	var ignore watch.PathMatcher = watch.EmptyMatcher{}
+	if key.dockerignore != "" {
+		if ignore, err = watch.LoadDockerIgnore(&build); err != nil {
+			return "", err
+		}
+	}
+	digest, err := f.contextDigest(key, ignore)
+	if err != nil {
+		return "", err
+	}
+	writeFingerprintField(h, "context", digest)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// contextDigest returns the digest of a build context, hashed once for all
+// the services sharing it.
+func (f *buildFingerprinter) contextDigest(key buildContextKey, ignore watch.PathMatcher) (string, error) {
+	if digest, ok := f.contexts[key]; ok {
+		return digest, nil
+	}
+	h := sha256.New()
+	if err := hashBuildContext(h, key.context, ignore); err != nil {
+		return "", err
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+	f.contexts[key] = digest
+	return digest, nil
+}
+
+// dockerignoreFile returns the .dockerignore file the builder reads the
+// ignored files of a build context from, "" if there is none.
+func dockerignoreFile(build types.BuildConfig) string {
+	for _, name := range []string{build.Dockerfile + ".dockerignore", ".dockerignore"} {
+		path := filepath.Join(build.Context, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// isLocalBuildContext tells whether a build context is a local directory.
+func isLocalBuildContext(buildContext string) bool {
+	return slices.Contains(localBuildPaths(types.BuildConfig{Context: buildContext}), buildContext)
+}
+
+// hashBuildContext hashes the names, types, permissions and content of the
+// files of a build context, skipping the ignored ones.
+func hashBuildContext(h hash.Hash, root string, ignore watch.PathMatcher) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		if entry.IsDir() {
+			if ignored, err := ignore.MatchesEntireDir(path); err != nil || ignored {
+				if ignored {
+					return fs.SkipDir
+				}
+				return err
+			}
+		} else if ignored, err := ignore.Matches(path); err != nil || ignored {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		writeFingerprintField(h, filepath.ToSlash(rel), info.Mode().String())
+		switch {
+		case info.Mode().IsRegular():
+			sum, err := fileSHA256(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				// removed meanwhile
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			writeFingerprintField(h, "content", sum)
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			writeFingerprintField(h, "symlink", target)
+		}
+		return nil
+	})
+}
+
+// writeFingerprintField writes a NUL terminated name and value, so that
+// distinct inputs can't produce the same stream.
+func writeFingerprintField(h hash.Hash, name, value string) {
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00", name, value)
+}
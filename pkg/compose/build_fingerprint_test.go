@@ -0,0 +1,173 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+func writeBuildContext(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NilError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestBuildFingerprint(t *testing.T) {
+	dir := writeBuildContext(t, map[string]string{
+		"Dockerfile":    "FROM alpine\nCOPY . /app\n",
+		".dockerignore": "*.log\n",
+		"main.go":       "package main",
+		"debug.log":     "",
+	})
+	project := &types.Project{Name: "myproject", Services: types.Services{
+		"api": {Name: "api", Build: &types.BuildConfig{
+			Context:    dir,
+			Dockerfile: "Dockerfile",
+			Args:       types.MappingWithEquals{"VERSION": new("1")},
+		}},
+		"worker": {Name: "worker", Build: &types.BuildConfig{
+			Context:            dir,
+			Dockerfile:         "Dockerfile",
+			AdditionalContexts: types.Mapping{"api": "service:api"},
+		}},
+		"remote": {Name: "remote", Build: &types.BuildConfig{
+			Context: "https://github.com/docker/compose.git",
+		}},
+	}}
+	baseImageIDs := map[string]string{"alpine": "sha256:alpine"}
+	fingerprint := func(name string) string {
+		t.Helper()
+		f := newBuildFingerprinter(project, nil, api.BuildOptions{}, projectBaseImages(project), baseImageIDs)
+		fingerprint, err := f.fingerprint(name)
+		assert.NilError(t, err)
+		return fingerprint
+	}
+
+	initial := fingerprint("api")
+	assert.Assert(t, initial != "")
+
+	// the services sharing a build context hash it once
+	f := newBuildFingerprinter(project, nil, api.BuildOptions{}, projectBaseImages(project), baseImageIDs)
+	_, err := f.fingerprint("worker")
+	assert.NilError(t, err)
+	assert.Equal(t, len(f.contexts), 1)
+	assert.Equal(t, fingerprint("api"), initial, "fingerprints are stable")
+	assert.Equal(t, fingerprint("remote"), "", "remote contexts have no fingerprint")
+
+	// ignored files are not part of the fingerprint
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("started"), 0o644))
+	assert.Equal(t, fingerprint("api"), initial)
+
+	worker := fingerprint("worker")
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // changed"), 0o644))
+	changed := fingerprint("api")
+	assert.Assert(t, changed != initial)
+	assert.Assert(t, fingerprint("worker") != worker, "a service context changes with the service")
+
+	project.Services["api"].Build.Args["VERSION"] = new("2")
+	assert.Assert(t, fingerprint("api") != changed)
+	changed = fingerprint("api")
+
+	// pulling a base image changes the fingerprint, a base image not pulled
+	// yet leaves none
+	baseImageIDs["alpine"] = "sha256:alpine2"
+	assert.Assert(t, fingerprint("api") != changed)
+	delete(baseImageIDs, "alpine")
+	assert.Equal(t, fingerprint("api"), "")
+}
+
+func TestSkipUnchangedBuilds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient, tested := newTestComposeService(t, mockCtrl, "1.47")
+	tested.proxyConfig = map[string]string{}
+	// the classic builder bases the images on the local ones
+	tested.dockerCli.(*mocks.MockCli).EXPECT().BuildKitEnabled().Return(false, nil)
+
+	dir := writeBuildContext(t, map[string]string{"Dockerfile": "FROM alpine\n"})
+	project := &types.Project{Name: "myproject", Services: types.Services{
+		"api":    {Name: "api", Build: &types.BuildConfig{Context: dir, Dockerfile: "Dockerfile"}},
+		"worker": {Name: "worker", Build: &types.BuildConfig{Context: dir, Dockerfile: "Dockerfile", Target: "worker"}},
+	}}
+	fingerprint, err := newBuildFingerprinter(project, map[string]string{}, api.BuildOptions{}, projectBaseImages(project), map[string]string{"alpine": "sha256:alpine"}).fingerprint("api")
+	assert.NilError(t, err)
+
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "alpine").
+		Return(client.ImageInspectResult{InspectResponse: image.InspectResponse{ID: "sha256:alpine"}}, nil)
+
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "myproject-api").
+		Return(client.ImageInspectResult{InspectResponse: image.InspectResponse{
+			ID: "sha256:api",
+			Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{
+				Labels: map[string]string{api.ImageFingerprintLabel: fingerprint},
+			}},
+		}}, nil)
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "myproject-worker").
+		Return(client.ImageInspectResult{InspectResponse: image.InspectResponse{ID: "sha256:worker"}}, nil)
+
+	serviceToBuild := types.Services{"api": project.Services["api"], "worker": project.Services["worker"]}
+	skipped, err := tested.skipUnchangedBuilds(t.Context(), project, serviceToBuild, api.BuildOptions{}, projectBaseImages(project), nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, skipped, map[string]string{"myproject-api": "sha256:api"})
+
+	// the services still to build get the fingerprint of their inputs
+	_, ok := serviceToBuild["api"]
+	assert.Assert(t, !ok)
+	labels := serviceToBuild["worker"].Build.Labels
+	assert.Assert(t, labels[api.ImageFingerprintLabel] != "")
+	assert.Equal(t, project.Services["worker"].Build.Labels[api.ImageFingerprintLabel], labels[api.ImageFingerprintLabel])
+
+	// pulling the base images forces the build, as does --force
+	skipped, err = tested.skipUnchangedBuilds(t.Context(), project, types.Services{"api": project.Services["api"]}, api.BuildOptions{Pull: true}, projectBaseImages(project), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(skipped), 0)
+	skipped, err = tested.skipUnchangedBuilds(t.Context(), project, types.Services{"api": project.Services["api"]}, api.BuildOptions{Force: true}, projectBaseImages(project), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(skipped), 0)
+}
+
+func TestBuildxDriver(t *testing.T) {
+	inspect := `Name:          default
+Driver:        docker
+
+Nodes:
+Name:             default
+Endpoint:         default
+Status:           running
+`
+	assert.Equal(t, buildxDriver(inspect), "docker")
+	assert.Equal(t, buildxDriver("Name: builder\nDriver: docker-container\n"), "docker-container")
+	assert.Equal(t, buildxDriver(""), "")
+}
@@ -0,0 +1,258 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+	"github.com/moby/buildkit/client"
+	"github.com/opencontainers/go-digest"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// buildRecorder collects what the builders tell about the services they
+// build, to write the report requested by BuildOptions.Report. A nil
+// recorder records nothing.
+type buildRecorder struct {
+	clock   clockwork.Clock
+	started time.Time
+
+	mu       sync.Mutex
+	builder  string
+	services map[string]*serviceBuildRecord // by service name
+	vertices map[digest.Digest]*client.Vertex
+	warnings []*client.VertexWarning
+	err      string
+}
+
+type serviceBuildRecord struct {
+	report  api.ServiceBuildReport
+	started time.Time
+	// first and last hold the time range of the build steps of the service,
+	// when the builder reports them
+	first, last time.Time
+}
+
+func newBuildRecorder(clock clockwork.Clock, options api.BuildOptions) *buildRecorder {
+	if options.Report == "" {
+		return nil
+	}
+	return &buildRecorder{
+		clock:    clock,
+		started:  clock.Now(),
+		services: map[string]*serviceBuildRecord{},
+		vertices: map[digest.Digest]*client.Vertex{},
+	}
+}
+
+// building records the build of a service starts.
+func (r *buildRecorder) building(builder string, service types.ServiceConfig, image string) {
+	if r == nil {
+		return
+	}
+	platforms := service.Build.Platforms
+	if len(platforms) == 0 && service.Platform != "" {
+		platforms = []string{service.Platform}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.builder = builder
+	r.services[service.Name] = &serviceBuildRecord{
+		report: api.ServiceBuildReport{
+			Image:     image,
+			Platforms: platforms,
+		},
+		started: r.clock.Now(),
+	}
+}
+
+// built records the image a service build produced, and the digest the
+// builder reported for it.
+func (r *buildRecorder) built(service, imageID, builtDigest string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.services[service]
+	if !ok {
+		return
+	}
+	record.report.ImageID = imageID
+	record.report.Digest = builtDigest
+	record.report.Duration = r.clock.Since(record.started).Seconds()
+}
+
+// upToDate records the build of a service was skipped.
+func (r *buildRecorder) upToDate(service types.ServiceConfig, image, imageID string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services[service.Name] = &serviceBuildRecord{
+		report: api.ServiceBuildReport{
+			Image:     image,
+			ImageID:   imageID,
+			Platforms: service.Build.Platforms,
+			UpToDate:  true,
+		},
+	}
+}
+
+// failed records the build failed with err: the services whose build started
+// but produced no image are reported as failed.
+func (r *buildRecorder) failed(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err.Error()
+	for _, record := range r.services {
+		if !record.report.UpToDate && record.report.ImageID == "" {
+			record.report.Failed = true
+			record.report.Duration = r.clock.Since(record.started).Seconds()
+		}
+	}
+}
+
+// warn records warnings about the build of a service.
+func (r *buildRecorder) warn(service string, warnings ...string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if record, ok := r.services[service]; ok {
+		record.report.Warnings = append(record.report.Warnings, warnings...)
+	}
+}
+
+// solveStatus records the latest state of the build steps and the warnings
+// BuildKit reports.
+func (r *buildRecorder) solveStatus(status *client.SolveStatus) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, vertex := range status.Vertexes {
+		v := *vertex
+		r.vertices[v.Digest] = &v
+	}
+	r.warnings = append(r.warnings, status.Warnings...)
+}
+
+// attributeSteps accounts the recorded build steps and warnings to the
+// services, as serviceOf tells from the name of a step, "" when it is none
+// of the services.
+func (r *buildRecorder) attributeSteps(serviceOf func(step string) string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, vertex := range r.vertices {
+		record, ok := r.services[serviceOf(vertex.Name)]
+		if !ok || vertex.Completed == nil || vertex.Error != "" {
+			continue
+		}
+		if vertex.Cached {
+			record.report.CachedSteps++
+		} else {
+			record.report.ExecutedSteps++
+		}
+		if vertex.Started != nil && (record.first.IsZero() || vertex.Started.Before(record.first)) {
+			record.first = *vertex.Started
+		}
+		if vertex.Completed.After(record.last) {
+			record.last = *vertex.Completed
+		}
+	}
+	for _, warning := range r.warnings {
+		vertex, ok := r.vertices[warning.Vertex]
+		if !ok {
+			continue
+		}
+		if record, ok := r.services[serviceOf(vertex.Name)]; ok {
+			record.report.Warnings = append(record.report.Warnings, string(warning.Short))
+		}
+	}
+}
+
+// report returns the report of the recorded builds.
+func (r *buildRecorder) report() api.BuildReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := api.BuildReport{
+		Builder:  r.builder,
+		Duration: r.clock.Since(r.started).Seconds(),
+		Services: map[string]api.ServiceBuildReport{},
+		Error:    r.err,
+	}
+	for name, record := range r.services {
+		service := record.report
+		if !record.first.IsZero() && !record.last.IsZero() {
+			// the builder may have built the services concurrently
+			service.Duration = record.last.Sub(record.first).Seconds()
+		}
+		if steps := service.CachedSteps + service.ExecutedSteps; steps > 0 {
+			service.CacheHitRatio = float64(service.CachedSteps) / float64(steps)
+		}
+		report.Services[name] = service
+	}
+	return report
+}
+
+// write writes the report of the recorded builds as JSON to path.
+func (r *buildRecorder) write(path string) error {
+	if r == nil {
+		return nil
+	}
+	content, err := json.MarshalIndent(r.report(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing build report: %w", err)
+	}
+	return nil
+}
+
+// bakeStepTarget returns the bake target a build step belongs to: bake
+// prefixes the name of the steps with their target, as "[target] name" or
+// "[target stage n/m] name", when building more than one.
+func bakeStepTarget(step string) string {
+	step, ok := strings.CutPrefix(step, "[")
+	if !ok {
+		return ""
+	}
+	end := strings.IndexAny(step, " ]")
+	if end < 0 {
+		return ""
+	}
+	return step[:end]
+}
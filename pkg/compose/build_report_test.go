@@ -0,0 +1,151 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+	"github.com/moby/buildkit/client"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestBakeStepTarget(t *testing.T) {
+	assert.Equal(t, bakeStepTarget("[api internal] load build definition from Dockerfile"), "api")
+	assert.Equal(t, bakeStepTarget("[api 2/3] RUN make"), "api")
+	assert.Equal(t, bakeStepTarget("[worker] exporting to image"), "worker")
+	assert.Equal(t, bakeStepTarget("exporting to image"), "")
+}
+
+func TestBuildRecorder(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	path := filepath.Join(t.TempDir(), "report.json")
+	recorder := newBuildRecorder(clock, api.BuildOptions{Report: path})
+	apiService := types.ServiceConfig{Name: "api", Build: &types.BuildConfig{Platforms: []string{"linux/amd64", "linux/arm64"}}}
+	worker := types.ServiceConfig{Name: "worker", Platform: "linux/amd64", Build: &types.BuildConfig{}}
+	recorder.building("bake", apiService, "myproject-api")
+	recorder.building("bake", worker, "myproject-worker")
+	recorder.upToDate(types.ServiceConfig{Name: "db", Build: &types.BuildConfig{}}, "myproject-db", "sha256:db")
+
+	at := func(seconds int) *time.Time {
+		t := clock.Now().Add(time.Duration(seconds) * time.Second)
+		return &t
+	}
+	recorder.solveStatus(&client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: "sha256:1", Name: "[api internal] load build definition from Dockerfile", Started: at(0)},
+			{Digest: "sha256:2", Name: "[api 1/2] FROM alpine", Started: at(1), Completed: at(1), Cached: true},
+			{Digest: "sha256:3", Name: "[worker 1/1] RUN make", Started: at(2), Completed: at(5)},
+		},
+	})
+	recorder.solveStatus(&client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: "sha256:1", Name: "[api internal] load build definition from Dockerfile", Started: at(0), Completed: at(1)},
+			{Digest: "sha256:4", Name: "[api 2/2] RUN make", Started: at(1), Completed: at(4)},
+			{Digest: "sha256:5", Name: "[api 2/2] RUN failed", Started: at(1), Completed: at(4), Error: "exit code 1"},
+		},
+		Warnings: []*client.VertexWarning{
+			{Vertex: "sha256:4", Short: []byte("JSONArgsRecommended: JSON arguments recommended for CMD")},
+		},
+	})
+	clock.Advance(6 * time.Second)
+	recorder.built("api", "sha256:api", "sha256:index")
+	recorder.built("worker", "sha256:worker", "sha256:worker")
+	recorder.warn("worker", "StageNameCasing: Stage name 'Build' should be lowercase")
+
+	services := map[string]string{"api": "api", "worker": "worker"}
+	recorder.attributeSteps(func(step string) string {
+		return services[bakeStepTarget(step)]
+	})
+	assert.NilError(t, recorder.write(path))
+
+	content, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	var report api.BuildReport
+	assert.NilError(t, json.Unmarshal(content, &report))
+	assert.Equal(t, report.Builder, "bake")
+	assert.Equal(t, report.Duration, 6.0)
+	assert.DeepEqual(t, report.Services, map[string]api.ServiceBuildReport{
+		"api": {
+			Image:         "myproject-api",
+			ImageID:       "sha256:api",
+			Digest:        "sha256:index",
+			Platforms:     []string{"linux/amd64", "linux/arm64"},
+			Duration:      4,
+			CachedSteps:   1,
+			ExecutedSteps: 2,
+			CacheHitRatio: 1.0 / 3,
+			Warnings:      []string{"JSONArgsRecommended: JSON arguments recommended for CMD"},
+		},
+		"worker": {
+			Image:         "myproject-worker",
+			ImageID:       "sha256:worker",
+			Digest:        "sha256:worker",
+			Platforms:     []string{"linux/amd64"},
+			Duration:      3,
+			ExecutedSteps: 1,
+			Warnings:      []string{"StageNameCasing: Stage name 'Build' should be lowercase"},
+		},
+		"db": {
+			Image:    "myproject-db",
+			ImageID:  "sha256:db",
+			UpToDate: true,
+		},
+	})
+}
+
+func TestBuildRecorderFailure(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	path := filepath.Join(t.TempDir(), "report.json")
+	recorder := newBuildRecorder(clock, api.BuildOptions{Report: path})
+	recorder.building("classic", types.ServiceConfig{Name: "api", Build: &types.BuildConfig{}}, "myproject-api")
+	recorder.building("classic", types.ServiceConfig{Name: "worker", Build: &types.BuildConfig{}}, "myproject-worker")
+	clock.Advance(2 * time.Second)
+	recorder.built("worker", "sha256:worker", "sha256:worker")
+	recorder.failed(errors.New("exit code 1"))
+	assert.NilError(t, recorder.write(path))
+
+	content, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	var report api.BuildReport
+	assert.NilError(t, json.Unmarshal(content, &report))
+	assert.Equal(t, report.Error, "exit code 1")
+	assert.DeepEqual(t, report.Services["api"], api.ServiceBuildReport{Image: "myproject-api", Failed: true, Duration: 2})
+	assert.Assert(t, !report.Services["worker"].Failed)
+}
+
+func TestForwardBakeStatusRecords(t *testing.T) {
+	recorder := newBuildRecorder(clockwork.NewFakeClock(), api.BuildOptions{Report: "report.json"})
+	ch := make(chan *client.SolveStatus, 1)
+	stream := `{"vertexes":[{"digest":"sha256:1","name":"[api 1/1] FROM alpine","cached":true}]}
+ERROR: failed to build
+`
+	errMessage, err := forwardBakeStatus(strings.NewReader(stream), ch, recorder)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, errMessage, []string{"failed to build\n"})
+	assert.Equal(t, len(ch), 1)
+	assert.Equal(t, recorder.vertices["sha256:1"].Name, "[api 1/1] FROM alpine")
+}
@@ -106,7 +106,7 @@ func Test_addBuildDependencies(t *testing.T) {
 		},
 	}}
 
-	services := addBuildDependencies([]string{"test"}, project)
+	services := addBuildDependencies([]string{"test"}, project, projectBaseImages(project))
 	expected := []string{"test", "foo", "bar", "zot"}
 	slices.Sort(services)
 	slices.Sort(expected)
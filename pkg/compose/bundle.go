@@ -0,0 +1,495 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli/command"
+	"github.com/moby/moby/api/types/jsonstream"
+	"github.com/moby/moby/client"
+	"github.com/moby/sys/atomicwriter"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/docker/compose/v5/internal/oci"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/remote"
+)
+
+// bundleOverrideFile is the compose file a bundle adds to the project, so
+// the project and its images keep their name wherever the bundle is loaded
+const bundleOverrideFile = "bundle.yaml"
+
+func (s *composeService) SaveBundle(ctx context.Context, project *types.Project, options api.BundleSaveOptions) error {
+	return Run(ctx, func(ctx context.Context) error {
+		return s.saveBundle(ctx, project, options)
+	}, "bundle", s.events)
+}
+
+func (s *composeService) saveBundle(ctx context.Context, project *types.Project, options api.BundleSaveOptions) error {
+	if options.Output == "" {
+		if s.stdout().IsTerminal() {
+			return errors.New("output option is required when saving a bundle to terminal")
+		}
+	} else if err := command.ValidateOutputPath(options.Output); err != nil {
+		return fmt.Errorf("failed to save bundle: %w", err)
+	}
+
+	project, err := project.WithProfiles([]string{"*"})
+	if err != nil {
+		return err
+	}
+	var saveOptions []client.ImageSaveOption
+	if options.Platform != "" {
+		platform, err := platforms.Parse(options.Platform)
+		if err != nil {
+			return err
+		}
+		saveOptions = append(saveOptions, client.ImageSaveWithPlatforms(platform))
+		project, err = withBundlePlatform(project, options.Platform)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.ensureImagesExists(ctx, project, &api.BuildOptions{Services: project.ServiceNames()}, false)
+	if err != nil {
+		return err
+	}
+
+	layers, err := s.createLayers(ctx, project, api.PublishOptions{WithEnvironment: true})
+	if err != nil {
+		return err
+	}
+	override, err := bundleOverride(project)
+	if err != nil {
+		return err
+	}
+	layers = append(layers, oci.DescriptorForComposeFile(bundleOverrideFile, override))
+	manifest, blobs, err := oci.ComposeManifest(layers)
+	if err != nil {
+		return err
+	}
+	manifest.Annotations[api.ProjectLabel] = project.Name
+
+	resource := "Project " + project.Name
+	s.events.On(newEvent(resource, api.Working, api.StatusSaving))
+	if !s.dryRun {
+		images, err := s.apiClient().ImageSave(ctx, bundleImages(project), saveOptions...)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = images.Close() }()
+
+		if options.Output == "" {
+			err = writeBundle(s.stdout(), images, manifest, blobs)
+		} else {
+			err = writeBundleFile(options.Output, images, manifest, blobs)
+		}
+		if err != nil {
+			s.events.On(errorEvent(resource, err.Error()))
+			return err
+		}
+	}
+	s.events.On(newEvent(resource, api.Done, api.StatusSaved))
+	return nil
+}
+
+// withBundlePlatform sets the platform of the services of a project, so
+// their images are pulled and built for the platform the bundle is saved for.
+func withBundlePlatform(project *types.Project, platform string) (*types.Project, error) {
+	return project.WithServicesTransform(func(name string, service types.ServiceConfig) (types.ServiceConfig, error) {
+		if service.Platform != "" && service.Platform != platform {
+			return service, fmt.Errorf("service %q runs on platform %s, not %s", name, service.Platform, platform)
+		}
+		service.Platform = platform
+		if service.Build != nil {
+			if len(service.Build.Platforms) > 0 && !slices.Contains(service.Build.Platforms, platform) {
+				return service, fmt.Errorf("service %q build configuration does not support platform: %s", name, platform)
+			}
+			service.Build.Platforms = []string{platform}
+		}
+		return service, nil
+	})
+}
+
+// bundleImages lists the images a project runs: the images of its services,
+// of their pre_start hooks and of their image volumes.
+func bundleImages(project *types.Project) []string {
+	var images []string
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if service.Image != "" || service.Build != nil {
+			images = append(images, api.GetImageNameOrDefault(service, project.Name))
+		}
+		images = append(images, api.GetDependentImages(service, project.Name)...)
+		for _, volume := range service.Volumes {
+			if isLockableImageVolume(project, volume) {
+				images = append(images, volume.Source)
+			}
+		}
+	}
+	slices.Sort(images)
+	return slices.Compact(images)
+}
+
+// bundleOverride returns the compose file naming the project and the images
+// of its services as they are saved, so they depend neither on the directory
+// nor on the environment the bundle is loaded into.
+func bundleOverride(project *types.Project) ([]byte, error) {
+	override := types.Project{
+		Name:     project.Name,
+		Services: types.Services{},
+	}
+	for name, service := range project.Services {
+		if service.Image != "" || service.Build != nil {
+			override.Services[name] = types.ServiceConfig{
+				Image: api.GetImageNameOrDefault(service, project.Name),
+			}
+		}
+	}
+	return override.MarshalYAML()
+}
+
+func writeBundleFile(file string, images io.Reader, manifest v1.Descriptor, blobs []v1.Descriptor) error {
+	writer, err := atomicwriter.New(file, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := writeBundle(writer, images, manifest, blobs); err != nil {
+		// don't leave an incomplete bundle behind
+		_ = writer.Close()
+		_ = os.Remove(file)
+		return err
+	}
+	return writer.Close()
+}
+
+// writeBundle writes the OCI layout of the images saved by the engine, with
+// the blobs of the compose artifact added and its manifest referenced by the
+// layout index.
+func writeBundle(w io.Writer, images io.Reader, manifest v1.Descriptor, blobs []v1.Descriptor) error {
+	tr := tar.NewReader(images)
+	tw := tar.NewWriter(w)
+	written := map[string]bool{}
+	var index *v1.Index
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(header.Name)
+		if name == v1.ImageIndexFile {
+			index = &v1.Index{}
+			if err := json.NewDecoder(tr).Decode(index); err != nil {
+				return fmt.Errorf("reading saved images index: %w", err)
+			}
+			continue
+		}
+		written[name] = true
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	if index == nil {
+		return errors.New("the engine didn't save the images as an OCI layout, saving a bundle requires Docker Engine 25 or later")
+	}
+
+	for _, blob := range blobs {
+		name := path.Join(v1.ImageBlobsDir, blob.Digest.Algorithm().String(), blob.Digest.Encoded())
+		if written[name] {
+			continue
+		}
+		written[name] = true
+		if err := writeTarFile(tw, name, blob.Data); err != nil {
+			return err
+		}
+	}
+	index.Manifests = append(index.Manifests, manifest)
+	content, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, v1.ImageIndexFile, content); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, name string, content []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(content)),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(content)
+	return err
+}
+
+func (s *composeService) LoadBundle(ctx context.Context, options api.BundleLoadOptions) (string, error) {
+	var dir string
+	err := Run(ctx, func(ctx context.Context) error {
+		var err error
+		dir, err = s.loadBundle(ctx, options)
+		return err
+	}, "bundle", s.events)
+	return dir, err
+}
+
+func (s *composeService) loadBundle(ctx context.Context, options api.BundleLoadOptions) (string, error) {
+	if options.Directory != "" {
+		if err := checkBundleDirectory(options.Directory); err != nil {
+			return "", err
+		}
+	}
+
+	var input io.Reader
+	if options.Input == "" {
+		if s.stdin().IsTerminal() {
+			return "", errors.New("input option is required when loading a bundle from terminal")
+		}
+		input = s.stdin()
+	} else {
+		f, err := os.Open(options.Input)
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = f.Close() }()
+		input = f
+	}
+
+	var (
+		manifest v1.Descriptor
+		blobs    bundleBlobs
+	)
+	if s.dryRun {
+		var err error
+		manifest, blobs, err = rewriteBundle(io.Discard, input)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		// the images are loaded while the bundle is read, without the compose
+		// artifact the engine has no use for
+		pr, pw := io.Pipe()
+		rewritten := make(chan error, 1)
+		go func() {
+			var err error
+			manifest, blobs, err = rewriteBundle(pw, input)
+			_ = pw.CloseWithError(err)
+			rewritten <- err
+		}()
+		loadErr := s.loadBundleImages(ctx, pr)
+		_ = pr.CloseWithError(loadErr)
+		if err := <-rewritten; err != nil && !errors.Is(err, io.ErrClosedPipe) {
+			return "", err
+		}
+		if loadErr != nil {
+			return "", loadErr
+		}
+	}
+
+	name := manifest.Annotations[api.ProjectLabel]
+	dir := options.Directory
+	if dir == "" {
+		if name == "" || name != filepath.Base(name) {
+			return "", fmt.Errorf("invalid bundle project name %q", name)
+		}
+		dir = name
+		if err := checkBundleDirectory(dir); err != nil {
+			return "", err
+		}
+	}
+
+	var artifact v1.Manifest
+	content, err := blobs.get(manifest)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(content, &artifact); err != nil {
+		return "", err
+	}
+	if !s.dryRun {
+		if err := remote.WriteComposeArtifact(dir, "bundle", artifact, blobs.get); err != nil {
+			return "", err
+		}
+	}
+	s.events.On(newEvent("Project "+name, api.Done, api.StatusLoaded))
+	return dir, nil
+}
+
+// loadBundleImages loads the images of a bundle into the engine.
+func (s *composeService) loadBundleImages(ctx context.Context, bundle io.Reader) error {
+	response, err := s.apiClient().ImageLoad(ctx, bundle, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Close() }()
+
+	dec := json.NewDecoder(response)
+	for {
+		var jm jsonstream.Message
+		if err := dec.Decode(&jm); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if jm.Error != nil {
+			return errors.New(jm.Error.Message)
+		}
+		if image, ok := strings.CutPrefix(strings.TrimSpace(jm.Stream), "Loaded image: "); ok {
+			s.events.On(newEvent("Image "+image, api.Done, api.StatusLoaded))
+		}
+	}
+}
+
+// maxBundleBlobSize is the size of the largest blob rewriteBundle keeps. The
+// blobs of the compose artifact are small, but which blobs they are is only
+// known once the layout index is read, which may come last.
+const maxBundleBlobSize = 1 << 20
+
+// bundleBlobs are the blobs of a bundle, by digest.
+type bundleBlobs map[digest.Digest][]byte
+
+// get returns the content of a bundle blob.
+func (b bundleBlobs) get(descriptor v1.Descriptor) ([]byte, error) {
+	content, ok := b[descriptor.Digest]
+	if !ok || descriptor.Digest != digest.FromBytes(content) {
+		return nil, fmt.Errorf("bundle content %s is missing or corrupted", descriptor.Digest)
+	}
+	return content, nil
+}
+
+// rewriteBundle copies the OCI layout of a bundle to w, without the compose
+// artifact manifest in the layout index, and returns that manifest with the
+// blobs small enough to belong to the compose artifact.
+func rewriteBundle(w io.Writer, bundle io.Reader) (v1.Descriptor, bundleBlobs, error) {
+	tr := tar.NewReader(bundle)
+	tw := tar.NewWriter(w)
+	var manifest *v1.Descriptor
+	blobs := bundleBlobs{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return v1.Descriptor{}, nil, err
+		}
+		name := path.Clean(header.Name)
+		if name != v1.ImageIndexFile {
+			if err := tw.WriteHeader(header); err != nil {
+				return v1.Descriptor{}, nil, err
+			}
+			d, isBlob := layoutBlobDigest(name)
+			keep := isBlob && header.Size <= maxBundleBlobSize
+			var content bytes.Buffer
+			out := io.Writer(tw)
+			if keep {
+				out = io.MultiWriter(tw, &content)
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				return v1.Descriptor{}, nil, err
+			}
+			if keep {
+				blobs[d] = content.Bytes()
+			}
+			continue
+		}
+
+		var index v1.Index
+		if err := json.NewDecoder(tr).Decode(&index); err != nil {
+			return v1.Descriptor{}, nil, fmt.Errorf("reading bundle index: %w", err)
+		}
+		var manifests []v1.Descriptor
+		for _, descriptor := range index.Manifests {
+			if descriptor.ArtifactType == oci.ComposeProjectArtifactType {
+				manifest = &descriptor
+				continue
+			}
+			manifests = append(manifests, descriptor)
+		}
+		index.Manifests = manifests
+		content, err := json.Marshal(index)
+		if err != nil {
+			return v1.Descriptor{}, nil, err
+		}
+		if err := writeTarFile(tw, header.Name, content); err != nil {
+			return v1.Descriptor{}, nil, err
+		}
+	}
+	if manifest == nil {
+		return v1.Descriptor{}, nil, errors.New("not a compose bundle, the archive has no compose project")
+	}
+	return *manifest, blobs, tw.Close()
+}
+
+// layoutBlobDigest returns the digest of the blob an OCI layout stores at
+// name, if name is under the blobs directory.
+func layoutBlobDigest(name string) (digest.Digest, bool) {
+	rel, ok := strings.CutPrefix(name, v1.ImageBlobsDir+"/")
+	if !ok {
+		return "", false
+	}
+	algorithm, encoded, ok := strings.Cut(rel, "/")
+	if !ok {
+		return "", false
+	}
+	d := digest.NewDigestFromEncoded(digest.Algorithm(algorithm), encoded)
+	return d, d.Validate() == nil
+}
+
+// checkBundleDirectory checks a bundle can be extracted to dir, which must
+// be empty if it exists.
+func checkBundleDirectory(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("cannot extract the bundle to %s, the directory is not empty", dir)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/internal/oci"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestBundleImages(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Services: types.Services{
+			"web": {
+				Name:     "web",
+				Image:    "nginx:1.27",
+				PreStart: []types.ServiceHook{{Image: "busybox"}, {Command: []string{"true"}}},
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeImage, Source: "data:v1", Target: "/data"},
+					{Type: types.VolumeTypeImage, Source: "app", Target: "/app"},
+				},
+			},
+			"app":   {Name: "app", Build: &types.BuildConfig{Context: "."}},
+			"proxy": {Name: "proxy", Image: "nginx:1.27"},
+		},
+	}
+	assert.DeepEqual(t, bundleImages(project), []string{"busybox", "data:v1", "myproject-app", "nginx:1.27"})
+
+	override, err := bundleOverride(project)
+	assert.NilError(t, err)
+	assert.Equal(t, string(override), `name: myproject
+services:
+  app:
+    image: myproject-app
+  proxy:
+    image: nginx:1.27
+  web:
+    image: nginx:1.27
+`)
+}
+
+func TestBundleSaveLoad(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient, tested := newTestComposeService(t, mockCtrl, "1.48")
+
+	envFile := "0123.env"
+	layers := []v1.Descriptor{
+		oci.DescriptorForComposeFile("compose.yaml", []byte("services:\n  web:\n    image: nginx\n    env_file: "+envFile+"\n")),
+		oci.DescriptorForEnvFile(envFile, []byte("FOO=bar\n")),
+		oci.DescriptorForComposeFile(bundleOverrideFile, []byte("name: myproject\n")),
+	}
+	// the content is only read from the layout blobs, not from the
+	// descriptors, where embedding it is optional
+	var referenced []v1.Descriptor
+	for _, layer := range layers {
+		layer.Data = nil
+		referenced = append(referenced, layer)
+	}
+	manifest, blobs, err := oci.ComposeManifest(referenced)
+	assert.NilError(t, err)
+	blobs = append(blobs[:len(blobs)-len(layers)], layers...)
+	manifest.Annotations[api.ProjectLabel] = "myproject"
+	manifest.Data = nil
+
+	var bundle bytes.Buffer
+	assert.NilError(t, writeBundle(&bundle, engineSavedImages(t), manifest, blobs))
+	index := readLayoutIndex(t, bytes.NewReader(bundle.Bytes()))
+	assert.Equal(t, len(index.Manifests), 2)
+	assert.Equal(t, index.Manifests[1].ArtifactType, oci.ComposeProjectArtifactType)
+
+	path := filepath.Join(t.TempDir(), "bundle.tar")
+	assert.NilError(t, os.WriteFile(path, bundle.Bytes(), 0o600))
+
+	var loaded *v1.Index
+	apiClient.EXPECT().ImageLoad(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input io.Reader, _ ...client.ImageLoadOption) (client.ImageLoadResult, error) {
+			loaded = readLayoutIndex(t, input)
+			return io.NopCloser(strings.NewReader(`{"stream":"Loaded image: nginx:latest\n"}`)), nil
+		})
+	dir := filepath.Join(t.TempDir(), "app")
+	extracted, err := tested.LoadBundle(t.Context(), api.BundleLoadOptions{Input: path, Directory: dir})
+	assert.NilError(t, err)
+	assert.Equal(t, extracted, dir)
+	assert.Equal(t, len(loaded.Manifests), 1, "the compose artifact is not loaded into the engine")
+
+	compose, err := os.ReadFile(filepath.Join(dir, "compose.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(compose), "services:\n  web:\n    image: nginx\n    env_file: "+envFile+"\n\n---\nname: myproject\n")
+	env, err := os.ReadFile(filepath.Join(dir, envFile))
+	assert.NilError(t, err)
+	assert.Equal(t, string(env), "FOO=bar\n")
+
+	_, err = tested.LoadBundle(t.Context(), api.BundleLoadOptions{Input: path, Directory: dir})
+	assert.ErrorContains(t, err, "the directory is not empty")
+}
+
+func TestLoadBundleRejectsImageArchive(t *testing.T) {
+	_, _, err := rewriteBundle(io.Discard, engineSavedImages(t))
+	assert.ErrorContains(t, err, "not a compose bundle")
+}
+
+// engineSavedImages returns an OCI layout archive, as the engine saves images
+func engineSavedImages(t *testing.T) io.Reader {
+	t.Helper()
+	image := []byte(`{"schemaVersion":2}`)
+	d := digest.FromBytes(image)
+	index, err := json.Marshal(v1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: v1.MediaTypeImageIndex,
+		Manifests: []v1.Descriptor{{
+			MediaType:   v1.MediaTypeImageManifest,
+			Digest:      d,
+			Size:        int64(len(image)),
+			Annotations: map[string]string{"io.containerd.image.name": "docker.io/library/nginx:latest"},
+		}},
+	})
+	assert.NilError(t, err)
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	assert.NilError(t, writeTarFile(tw, v1.ImageLayoutFile, []byte(`{"imageLayoutVersion":"1.0.0"}`)))
+	assert.NilError(t, writeTarFile(tw, "blobs/sha256/"+d.Encoded(), image))
+	assert.NilError(t, writeTarFile(tw, v1.ImageIndexFile, index))
+	assert.NilError(t, writeTarFile(tw, "manifest.json", []byte(`[]`)))
+	assert.NilError(t, tw.Close())
+	return &archive
+}
+
+func readLayoutIndex(t *testing.T, archive io.Reader) *v1.Index {
+	t.Helper()
+	tr := tar.NewReader(archive)
+	var index *v1.Index
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return index
+		}
+		assert.NilError(t, err)
+		if header.Name == v1.ImageIndexFile {
+			index = &v1.Index{}
+			assert.NilError(t, json.NewDecoder(tr).Decode(index))
+		}
+	}
+}
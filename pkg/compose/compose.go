@@ -215,6 +215,8 @@ type composeService struct {
 	clock          clockwork.Clock
 	maxConcurrency int
 	dryRun         bool
+	// retryPolicies configures the retries of the plan operations, by type
+	retryPolicies map[OperationType]RetryPolicy
 
 	runtimeAPIVersion runtimeVersionCache
 }
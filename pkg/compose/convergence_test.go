@@ -535,8 +535,10 @@ func TestCreateMobyContainer(t *testing.T) {
 			AttachStderr: true,
 			Image:        "bork-test",
 			Labels: map[string]string{
-				"com.docker.compose.config-hash": "8dbce408396f8986266bc5deba0c09cfebac63c95c2238e405c7bee5f1bd84b8",
-				"com.docker.compose.depends_on":  "",
+				"com.docker.compose.config-field-hashes":   `{"networks.a":"da40e338121f","networks.b":"430ee21da927"}`,
+				"com.docker.compose.config-hash":           "8dbce408396f8986266bc5deba0c09cfebac63c95c2238e405c7bee5f1bd84b8",
+				"com.docker.compose.depends_on":            "",
+				"com.docker.compose.immutable-config-hash": "8dbce408396f8986266bc5deba0c09cfebac63c95c2238e405c7bee5f1bd84b8",
 			},
 		},
 		HostConfig: &container.HostConfig{
@@ -85,27 +85,47 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 		return err
 	}
 
-	prepareNetworks(project)
-	externalNetworks, err := s.checkExternalNetworks(ctx, project)
+	// Temporary implementation of use_api_socket until we get actual support inside docker engine
+	project, err = s.useAPISocket(project)
 	if err != nil {
 		return err
 	}
 
-	prepareVolumes(project)
-	externalVolumes, err := s.checkExternalVolumes(ctx, project)
-	if err != nil {
+	if err := s.resumeInterruptedPlan(ctx, project.Name, options.Resume); err != nil {
 		return err
 	}
 
-	// Temporary implementation of use_api_socket until we get actual support inside docker engine
-	project, err = s.useAPISocket(project)
+	observed, plan, err := s.observeAndReconcile(ctx, project, options, s.prompt)
 	if err != nil {
 		return err
 	}
 
+	// Emit "Running" events for containers that are already up-to-date,
+	// matching the previous convergence behavior for progress display.
+	emitRunningEvents(project, observed, plan, s.events)
+
+	return s.executePlan(ctx, project, observed, plan)
+}
+
+// observeAndReconcile snapshots the project resources and computes the plan
+// converging them to the project model. It is shared by create, which then
+// executes the plan, and Plan, which only reports it.
+func (s *composeService) observeAndReconcile(ctx context.Context, project *types.Project, options api.CreateOptions, prompt Prompt) (*ObservedState, *Plan, error) {
+	prepareNetworks(project)
+	externalNetworks, err := s.checkExternalNetworks(ctx, project)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prepareVolumes(project)
+	externalVolumes, err := s.checkExternalVolumes(ctx, project)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	observed, err := s.collectObservedState(ctx, project)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	observed.setResolvedNetworks(externalNetworks, project)
 	observed.setResolvedVolumes(externalVolumes)
@@ -119,16 +139,14 @@ func (s *composeService) create(ctx context.Context, project *types.Project, opt
 			"--remove-orphans flag to clean it up.", observed.orphanNames())
 	}
 
-	plan, err := reconcile(ctx, project, observed, toReconcileOptions(options), s.prompt)
+	reconcileOptions := toReconcileOptions(options)
+	// a dry run reports why containers would be recreated or updated
+	reconcileOptions.Explain = reconcileOptions.Explain || s.dryRun
+	plan, err := reconcile(ctx, project, observed, reconcileOptions, prompt)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-
-	// Emit "Running" events for containers that are already up-to-date,
-	// matching the previous convergence behavior for progress display.
-	emitRunningEvents(project, observed, plan, s.events)
-
-	return s.executePlan(ctx, project, observed, plan)
+	return observed, plan, nil
 }
 
 func prepareNetworks(project *types.Project) {
@@ -262,6 +280,20 @@ func (s *composeService) getCreateConfigs(ctx context.Context,
 		return createConfigs{}, err
 	}
 	labels[api.ConfigHashLabel] = hash
+	immutableHash, err := immutableServiceHash(service)
+	if err != nil {
+		return createConfigs{}, err
+	}
+	labels[api.ImmutableConfigHashLabel] = immutableHash
+	fieldHashes, err := serviceFieldHashes(service)
+	if err != nil {
+		return createConfigs{}, err
+	}
+	fieldHashesLabel, err := json.Marshal(fieldHashes)
+	if err != nil {
+		return createConfigs{}, err
+	}
+	labels[api.ConfigFieldHashesLabel] = string(fieldHashesLabel)
 	if number > 0 {
 		// One-off containers are not indexed
 		labels[api.ContainerNumberLabel] = strconv.Itoa(number)
@@ -1475,6 +1507,19 @@ func (s *composeService) createNetwork(ctx context.Context, n *types.NetworkConf
 }
 
 func (s *composeService) resolveExternalNetwork(ctx context.Context, n *types.NetworkConfig) (string, error) {
+	id, found, err := s.lookupExternalNetwork(ctx, n)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("network %s declared as external, but could not be found", n.Name)
+	}
+	return id, nil
+}
+
+// lookupExternalNetwork returns the ID of the external network n, and whether
+// it was found.
+func (s *composeService) lookupExternalNetwork(ctx context.Context, n *types.NetworkConfig) (string, bool, error) {
 	// NetworkInspect will match on ID prefix, so NetworkList with a name
 	// filter is used to look for an exact match to prevent e.g. a network
 	// named `db` from getting erroneously matched to a network with an ID
@@ -1483,7 +1528,7 @@ func (s *composeService) resolveExternalNetwork(ctx context.Context, n *types.Ne
 		Filters: make(client.Filters).Add("name", n.Name),
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	networks := res.Items
 
@@ -1493,7 +1538,7 @@ func (s *composeService) resolveExternalNetwork(ctx context.Context, n *types.Ne
 		if err == nil {
 			networks = append(networks, network.Summary{Network: sn.Network.Network})
 		} else if !errdefs.IsNotFound(err) {
-			return "", err
+			return "", false, err
 		}
 	}
 
@@ -1506,22 +1551,22 @@ func (s *composeService) resolveExternalNetwork(ctx context.Context, n *types.Ne
 
 	switch len(networks) {
 	case 1:
-		return networks[0].ID, nil
+		return networks[0].ID, true, nil
 	case 0:
 		enabled, err := s.isSwarmEnabled(ctx)
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 		if enabled {
 			// Swarm nodes do not register overlay networks that were
 			// created on a different node unless they're in use.
 			// So we can't preemptively check network exists, but
 			// networkAttach will later fail anyway if network actually doesn't exist
-			return "swarm", nil
+			return "swarm", true, nil
 		}
-		return "", fmt.Errorf("network %s declared as external, but could not be found", n.Name)
+		return "", false, nil
 	default:
-		return "", fmt.Errorf("multiple networks with name %q were found. Use network ID as `name` to avoid ambiguity", n.Name)
+		return "", false, fmt.Errorf("multiple networks with name %q were found. Use network ID as `name` to avoid ambiguity", n.Name)
 	}
 }
 
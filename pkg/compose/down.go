@@ -111,6 +111,13 @@ func (s *composeService) down(ctx context.Context, projectName string, options a
 		return err
 	}
 
+	if len(options.Services) == 0 && !s.dryRun {
+		// the containers a plan execution left being recreated are removed
+		if err := removePlanJournal(projectName); err != nil {
+			return err
+		}
+	}
+
 	ops := s.ensureNetworksDown(ctx, project)
 
 	if options.Images != "" {
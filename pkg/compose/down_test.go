@@ -38,6 +38,10 @@ import (
 )
 
 func TestDown(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	journal := startPlanJournal(strings.ToLower(testProject), recreatePlan(true))
+	assert.NilError(t, journal.done(1))
+
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -95,6 +99,10 @@ func TestDown(t *testing.T) {
 
 	err = tested.Down(t.Context(), strings.ToLower(testProject), compose.DownOptions{})
 	assert.NilError(t, err)
+
+	found, err := journal.load()
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
 }
 
 func TestDownWithGivenServices(t *testing.T) {
@@ -0,0 +1,259 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/client"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// Drift compares the project resources with the model, the same way `up`
+// does to decide what to change, but only reports the differences. Images are
+// not pulled nor built: containers are compared with the images available
+// locally.
+func (s *composeService) Drift(ctx context.Context, project *types.Project, options api.DriftOptions) ([]api.Drift, error) {
+	if err := s.labelLocalImageDigests(ctx, project); err != nil {
+		return nil, err
+	}
+	project, err := s.useAPISocket(project)
+	if err != nil {
+		return nil, err
+	}
+
+	prepareNetworks(project)
+	prepareVolumes(project)
+
+	var drifts []api.Drift
+	externalNetworks := map[string]string{}
+	for _, key := range sortedKeys(project.Networks) {
+		networkConfig := project.Networks[key]
+		if !networkConfig.External {
+			continue
+		}
+		id, found, err := s.lookupExternalNetwork(ctx, &networkConfig)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			drifts = append(drifts, api.Drift{
+				Resource: "network:" + key,
+				Kind:     api.DriftMissing,
+				Details:  fmt.Sprintf("external network %s not found", networkConfig.Name),
+			})
+			continue
+		}
+		externalNetworks[key] = id
+	}
+	externalVolumes := map[string]string{}
+	for _, key := range sortedKeys(project.Volumes) {
+		volume := project.Volumes[key]
+		if !volume.External {
+			continue
+		}
+		_, err := s.apiClient().VolumeInspect(ctx, volume.Name, client.VolumeInspectOptions{})
+		if errdefs.IsNotFound(err) {
+			drifts = append(drifts, api.Drift{
+				Resource: "volume:" + key,
+				Kind:     api.DriftMissing,
+				Details:  fmt.Sprintf("external volume %s not found", volume.Name),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		externalVolumes[key] = volume.Name
+	}
+
+	observed, err := s.collectObservedState(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	observed.setResolvedNetworks(externalNetworks, project)
+	observed.setResolvedVolumes(externalVolumes)
+
+	r := newReconciler(project, observed, ReconcileOptions{
+		Recreate:             api.RecreateDiverged,
+		RecreateDependencies: api.RecreateDiverged,
+		Explain:              true,
+	}, AlwaysOkPrompt())
+	detected, err := r.detectDrift(options.Services)
+	if err != nil {
+		return nil, err
+	}
+	return append(drifts, detected...), nil
+}
+
+// detectDrift reports the differences between the observed state and the
+// project model, relying on the same checks as the reconciliation. Unlike
+// reconcile, it does not cascade the recreation of a service to the services
+// sharing its namespaces: only resources which diverged are reported.
+func (r *reconciler) detectDrift(services []string) ([]api.Drift, error) {
+	var drifts []api.Drift
+	for _, key := range sortedKeys(r.project.Networks) {
+		desired := r.project.Networks[key]
+		if desired.External {
+			continue
+		}
+		observed, exists := r.resolvedNetworks[key]
+		if !exists {
+			drifts = append(drifts, api.Drift{
+				Resource: "network:" + key,
+				Kind:     api.DriftMissing,
+				Details:  fmt.Sprintf("network %s not found", desired.Name),
+			})
+			continue
+		}
+		expected, err := NetworkHash(&desired)
+		if err != nil {
+			return nil, err
+		}
+		if observed.ConfigHash != "" && observed.ConfigHash != expected {
+			drifts = append(drifts, api.Drift{
+				Resource: "network:" + key,
+				Kind:     api.DriftDiverged,
+				Details:  fmt.Sprintf("network %s configuration changed", observed.Name),
+			})
+		}
+	}
+
+	for _, key := range sortedKeys(r.project.Volumes) {
+		desired := r.project.Volumes[key]
+		if desired.External {
+			continue
+		}
+		observed, exists := r.resolvedVolumes[key]
+		if !exists {
+			drifts = append(drifts, api.Drift{
+				Resource: "volume:" + key,
+				Kind:     api.DriftMissing,
+				Details:  fmt.Sprintf("volume %s not found", desired.Name),
+			})
+			continue
+		}
+		expected, err := VolumeHash(desired)
+		if err != nil {
+			return nil, err
+		}
+		if observed.ConfigHash != "" && observed.ConfigHash != expected {
+			drifts = append(drifts, api.Drift{
+				Resource: "volume:" + key,
+				Kind:     api.DriftDiverged,
+				Details:  fmt.Sprintf("volume %s configuration changed", observed.Name),
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(r.project.Services) {
+		service := r.project.Services[name]
+		if service.Provider != nil || (len(services) > 0 && !slices.Contains(services, name)) {
+			continue
+		}
+		serviceDrifts, err := r.detectServiceDrift(service)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, serviceDrifts...)
+	}
+
+	// orphans belong to no declared service, a service filter excludes them
+	if len(services) > 0 {
+		return drifts, nil
+	}
+	for _, oc := range r.observed.Orphans {
+		drifts = append(drifts, api.Drift{
+			Resource: "container:" + oc.Name,
+			Kind:     api.DriftOrphan,
+			Details:  fmt.Sprintf("service %s is not declared", oc.Summary.Labels[api.ServiceLabel]),
+		})
+	}
+	return drifts, nil
+}
+
+func (r *reconciler) detectServiceDrift(service types.ServiceConfig) ([]api.Drift, error) {
+	hashes, err := expectedServiceHashes(service, r.observedContainersByService)
+	if err != nil {
+		return nil, err
+	}
+	hashes.fields, err = serviceFieldHashes(withResolvedServiceRefs(service, r.observedContainersByService))
+	if err != nil {
+		return nil, err
+	}
+	scale, err := getScale(service)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []api.Drift
+	containers := slices.Clone(r.observed.Containers[service.Name])
+	if len(containers) != scale {
+		drifts = append(drifts, api.Drift{
+			Resource: "service:" + service.Name,
+			Kind:     api.DriftReplicas,
+			Details:  fmt.Sprintf("%d containers, %d replicas declared", len(containers), scale),
+		})
+	}
+
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Number < containers[j].Number })
+	for _, oc := range containers {
+		if r.mustRecreate(service, hashes, false, oc, api.RecreateDiverged) {
+			drifts = append(drifts, api.Drift{
+				Resource: "container:" + oc.Name,
+				Kind:     api.DriftOutdated,
+				Details:  strings.Join(r.explainRecreate(service, hashes, false, oc, api.RecreateDiverged), ", "),
+			})
+			continue
+		}
+		if oc.Resources == nil {
+			continue
+		}
+		if expected := expectedUpdatableResources(service); !expected.matches(*oc.Resources) {
+			drifts = append(drifts, api.Drift{
+				Resource: "container:" + oc.Name,
+				Kind:     api.DriftResources,
+				Details:  strings.Join(explainUpdate(expected, *oc.Resources), ", "),
+			})
+		}
+		attachments, err := r.planNetworkAttachments(service, &oc, nil)
+		if err != nil {
+			return nil, err
+		}
+		var causes []string
+		for _, node := range attachments {
+			if !slices.Contains(causes, node.Operation.Cause) {
+				causes = append(causes, node.Operation.Cause)
+			}
+		}
+		if len(causes) > 0 {
+			drifts = append(drifts, api.Drift{
+				Resource: "container:" + oc.Name,
+				Kind:     api.DriftNetworks,
+				Details:  strings.Join(causes, ", "),
+			})
+		}
+	}
+	return drifts, nil
+}
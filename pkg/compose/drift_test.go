@@ -0,0 +1,157 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func detectDrift(t *testing.T, project *types.Project, observed *ObservedState, services ...string) []api.Drift {
+	t.Helper()
+	r := newReconciler(project, observed, ReconcileOptions{
+		Recreate:             api.RecreateDiverged,
+		RecreateDependencies: api.RecreateDiverged,
+		Explain:              true,
+	}, noPrompt)
+	drifts, err := r.detectDrift(services)
+	assert.NilError(t, err)
+	return drifts
+}
+
+func TestDetectDrift_UpToDate(t *testing.T) {
+	svc := types.ServiceConfig{Name: "web", Image: "nginx", Scale: intPtr(1)}
+	project, observed := explainProject(t, svc, svc, true)
+	project.Networks = types.Networks{"default": {Name: "myproject_default"}}
+	network := project.Networks["default"]
+	networkHash, err := NetworkHash(&network)
+	assert.NilError(t, err)
+	observed.Networks["default"] = []ObservedNetwork{{ID: "n1", Name: "myproject_default", ConfigHash: networkHash}}
+
+	assert.Assert(t, detectDrift(t, project, observed) == nil)
+}
+
+func TestDetectDrift_Services(t *testing.T) {
+	created := types.ServiceConfig{
+		Name: "web", Image: "nginx", Scale: intPtr(1), MemLimit: 1 << 30,
+		Environment: types.NewMappingWithEquals([]string{"FOO=1"}),
+	}
+
+	t.Run("outdated", func(t *testing.T) {
+		svc := created
+		svc.Environment = types.NewMappingWithEquals([]string{"FOO=2"})
+		project, observed := explainProject(t, svc, created, true)
+		observed.Containers["web"][0].Name = "myproject-web-1"
+
+		assert.DeepEqual(t, detectDrift(t, project, observed), []api.Drift{
+			{Resource: "container:myproject-web-1", Kind: api.DriftOutdated, Details: "environment changed"},
+		})
+	})
+
+	t.Run("resources", func(t *testing.T) {
+		svc := created
+		svc.MemLimit = 2 << 30
+		project, observed := explainProject(t, svc, created, true)
+		observed.Containers["web"][0].Name = "myproject-web-1"
+
+		assert.DeepEqual(t, detectDrift(t, project, observed), []api.Drift{
+			{Resource: "container:myproject-web-1", Kind: api.DriftResources, Details: "mem_limit changed"},
+		})
+	})
+
+	t.Run("networks", func(t *testing.T) {
+		created := types.ServiceConfig{
+			Name: "web", Image: "nginx", Scale: intPtr(1),
+			Networks: map[string]*types.ServiceNetworkConfig{"front": nil, "back": nil},
+		}
+		svc := created
+		svc.Networks = map[string]*types.ServiceNetworkConfig{"front": {Aliases: []string{"www"}}, "monitoring": nil}
+		project, observed := networksProject(t, svc, created, map[string]observedEndpoint{
+			"myproject_front": webEndpoint("n1"),
+			"myproject_back":  webEndpoint("n2"),
+		})
+		observed.Containers["web"][0].Name = "myproject-web-1"
+
+		assert.DeepEqual(t, detectDrift(t, project, observed), []api.Drift{
+			{
+				Resource: "container:myproject-web-1", Kind: api.DriftNetworks,
+				Details: "network front settings changed, network monitoring added, network back removed",
+			},
+		})
+	})
+
+	t.Run("replicas", func(t *testing.T) {
+		svc := created
+		svc.Scale = intPtr(3)
+		project, observed := explainProject(t, svc, created, true)
+
+		assert.DeepEqual(t, detectDrift(t, project, observed), []api.Drift{
+			{Resource: "service:web", Kind: api.DriftReplicas, Details: "1 containers, 3 replicas declared"},
+		})
+	})
+
+	t.Run("filtered out", func(t *testing.T) {
+		svc := created
+		svc.Scale = intPtr(3)
+		project, observed := explainProject(t, svc, created, true)
+		project.Services["db"] = types.ServiceConfig{Name: "db", Image: "postgres"}
+
+		assert.DeepEqual(t, detectDrift(t, project, observed, "db"), []api.Drift{
+			{Resource: "service:db", Kind: api.DriftReplicas, Details: "0 containers, 1 replicas declared"},
+		})
+	})
+}
+
+func TestDetectDrift_NetworksVolumesAndOrphans(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Networks: types.Networks{
+			"front": {Name: "myproject_front"},
+			"back":  {Name: "myproject_back"},
+			"ext":   {Name: "shared", External: true},
+		},
+		Volumes: types.Volumes{
+			"data": {Name: "myproject_data"},
+		},
+	}
+	observed := emptyObservedState("myproject")
+	observed.Networks["front"] = []ObservedNetwork{{ID: "n1", Name: "myproject_front", ConfigHash: "stale"}}
+	observed.Orphans = []ObservedContainer{{
+		ID: "c1", Name: "myproject-old-1", State: container.StateRunning,
+		Summary: container.Summary{Labels: map[string]string{api.ServiceLabel: "old"}},
+	}}
+
+	assert.DeepEqual(t, detectDrift(t, project, observed), []api.Drift{
+		{Resource: "network:back", Kind: api.DriftMissing, Details: "network myproject_back not found"},
+		{Resource: "network:front", Kind: api.DriftDiverged, Details: "network myproject_front configuration changed"},
+		{Resource: "volume:data", Kind: api.DriftMissing, Details: "volume myproject_data not found"},
+		{Resource: "container:myproject-old-1", Kind: api.DriftOrphan, Details: "service old is not declared"},
+	})
+
+	// orphans are not reported for selected services
+	project.Services = types.Services{"web": {Name: "web", Image: "nginx", Scale: intPtr(0)}}
+	assert.DeepEqual(t, detectDrift(t, project, observed, "web"), []api.Drift{
+		{Resource: "network:back", Kind: api.DriftMissing, Details: "network myproject_back not found"},
+		{Resource: "network:front", Kind: api.DriftDiverged, Details: "network myproject_front configuration changed"},
+		{Resource: "volume:data", Kind: api.DriftMissing, Details: "volume myproject_data not found"},
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -38,6 +39,23 @@ type planExecutor struct {
 	// round-trip per create.
 	containersMu        sync.Mutex
 	containersByService map[string]Containers
+
+	// replaced holds the containers replaced by the plan which are kept until
+	// their replacement is healthy (rollback on failure), by container ID.
+	// They are restored if the plan fails before they are removed.
+	replacedMu sync.Mutex
+	replaced   map[string]replacedContainer
+
+	// journal records the completed nodes so that an interrupted execution
+	// can be resumed, nil if the plan is not journaled.
+	journal *planJournal
+}
+
+// replacedContainer is a container kept by the plan until its replacement,
+// created by the createNodeID node, is healthy.
+type replacedContainer struct {
+	container    container.Summary
+	createNodeID int
 }
 
 // reconciliationContext holds results produced by completed nodes so that downstream
@@ -69,7 +87,11 @@ func (pc *reconciliationContext) get(nodeID int) operationResult {
 // while respecting dependency edges. It emits progress events and handles
 // group-based event aggregation for composite operations like recreate.
 func (s *composeService) executePlan(ctx context.Context, project *types.Project, observed *ObservedState, plan *Plan) error {
-	return s.newPlanExecutor(project, observed).run(ctx, plan)
+	exec := s.newPlanExecutor(project, observed)
+	if !s.dryRun {
+		exec.journal = startPlanJournal(project.Name, plan)
+	}
+	return exec.run(ctx, plan)
 }
 
 // newPlanExecutor constructs a planExecutor seeded from the observed state.
@@ -81,6 +103,7 @@ func (s *composeService) newPlanExecutor(project *types.Project, observed *Obser
 		project:             project,
 		pctx:                &reconciliationContext{results: map[int]operationResult{}},
 		containersByService: observed.containersByService(),
+		replaced:            map[string]replacedContainer{},
 	}
 }
 
@@ -102,36 +125,55 @@ func (exec *planExecutor) run(ctx context.Context, plan *Plan) error {
 	groups := exec.buildGroupTracker(plan)
 	events := exec.compose.events
 
-	eg, ctx := errgroup.WithContext(ctx)
+	eg, egCtx := errgroup.WithContext(ctx)
 	for _, node := range plan.Nodes {
 		eg.Go(func() error {
 			// Wait for all dependencies
 			for _, dep := range node.DependsOn {
 				select {
 				case <-done[dep.ID]:
-				case <-ctx.Done():
-					return ctx.Err()
+				case <-egCtx.Done():
+					return egCtx.Err()
 				}
 			}
 
 			// Emit group start event if this is the first node of a group
 			groups.onNodeStart(node, events)
 
-			err := exec.executeNode(ctx, node)
+			err := exec.executeNodeWithRetry(egCtx, node, groups)
 
-			if err == nil {
-				// Emit group done event if this is the last node of a group
-				groups.onNodeDone(node, events)
-			} else if ctx.Err() == nil {
-				groups.onNodeError(node, events, err)
+			if err != nil {
+				if egCtx.Err() == nil {
+					groups.onNodeError(node, events, err)
+				}
+				// dependents are not released: they return once the
+				// failure cancels egCtx
+				return err
 			}
-
+			exec.journalNodeDone(node.ID)
+			// Emit group done event if this is the last node of a group
+			groups.onNodeDone(node, events)
 			close(done[node.ID])
-			return err
+			return nil
 		})
 	}
 
-	return eg.Wait()
+	err := eg.Wait()
+	if err == nil {
+		exec.closeJournal()
+		return nil
+	}
+	// Roll back once all nodes returned, so that no operation of the plan is
+	// still running against the containers being restored.
+	if len(exec.replaced) > 0 {
+		if rbErr := exec.rollback(context.WithoutCancel(ctx)); rbErr != nil {
+			return fmt.Errorf("%w, rollback failed: %w", err, rbErr)
+		}
+		exec.closeJournal()
+		return fmt.Errorf("%w, rolled back", err)
+	}
+	exec.settleJournal(ctx)
+	return err
 }
 
 // executeNode dispatches a single plan node to the appropriate API call.
@@ -153,13 +195,17 @@ func (exec *planExecutor) executeNode(ctx context.Context, node *PlanNode) error
 	case OpCreateContainer:
 		return exec.execCreateContainer(ctx, node)
 	case OpStartContainer:
-		return exec.execStartContainer(ctx, op)
+		return exec.execStartContainer(ctx, node)
 	case OpStopContainer:
 		return exec.execStopContainer(ctx, op)
 	case OpRemoveContainer:
 		return exec.execRemoveContainer(ctx, op)
 	case OpRenameContainer:
 		return exec.execRenameContainer(ctx, node)
+	case OpWaitContainer:
+		return exec.execWaitContainer(ctx, node)
+	case OpUpdateContainer:
+		return exec.execUpdateContainer(ctx, op)
 	case OpRunProvider:
 		return exec.compose.runPlugin(ctx, exec.project, *op.Service, "up")
 	default:
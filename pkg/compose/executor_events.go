@@ -17,6 +17,8 @@
 package compose
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/docker/compose/v5/pkg/api"
@@ -31,6 +33,7 @@ type groupTracker struct {
 
 type groupState struct {
 	eventName string // e.g. "Container myproject-web-1"
+	details   string // why the container is recreated, with ReconcileOptions.Explain
 	total     int    // total nodes in this group
 	started   int    // nodes that have started
 	done      int    // nodes that have completed
@@ -50,6 +53,9 @@ func (exec *planExecutor) buildGroupTracker(plan *Plan) *groupTracker {
 		if gt.groups[node.Group].eventName == "" && node.Operation.Container != nil {
 			gt.groups[node.Group].eventName = getContainerProgressName(*node.Operation.Container)
 		}
+		if len(node.Operation.Divergences) > 0 {
+			gt.groups[node.Group].details = strings.Join(node.Operation.Divergences, ", ")
+		}
 	}
 	// Fallback for groups where no node had a Container (shouldn't happen for recreate)
 	for name, gs := range gt.groups {
@@ -71,7 +77,7 @@ func (gt *groupTracker) onNodeStart(node *PlanNode, events api.EventProcessor) {
 	gs := gt.groups[node.Group]
 	gs.started++
 	if gs.started == 1 {
-		events.On(newEvent(gs.eventName, api.Working, "Recreate"))
+		events.On(newEvent(gs.eventName, api.Working, "Recreate", gs.details))
 	}
 }
 
@@ -104,6 +110,34 @@ func (gt *groupTracker) onNodeError(node *PlanNode, events api.EventProcessor, e
 	})
 }
 
+func (gt *groupTracker) onNodeRetry(node *PlanNode, events api.EventProcessor, attempt, maxAttempts int, err error) {
+	id := nodeEventName(node)
+	if node.Group != "" {
+		gt.mu.Lock()
+		id = gt.groups[node.Group].eventName
+		gt.mu.Unlock()
+	}
+	events.On(newEvent(id, api.Working, fmt.Sprintf("Retrying (%d/%d)", attempt, maxAttempts), err.Error()))
+}
+
+// nodeEventName returns the name of the resource an ungrouped node reports
+// progress for, as emitStartEvent does.
+func nodeEventName(node *PlanNode) string {
+	op := node.Operation
+	switch op.Type {
+	case OpCreateContainer:
+		return "Container " + op.Name
+	case OpCreateNetwork, OpRemoveNetwork:
+		return "Network " + op.Name
+	case OpCreateVolume, OpRemoveVolume:
+		return "Volume " + op.Name
+	}
+	if op.Container != nil {
+		return getContainerProgressName(*op.Container)
+	}
+	return op.ResourceID
+}
+
 // emitStartEvent emits the appropriate Working event for an ungrouped node.
 func emitStartEvent(node *PlanNode, events api.EventProcessor) {
 	op := node.Operation
@@ -117,6 +151,8 @@ func emitStartEvent(node *PlanNode, events api.EventProcessor) {
 		events.On(stoppingEvent(getContainerProgressName(*op.Container)))
 	case OpRemoveContainer:
 		events.On(removingEvent(getContainerProgressName(*op.Container)))
+	case OpUpdateContainer:
+		events.On(newEvent(getContainerProgressName(*op.Container), api.Working, api.StatusUpdating, strings.Join(op.Divergences, ", ")))
 	case OpCreateNetwork:
 		events.On(creatingEvent("Network " + op.Name))
 	case OpRemoveNetwork:
@@ -141,6 +177,8 @@ func emitDoneEvent(node *PlanNode, events api.EventProcessor) {
 		events.On(stoppedEvent(getContainerProgressName(*op.Container)))
 	case OpRemoveContainer:
 		events.On(removedEvent(getContainerProgressName(*op.Container)))
+	case OpUpdateContainer:
+		events.On(newEvent(getContainerProgressName(*op.Container), api.Done, api.StatusUpdated))
 	case OpCreateNetwork:
 		events.On(createdEvent("Network " + op.Name))
 	case OpRemoveNetwork:
@@ -18,8 +18,10 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/moby/moby/api/types/container"
@@ -58,7 +60,8 @@ func (exec *planExecutor) execDisconnectNetwork(ctx context.Context, op Operatio
 
 func (exec *planExecutor) execConnectNetwork(ctx context.Context, op Operation) error {
 	_, err := exec.compose.apiClient().NetworkConnect(ctx, op.Name, client.NetworkConnectOptions{
-		Container: op.Container.ID,
+		Container:      op.Container.ID,
+		EndpointConfig: op.Endpoint,
 	})
 	return err
 }
@@ -124,10 +127,26 @@ func (exec *planExecutor) execCreateContainer(ctx context.Context, node *PlanNod
 	exec.containersMu.Lock()
 	exec.containersByService[op.Service.Name] = append(exec.containersByService[op.Service.Name], ctr)
 	exec.containersMu.Unlock()
+
+	if op.Replaces != nil {
+		exec.replacedMu.Lock()
+		exec.replaced[op.Replaces.ID] = replacedContainer{container: *op.Replaces, createNodeID: node.ID}
+		exec.replacedMu.Unlock()
+	}
 	return nil
 }
 
-func (exec *planExecutor) execStartContainer(ctx context.Context, op Operation) error {
+func (exec *planExecutor) execStartContainer(ctx context.Context, node *PlanNode) error {
+	op := node.Operation
+	if op.CreateNodeID != 0 {
+		// Replacement created by the plan (rolling update): start it as `up`
+		// does, with its secrets, configs and post_start hooks.
+		ctr, err := exec.createdContainer(node)
+		if err != nil {
+			return err
+		}
+		return exec.compose.startServiceContainer(ctx, exec.project, *op.Service, ctr, nil)
+	}
 	startMx.Lock()
 	defer startMx.Unlock()
 	_, err := exec.compose.apiClient().ContainerStart(ctx, op.Container.ID, client.ContainerStartOptions{})
@@ -158,6 +177,24 @@ func (exec *planExecutor) execRemoveContainer(ctx context.Context, op Operation)
 		func(c container.Summary) bool { return c.ID == op.Container.ID },
 	)
 	exec.containersMu.Unlock()
+
+	// The replacement is healthy, the replaced container can't be restored
+	exec.replacedMu.Lock()
+	delete(exec.replaced, op.Container.ID)
+	exec.replacedMu.Unlock()
+	return nil
+}
+
+// execUpdateContainer updates the resources and restart policy of a live
+// container, which keeps running.
+func (exec *planExecutor) execUpdateContainer(ctx context.Context, op Operation) error {
+	res, err := exec.compose.apiClient().ContainerUpdate(ctx, op.Container.ID, expectedUpdatableResources(*op.Service).updateOptions())
+	if err != nil {
+		return err
+	}
+	for _, warning := range res.Warnings {
+		logrus.Warn(warning)
+	}
 	return nil
 }
 
@@ -175,3 +212,131 @@ func (exec *planExecutor) execRenameContainer(ctx context.Context, node *PlanNod
 	})
 	return err
 }
+
+// createdContainer returns the container created by the node referenced by
+// node.Operation.CreateNodeID.
+func (exec *planExecutor) createdContainer(node *PlanNode) (container.Summary, error) {
+	op := node.Operation
+	createdID := exec.pctx.get(op.CreateNodeID).ContainerID
+	if createdID == "" {
+		return container.Summary{}, fmt.Errorf("internal: node #%d: create node #%d returned empty ID", node.ID, op.CreateNodeID)
+	}
+	exec.containersMu.Lock()
+	defer exec.containersMu.Unlock()
+	for _, ctr := range exec.containersByService[op.Service.Name] {
+		if ctr.ID == createdID {
+			return ctr, nil
+		}
+	}
+	return container.Summary{}, fmt.Errorf("internal: node #%d: container %s not found", node.ID, createdID)
+}
+
+// --- Rolling update operations ---
+
+// execWaitContainer waits for the replacement container of a rolling update to
+// become healthy, or running if it has no healthcheck, and to stay so for
+// op.Monitor. It then waits op.Delay before the next batch.
+func (exec *planExecutor) execWaitContainer(ctx context.Context, node *PlanNode) error {
+	op := node.Operation
+	ctr, err := exec.createdContainer(node)
+	if err != nil {
+		return err
+	}
+	if err := exec.waitReplicaHealthy(ctx, ctr, op.Monitor); err != nil {
+		switch op.FailureAction {
+		case updateFailureContinue:
+			logrus.Warnf("rolling update of service %q: %v, continuing", op.Service.Name, err)
+		case updateFailureRollback:
+			return fmt.Errorf("service %q: %w", op.Service.Name, err)
+		default:
+			return fmt.Errorf("rolling update of service %q paused: %w", op.Service.Name, err)
+		}
+	}
+	if op.Delay > 0 {
+		timer := time.NewTimer(op.Delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// waitReplicaHealthy polls ctr until it has been healthy for monitor. Unlike
+// isServiceHealthy, a restarting container is a failure: a replacement stuck
+// in a restart loop must not block the rolling update forever.
+func (exec *planExecutor) waitReplicaHealthy(ctx context.Context, ctr container.Summary, monitor time.Duration) error {
+	name := getCanonicalContainerName(ctr)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	var healthySince time.Time
+	for {
+		res, err := exec.compose.apiClient().ContainerInspect(ctx, ctr.ID, client.ContainerInspectOptions{})
+		if err != nil {
+			return err
+		}
+		healthy := false
+		switch state := res.Container.State; {
+		case state == nil:
+		case state.Status == container.StateExited || state.Status == container.StateDead:
+			return fmt.Errorf("container %s exited (%d)", name, state.ExitCode)
+		case state.Restarting:
+			return fmt.Errorf("container %s is restarting", name)
+		case state.Health != nil && state.Health.Status == container.Unhealthy:
+			return fmt.Errorf("container %s is unhealthy", name)
+		case state.Health != nil && state.Health.Status != container.NoHealthcheck:
+			healthy = state.Health.Status == container.Healthy
+		default:
+			healthy = state.Running
+		}
+
+		switch {
+		case !healthy:
+			healthySince = time.Time{}
+		case healthySince.IsZero():
+			healthySince = time.Now()
+		}
+		if healthy && time.Since(healthySince) >= monitor {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// rollback restores the containers replaced by the plan and not removed yet:
+// their replacements are removed, and they are started again if they were
+// running.
+func (exec *planExecutor) rollback(ctx context.Context) error {
+	var errs []error
+	for _, id := range sortedKeys(exec.replaced) {
+		replaced := exec.replaced[id]
+		name := getContainerProgressName(replaced.container)
+		exec.compose.events.On(newEvent(name, api.Working, "Rolling back"))
+		if createdID := exec.pctx.get(replaced.createNodeID).ContainerID; createdID != "" {
+			_, err := exec.compose.apiClient().ContainerRemove(ctx, createdID, client.ContainerRemoveOptions{Force: true})
+			if err != nil && !errdefs.IsNotFound(err) {
+				errs = append(errs, err)
+				exec.compose.events.On(errorEvent(name, err.Error()))
+				continue
+			}
+		}
+		if replaced.container.State == container.StateRunning {
+			startMx.Lock()
+			_, err := exec.compose.apiClient().ContainerStart(ctx, id, client.ContainerStartOptions{})
+			startMx.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				exec.compose.events.On(errorEvent(name, err.Error()))
+				continue
+			}
+		}
+		exec.compose.events.On(newEvent(name, api.Done, "Rolled back"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"sort"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// explainRecreate lists every reason oc must be recreated, see mustRecreate.
+func (r *reconciler) explainRecreate(expected types.ServiceConfig, hashes expectedHashes, parentRecreated bool, oc ObservedContainer, policy string) []string {
+	return r.recreateCauses(expected, hashes, parentRecreated, oc, policy, true)
+}
+
+// configChanges lists the fields of the configuration which changed since oc
+// was created. Without explain, or if oc doesn't record its field hashes, the
+// configuration is only reported as changed.
+func configChanges(expected types.ServiceConfig, hashes expectedHashes, oc ObservedContainer, explain bool) []string {
+	if !explain || hashes.fields == nil || oc.FieldHashes == nil {
+		return []string{"configuration changed"}
+	}
+	if diff := diffFieldHashes(hashes.fields, oc.FieldHashes); len(diff) > 0 {
+		return diff
+	}
+	if len(expected.Environment) > 0 {
+		// every field is hashed but the environment values
+		return []string{"environment changed"}
+	}
+	return []string{"configuration changed"}
+}
+
+// diffFieldHashes lists the fields which differ between the expected and
+// the recorded field hashes (see serviceFieldHashes), sorted by field name.
+// It returns nil if either is missing.
+func diffFieldHashes(expected, recorded map[string]string) []string {
+	if expected == nil || recorded == nil {
+		return nil
+	}
+	var diff []string
+	for field, hash := range expected {
+		old, ok := recorded[field]
+		switch {
+		case !ok:
+			diff = append(diff, field+" added")
+		case old != hash:
+			diff = append(diff, field+" changed")
+		}
+	}
+	for field := range recorded {
+		if _, ok := expected[field]; !ok {
+			diff = append(diff, field+" removed")
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// explainUpdate lists the settings of a container updated in place which
+// differ from the expected ones, named after the service attributes.
+func explainUpdate(expected, live updatableResources) []string {
+	var divergences []string
+	for _, field := range []struct {
+		name           string
+		expected, live any
+	}{
+		{"cpus", expected.NanoCPUs, live.NanoCPUs},
+		{"cpu_shares", expected.CPUShares, live.CPUShares},
+		{"cpu_period", expected.CPUPeriod, live.CPUPeriod},
+		{"cpu_quota", expected.CPUQuota, live.CPUQuota},
+		{"cpuset", expected.CpusetCpus, live.CpusetCpus},
+		{"mem_limit", expected.Memory, live.Memory},
+		{"mem_reservation", expected.MemoryReservation, live.MemoryReservation},
+		{"pids_limit", expected.PidsLimit, live.PidsLimit},
+		{"restart", expected.RestartPolicy, live.RestartPolicy},
+	} {
+		if field.expected != field.live {
+			divergences = append(divergences, field.name+" changed")
+		}
+	}
+	if expected.MemorySwap != 0 && expected.MemorySwap != live.MemorySwap {
+		divergences = append(divergences, "memswap_limit changed")
+	}
+	return divergences
+}
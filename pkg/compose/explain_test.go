@@ -0,0 +1,158 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestServiceFieldHashes(t *testing.T) {
+	svc := types.ServiceConfig{
+		Name:        "web",
+		Image:       "nginx",
+		Scale:       intPtr(2),
+		Labels:      types.Labels{"FOO": "1", "BAR": "1"},
+		Environment: types.NewMappingWithEquals([]string{"FOO=1", "BAR"}),
+	}
+	hashes, err := serviceFieldHashes(svc)
+	assert.NilError(t, err)
+	assert.Equal(t, len(hashes), 4)
+	for _, field := range []string{"image", "labels.FOO", "labels.BAR", "environment"} {
+		_, ok := hashes[field]
+		assert.Assert(t, ok, field)
+	}
+
+	svc.Labels = types.Labels{"FOO": "2", "BAR": "1"}
+	changed, err := serviceFieldHashes(svc)
+	assert.NilError(t, err)
+	assert.Assert(t, changed["labels.FOO"] != hashes["labels.FOO"])
+	assert.Equal(t, changed["labels.BAR"], hashes["labels.BAR"])
+
+	// environment values are not hashed, only the variable names
+	svc.Environment = types.NewMappingWithEquals([]string{"FOO=2", "BAR"})
+	changed, err = serviceFieldHashes(svc)
+	assert.NilError(t, err)
+	assert.Equal(t, changed["environment"], hashes["environment"])
+	svc.Environment = types.NewMappingWithEquals([]string{"FOO=1", "BAZ"})
+	changed, err = serviceFieldHashes(svc)
+	assert.NilError(t, err)
+	assert.Assert(t, changed["environment"] != hashes["environment"])
+}
+
+func TestDiffFieldHashes(t *testing.T) {
+	diff := diffFieldHashes(
+		map[string]string{"image": "a", "environment.FOO": "b", "environment.BAZ": "c"},
+		map[string]string{"image": "a", "environment.FOO": "x", "environment.BAR": "d"},
+	)
+	assert.DeepEqual(t, diff, []string{"environment.BAR removed", "environment.BAZ added", "environment.FOO changed"})
+
+	assert.Assert(t, diffFieldHashes(map[string]string{"image": "a"}, nil) == nil)
+}
+
+// explainProject returns a project running service web, whose single
+// container was created from created.
+func explainProject(t *testing.T, svc, created types.ServiceConfig, recordFields bool) (*types.Project, *ObservedState) {
+	t.Helper()
+	var fields map[string]string
+	if recordFields {
+		var err error
+		fields, err = serviceFieldHashes(created)
+		assert.NilError(t, err)
+	}
+	project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(created))
+	observed.Containers["web"][0].FieldHashes = fields
+	return project, observed
+}
+
+func TestReconcileExplainRecreate(t *testing.T) {
+	created := types.ServiceConfig{
+		Name: "web", Image: "nginx", Scale: intPtr(1),
+		Environment: types.NewMappingWithEquals([]string{"FOO=1", "BAR=1"}),
+	}
+	svc := created
+	svc.Environment = types.NewMappingWithEquals([]string{"FOO=2", "BAZ=1"})
+	svc.CustomLabels = types.Labels{api.ImageDigestLabel: "sha256:new"}
+	project, observed := explainProject(t, svc, created, true)
+
+	opts := defaultReconcileOptions()
+	opts.Explain = true
+	plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.Nodes[0].Operation.Type, OpCreateContainer)
+	assert.DeepEqual(t, plan.Nodes[0].Operation.Divergences, []string{
+		"environment changed",
+		"image digest changed",
+	})
+
+	// a value changed: the environment is the only field not hashed per key
+	svc.Environment = types.NewMappingWithEquals([]string{"FOO=2", "BAR=1"})
+	svc.CustomLabels = nil
+	project, observed = explainProject(t, svc, created, true)
+	plan, err = reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, plan.Nodes[0].Operation.Divergences, []string{"environment changed"})
+
+	// divergences are only computed on demand
+	plan, err = reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.Nodes[0].Operation.Divergences == nil)
+}
+
+func TestReconcileExplainRecreateWithoutFieldHashes(t *testing.T) {
+	created := types.ServiceConfig{Name: "web", Image: "nginx", Scale: intPtr(1)}
+	svc := created
+	svc.Image = "nginx:latest"
+	project, observed := explainProject(t, svc, created, false)
+
+	opts := defaultReconcileOptions()
+	opts.Explain = true
+	plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, plan.Nodes[0].Operation.Divergences, []string{"configuration changed"})
+}
+
+func TestReconcileExplainForceRecreate(t *testing.T) {
+	created := types.ServiceConfig{Name: "web", Image: "nginx", Scale: intPtr(1)}
+	project, observed := explainProject(t, created, created, true)
+
+	opts := defaultReconcileOptions()
+	opts.Explain = true
+	opts.Recreate = api.RecreateForce
+	plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, plan.Nodes[0].Operation.Divergences, []string{"recreation forced"})
+}
+
+func TestReconcileExplainUpdate(t *testing.T) {
+	created := types.ServiceConfig{Name: "web", Image: "nginx", Scale: intPtr(1), MemLimit: 1 << 30}
+	svc := created
+	svc.MemLimit = 2 << 30
+	svc.Restart = types.RestartPolicyAlways
+	project, observed := explainProject(t, svc, created, true)
+
+	opts := defaultReconcileOptions()
+	opts.Explain = true
+	plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.Nodes[0].Operation.Type, OpUpdateContainer)
+	assert.DeepEqual(t, plan.Nodes[0].Operation.Divergences, []string{"mem_limit changed", "restart changed"})
+}
@@ -18,6 +18,9 @@ package compose
 
 import (
 	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/opencontainers/go-digest"
@@ -25,6 +28,16 @@ import (
 
 // ServiceHash computes the configuration hash for a service.
 func ServiceHash(o types.ServiceConfig) (string, error) {
+	bytes, err := json.Marshal(hashedServiceConfig(o))
+	if err != nil {
+		return "", err
+	}
+	return digest.SHA256.FromBytes(bytes).Encoded(), nil
+}
+
+// hashedServiceConfig returns the part of the service configuration
+// ServiceHash covers.
+func hashedServiceConfig(o types.ServiceConfig) types.ServiceConfig {
 	// remove the Build config when generating the service hash
 	o.Build = nil
 	o.PullPolicy = ""
@@ -36,12 +49,109 @@ func ServiceHash(o types.ServiceConfig) (string, error) {
 	}
 	o.DependsOn = nil
 	o.Profiles = nil
+	return o
+}
 
-	bytes, err := json.Marshal(o)
+// serviceFieldHashes computes a hash per field of the configuration covered
+// by ServiceHash, so that a diverged service can be diffed against the
+// configuration a container was created from without storing it. Fields
+// holding a mapping (labels, deploy, ...) get a hash per key, named
+// "field.key". The environment is the exception: its values may be secrets,
+// which a short hash per value would expose to brute force, so only the
+// names of its variables are hashed.
+func serviceFieldHashes(o types.ServiceConfig) (map[string]string, error) {
+	bytes, err := json.Marshal(hashedServiceConfig(o))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return digest.SHA256.FromBytes(bytes).Encoded(), nil
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bytes, &fields); err != nil {
+		return nil, err
+	}
+	hashes := map[string]string{}
+	for name, value := range fields {
+		var mapping map[string]json.RawMessage
+		if json.Unmarshal(value, &mapping) != nil {
+			hashes[name] = shortHash(value)
+			continue
+		}
+		if name == "environment" {
+			names, err := json.Marshal(slices.Sorted(maps.Keys(mapping)))
+			if err != nil {
+				return nil, err
+			}
+			hashes[name] = shortHash(names)
+			continue
+		}
+		for key, v := range mapping {
+			hashes[name+"."+key] = shortHash(v)
+		}
+	}
+	return hashes, nil
+}
+
+// shortHash keeps the label recording serviceFieldHashes compact: it only
+// has to tell values apart, not to identify them.
+func shortHash(b []byte) string {
+	return digest.SHA256.FromBytes(b).Encoded()[:12]
+}
+
+// immutableServiceHash computes the configuration hash for a service, leaving
+// out the fields which can be updated on a live container (see
+// updatableResources and planNetworkAttachments): containers whose immutable
+// hash still matches can be updated in place rather than recreated.
+func immutableServiceHash(o types.ServiceConfig) (string, error) {
+	o.Networks = immutableEndpointSettings(o.Networks)
+	o.CPUS = 0
+	o.CPUShares = 0
+	o.CPUPeriod = 0
+	o.CPUQuota = 0
+	o.CPUSet = ""
+	o.MemLimit = 0
+	o.MemReservation = 0
+	o.MemSwapLimit = 0
+	o.PidsLimit = 0
+	o.Restart = ""
+	if o.Deploy != nil {
+		deploy := *o.Deploy
+		deploy.RestartPolicy = nil
+		if limits := deploy.Resources.Limits; limits != nil {
+			l := *limits
+			l.NanoCPUs, l.MemoryBytes, l.Pids = 0, 0, 0
+			deploy.Resources.Limits = &l
+		}
+		if reservations := deploy.Resources.Reservations; reservations != nil {
+			r := *reservations
+			r.MemoryBytes = 0
+			deploy.Resources.Reservations = &r
+		}
+		o.Deploy = &deploy
+	}
+	return ServiceHash(o)
+}
+
+// immutableEndpointSettings returns the service networks settings which can't
+// be reconciled on a live container. Attachments, aliases and static addresses
+// are left out, see planNetworkAttachments: networks which only set those are
+// dropped, so that attaching a container to them, or detaching it, doesn't
+// change the hash.
+func immutableEndpointSettings(networks map[string]*types.ServiceNetworkConfig) map[string]*types.ServiceNetworkConfig {
+	var settings map[string]*types.ServiceNetworkConfig
+	for key, config := range networks {
+		if config == nil {
+			continue
+		}
+		c := *config
+		c.Aliases, c.Ipv4Address, c.Ipv6Address, c.Extensions = nil, "", "", nil
+		if reflect.DeepEqual(c, types.ServiceNetworkConfig{}) {
+			continue
+		}
+		if settings == nil {
+			settings = map[string]*types.ServiceNetworkConfig{}
+		}
+		settings[key] = &c
+	}
+	return settings
 }
 
 // NetworkHash computes the configuration hash for a network.
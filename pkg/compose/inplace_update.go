@@ -0,0 +1,190 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// updatableResources holds the container settings the engine can update on a
+// live container, without restarting it: cpus, cpu_shares, cpu_period,
+// cpu_quota, cpuset, mem_limit, mem_reservation, memswap_limit, pids_limit,
+// restart, and their deploy.resources / deploy.restart_policy counterparts.
+// immutableServiceHash leaves the corresponding service fields out.
+type updatableResources struct {
+	NanoCPUs          int64
+	CPUShares         int64
+	CPUPeriod         int64
+	CPUQuota          int64
+	CpusetCpus        string
+	Memory            int64
+	MemoryReservation int64
+	MemorySwap        int64
+	PidsLimit         int64
+	RestartPolicy     container.RestartPolicy
+}
+
+// expectedUpdatableResources returns the updatable settings a container
+// created for service gets, see getCreateConfigs.
+func expectedUpdatableResources(service types.ServiceConfig) updatableResources {
+	return newUpdatableResources(getDeployResources(service), getRestartPolicy(service))
+}
+
+// observedUpdatableResources returns the updatable settings of an inspected
+// container.
+func observedUpdatableResources(hostConfig *container.HostConfig) *updatableResources {
+	if hostConfig == nil {
+		return nil
+	}
+	u := newUpdatableResources(hostConfig.Resources, hostConfig.RestartPolicy)
+	return &u
+}
+
+func newUpdatableResources(resources container.Resources, restart container.RestartPolicy) updatableResources {
+	u := updatableResources{
+		NanoCPUs:          resources.NanoCPUs,
+		CPUShares:         resources.CPUShares,
+		CPUPeriod:         resources.CPUPeriod,
+		CPUQuota:          resources.CPUQuota,
+		CpusetCpus:        resources.CpusetCpus,
+		Memory:            resources.Memory,
+		MemoryReservation: resources.MemoryReservation,
+		MemorySwap:        resources.MemorySwap,
+		RestartPolicy:     restart,
+	}
+	// unlimited is reported either as nil, 0 or -1
+	if resources.PidsLimit != nil && *resources.PidsLimit > 0 {
+		u.PidsLimit = *resources.PidsLimit
+	}
+	if u.RestartPolicy.Name == "" {
+		u.RestartPolicy.Name = container.RestartPolicyDisabled
+	}
+	return u
+}
+
+// matches reports whether the live settings of a container match u. Without
+// memswap_limit, the engine defaults the swap limit to twice the memory limit
+// at create time, so it is not compared.
+func (u updatableResources) matches(live updatableResources) bool {
+	if u.MemorySwap == 0 {
+		live.MemorySwap = 0
+	}
+	return u == live
+}
+
+// updatableFrom reports whether a container with the live settings can be
+// updated to u. The engine ignores zero values on update, so a limit can be
+// changed but not removed in place.
+func (u updatableResources) updatableFrom(live updatableResources) bool {
+	if u.MemorySwap == 0 {
+		// the swap limit is kept as is, and must not become lower than
+		// the memory limit
+		if live.MemorySwap > 0 && u.Memory > live.MemorySwap {
+			return false
+		}
+		live.MemorySwap = 0
+	}
+	for _, field := range [][2]int64{
+		{u.NanoCPUs, live.NanoCPUs},
+		{u.CPUShares, live.CPUShares},
+		{u.CPUPeriod, live.CPUPeriod},
+		{u.CPUQuota, live.CPUQuota},
+		{u.Memory, live.Memory},
+		{u.MemoryReservation, live.MemoryReservation},
+		{u.MemorySwap, live.MemorySwap},
+		{u.PidsLimit, live.PidsLimit},
+	} {
+		if field[0] != field[1] && field[0] == 0 {
+			return false
+		}
+	}
+	return u.CpusetCpus == live.CpusetCpus || u.CpusetCpus != ""
+}
+
+// updateOptions returns the options of the ContainerUpdate call applying u.
+func (u updatableResources) updateOptions() client.ContainerUpdateOptions {
+	var pidsLimit *int64
+	if u.PidsLimit != 0 {
+		pidsLimit = &u.PidsLimit
+	}
+	restart := u.RestartPolicy
+	return client.ContainerUpdateOptions{
+		Resources: &container.Resources{
+			NanoCPUs:          u.NanoCPUs,
+			CPUShares:         u.CPUShares,
+			CPUPeriod:         u.CPUPeriod,
+			CPUQuota:          u.CPUQuota,
+			CpusetCpus:        u.CpusetCpus,
+			Memory:            u.Memory,
+			MemoryReservation: u.MemoryReservation,
+			MemorySwap:        u.MemorySwap,
+			PidsLimit:         pidsLimit,
+		},
+		RestartPolicy: &restart,
+	}
+}
+
+// expectedHashes holds the configuration hashes a service's containers are
+// compared to, precomputed once per service by reconcileService.
+type expectedHashes struct {
+	config    string            // com.docker.compose.config-hash
+	immutable string            // com.docker.compose.immutable-config-hash
+	fields    map[string]string // com.docker.compose.config-field-hashes, only with ReconcileOptions.Explain
+}
+
+// updatableInPlace reports whether oc, whose configuration hash diverged, only
+// differs from expected by settings which can be updated on the live
+// container. Containers created before the immutable hash label was
+// introduced, or which were not inspected, are recreated.
+func updatableInPlace(expected types.ServiceConfig, hashes expectedHashes, oc ObservedContainer) bool {
+	if oc.ImmutableConfigHash == "" || oc.ImmutableConfigHash != hashes.immutable || oc.Resources == nil {
+		return false
+	}
+	return expectedUpdatableResources(expected).updatableFrom(*oc.Resources)
+}
+
+// planUpdateContainer plans the in-place update of oc if its live settings do
+// not match the expected ones, and returns the planned node (nil if none).
+// Labels can't be updated: a container updated in place keeps the
+// configuration hash it was created with, so live settings are compared
+// whether the hash matches or not.
+func (r *reconciler) planUpdateContainer(service types.ServiceConfig, oc *ObservedContainer, deps []*PlanNode) *PlanNode {
+	if oc.Resources == nil {
+		return nil
+	}
+	expected := expectedUpdatableResources(service)
+	if expected.matches(*oc.Resources) || !expected.updatableFrom(*oc.Resources) {
+		return nil
+	}
+	var divergences []string
+	if r.options.Explain {
+		divergences = explainUpdate(expected, *oc.Resources)
+	}
+	serviceCopy := service // copy for pointer stability
+	return r.plan.addNode(Operation{
+		Type:        OpUpdateContainer,
+		ResourceID:  fmt.Sprintf("service:%s:%d", service.Name, oc.Number),
+		Cause:       "resources changed",
+		Divergences: divergences,
+		Service:     &serviceCopy,
+		Container:   &oc.Summary,
+	}, "", deps...)
+}
@@ -0,0 +1,219 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestImmutableServiceHash(t *testing.T) {
+	base := types.ServiceConfig{Name: "db", Image: "postgres", MemLimit: 1 << 30}
+	hash := func(svc types.ServiceConfig) string {
+		h, err := immutableServiceHash(svc)
+		assert.NilError(t, err)
+		return h
+	}
+
+	updated := base
+	updated.MemLimit = 2 << 30
+	updated.CPUS = 1.5
+	updated.PidsLimit = 100
+	updated.Restart = types.RestartPolicyAlways
+	updated.Networks = map[string]*types.ServiceNetworkConfig{"front": {Aliases: []string{"www"}}}
+	updated.Deploy = &types.DeployConfig{
+		Resources: types.Resources{Limits: &types.Resource{MemoryBytes: 1 << 30}},
+	}
+	assert.Equal(t, hash(updated), hash(types.ServiceConfig{
+		Name: "db", Image: "postgres",
+		Deploy: &types.DeployConfig{Resources: types.Resources{Limits: &types.Resource{}}},
+	}))
+	assert.Assert(t, mustServiceHash(t, updated) != mustServiceHash(t, base))
+
+	changed := base
+	changed.Image = "postgres:17"
+	assert.Assert(t, hash(changed) != hash(base))
+}
+
+// inPlaceProject returns a project running service web, whose single
+// container was created from created and is observed with the live resources.
+func inPlaceProject(t *testing.T, svc, created types.ServiceConfig, live updatableResources) (*types.Project, *ObservedState) {
+	t.Helper()
+	hash := mustServiceHash(t, created)
+	immutable, err := immutableServiceHash(created)
+	assert.NilError(t, err)
+	project := &types.Project{
+		Name:     "myproject",
+		Services: types.Services{"web": svc},
+	}
+	observed := emptyObservedState("myproject")
+	observed.Containers["web"] = []ObservedContainer{{
+		ID: "c1aabbccddee", Number: 1, State: container.StateRunning,
+		ConfigHash: hash, ImmutableConfigHash: immutable, Resources: &live,
+		Summary: container.Summary{
+			ID: "c1aabbccddee", State: container.StateRunning, Names: []string{"/myproject-web-1"},
+			Labels: map[string]string{
+				api.ServiceLabel: "web", api.ContainerNumberLabel: "1",
+				api.ConfigHashLabel: hash, api.ImmutableConfigHashLabel: immutable,
+			},
+		},
+	}}
+	return project, observed
+}
+
+func TestReconcileContainers_ResourcesUpdatedInPlace(t *testing.T) {
+	created := types.ServiceConfig{Name: "web", Scale: intPtr(1), MemLimit: 1 << 30}
+	svc := created
+	svc.MemLimit = 2 << 30
+	svc.Restart = types.RestartPolicyUnlessStopped
+	project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(created))
+
+	plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, UpdateContainer, resources changed
+`)+"\n")
+}
+
+func TestReconcileContainers_UpdatedInPlaceUpToDate(t *testing.T) {
+	// the container was updated in place: it still carries the hash of the
+	// configuration it was created with, but its live resources match
+	created := types.ServiceConfig{Name: "web", Scale: intPtr(1), MemLimit: 1 << 30}
+	svc := created
+	svc.MemLimit = 2 << 30
+	project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(svc))
+
+	plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.IsEmpty())
+
+	// reverting to the original configuration is applied in place too
+	project.Services["web"] = created
+	plan, err = reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, UpdateContainer, resources changed
+`)+"\n")
+}
+
+func TestReconcileContainers_ResourcesNotUpdatableInPlace(t *testing.T) {
+	recreated := strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[2] -> #3 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[3] -> #4 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+`) + "\n"
+
+	t.Run("limit removed", func(t *testing.T) {
+		created := types.ServiceConfig{Name: "web", Scale: intPtr(1), MemLimit: 1 << 30}
+		svc := created
+		svc.MemLimit = 0
+		project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(created))
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), recreated)
+	})
+
+	t.Run("memory above swap limit", func(t *testing.T) {
+		created := types.ServiceConfig{Name: "web", Scale: intPtr(1), MemLimit: 1 << 30}
+		live := expectedUpdatableResources(created)
+		live.MemorySwap = 2 << 30 // engine default
+		svc := created
+		svc.MemLimit = 4 << 30
+		project, observed := inPlaceProject(t, svc, created, live)
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), recreated)
+	})
+
+	t.Run("immutable field changed", func(t *testing.T) {
+		created := types.ServiceConfig{Name: "web", Scale: intPtr(1), MemLimit: 1 << 30}
+		svc := created
+		svc.MemLimit = 2 << 30
+		svc.Environment = types.NewMappingWithEquals([]string{"FOO=bar"})
+		project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(created))
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), recreated)
+	})
+
+	t.Run("no immutable hash label", func(t *testing.T) {
+		created := types.ServiceConfig{Name: "web", Scale: intPtr(1), MemLimit: 1 << 30}
+		svc := created
+		svc.MemLimit = 2 << 30
+		project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(created))
+		observed.Containers["web"][0].ImmutableConfigHash = ""
+		observed.Containers["web"][0].Resources = nil
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), recreated)
+	})
+}
+
+func TestReconcileContainers_NoRecreateSkipsUpdate(t *testing.T) {
+	created := types.ServiceConfig{Name: "web", Scale: intPtr(1), MemLimit: 1 << 30}
+	svc := created
+	svc.MemLimit = 2 << 30
+	project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(created))
+
+	opts := defaultReconcileOptions()
+	opts.Recreate = api.RecreateNever
+	plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+	assert.Assert(t, plan.IsEmpty())
+}
+
+func TestExecutePlanUpdateContainer(t *testing.T) {
+	svc, apiClient := newTestService(t)
+
+	service := types.ServiceConfig{Name: "web", MemLimit: 2 << 30, CPUS: 0.5, PidsLimit: 100, Restart: types.RestartPolicyAlways}
+	ctr := container.Summary{ID: "c1", Names: []string{"/test-web-1"}}
+
+	apiClient.EXPECT().ContainerUpdate(gomock.Any(), "c1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, opts client.ContainerUpdateOptions) (client.ContainerUpdateResult, error) {
+			assert.Equal(t, opts.Resources.Memory, int64(2<<30))
+			assert.Equal(t, opts.Resources.NanoCPUs, int64(5e8))
+			assert.Equal(t, *opts.Resources.PidsLimit, int64(100))
+			assert.Equal(t, opts.RestartPolicy.Name, container.RestartPolicyAlways)
+			return client.ContainerUpdateResult{}, nil
+		})
+
+	plan := &Plan{}
+	plan.addNode(Operation{
+		Type:       OpUpdateContainer,
+		ResourceID: "service:web:1",
+		Cause:      "resources changed",
+		Service:    &service,
+		Container:  &ctr,
+	}, "")
+
+	err := svc.executePlan(t.Context(), &types.Project{Name: "test"}, emptyObservedState("test"), plan)
+	assert.NilError(t, err)
+}
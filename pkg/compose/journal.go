@@ -0,0 +1,352 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/internal/locker"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// planJournal records the progress of a plan execution in a per-project state
+// file, so that the recreations left half done by an interrupted execution
+// can be resumed (see resumeInterruptedPlan). Only recreations are journaled,
+// once their replacement container is created: other operations leave the
+// project in a state the reconciliation converges from.
+type planJournal struct {
+	file *locker.StateFile
+	mu   sync.Mutex
+	// planned holds the recreate groups of the plan not journaled yet, by ID
+	// of the node creating the replacement container.
+	planned map[int]journalGroup
+	state   journalState
+}
+
+type journalState struct {
+	Groups []journalGroup `json:"groups"`
+	// Completed lists the IDs of the plan nodes which completed.
+	Completed []int `json:"completed"`
+}
+
+// journalGroup describes the recreation of a container: the replacement is
+// created under a temporary name, then the replaced container is removed and
+// the replacement renamed.
+type journalGroup struct {
+	Name       string `json:"name"`
+	Replaced   string `json:"replaced"`
+	Running    bool   `json:"running"`
+	TmpName    string `json:"tmp_name"`
+	FinalName  string `json:"final_name"`
+	RemoveNode int    `json:"remove_node"`
+	RenameNode int    `json:"rename_node"`
+}
+
+func openPlanJournal(projectName string) (*planJournal, error) {
+	file, err := locker.NewStateFile(projectName, "journal.json")
+	if err != nil {
+		return nil, err
+	}
+	return &planJournal{file: file}, nil
+}
+
+// start collects the recreate groups of the plan. It returns false if the plan
+// has none, in which case it is not journaled. Nothing is written until the
+// replacement container of a group is created.
+func (j *planJournal) start(plan *Plan) (bool, error) {
+	groups := map[string]*journalGroup{}
+	createNodes := map[string]int{}
+	for _, node := range plan.Nodes {
+		if !strings.HasPrefix(node.Group, "recreate:") {
+			continue
+		}
+		g, ok := groups[node.Group]
+		if !ok {
+			g = &journalGroup{Name: node.Group}
+			groups[node.Group] = g
+		}
+		op := node.Operation
+		switch op.Type {
+		case OpCreateContainer:
+			g.TmpName = op.Name
+		case OpRemoveContainer:
+			g.Replaced = op.Container.ID
+			g.Running = op.Container.State == container.StateRunning
+			g.RemoveNode = node.ID
+		case OpRenameContainer:
+			g.FinalName = op.Name
+			g.RenameNode = node.ID
+			createNodes[node.Group] = op.CreateNodeID
+		}
+	}
+	j.planned = map[int]journalGroup{}
+	for name, g := range groups {
+		if createNode, ok := createNodes[name]; ok {
+			j.planned[createNode] = *g
+		}
+	}
+	return len(j.planned) > 0, nil
+}
+
+// done records the completion of a plan node. The completion of the node
+// creating a replacement container journals its recreate group.
+func (j *planJournal) done(nodeID int) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.state.Completed = append(j.state.Completed, nodeID)
+	if g, ok := j.planned[nodeID]; ok {
+		j.state.Groups = append(j.state.Groups, g)
+		delete(j.planned, nodeID)
+	}
+	if len(j.state.Groups) == 0 {
+		return nil
+	}
+	return j.save()
+}
+
+func (j *planJournal) save() error {
+	data, err := json.Marshal(j.state)
+	if err != nil {
+		return err
+	}
+	return j.file.Write(data)
+}
+
+// load reads the journal left by a previous execution. It returns false if
+// there is none.
+func (j *planJournal) load() (bool, error) {
+	data, err := j.file.Read()
+	if err != nil || data == nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, &j.state); err != nil {
+		return false, fmt.Errorf("invalid plan journal: %w", err)
+	}
+	return true, nil
+}
+
+// pending returns the journaled recreations which did not complete.
+func (j *planJournal) pending() []journalGroup {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var groups []journalGroup
+	for _, g := range j.state.Groups {
+		if !slices.Contains(j.state.Completed, g.RenameNode) {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// removed tells whether the recreation removed the replaced container, which
+// is then only available as the replacement left under its temporary name.
+func (j *planJournal) removed(g journalGroup) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return slices.Contains(j.state.Completed, g.RemoveNode)
+}
+
+// keep replaces the journaled recreations with groups, removing the journal
+// if there are none left.
+func (j *planJournal) keep(groups []journalGroup) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.state.Groups = groups
+	if len(groups) == 0 {
+		return j.file.Remove()
+	}
+	return j.save()
+}
+
+func (j *planJournal) remove() error {
+	return j.file.Remove()
+}
+
+// removePlanJournal removes the journal of the project, once the containers
+// it refers to are removed.
+func removePlanJournal(projectName string) error {
+	journal, err := openPlanJournal(projectName)
+	if err != nil {
+		return err
+	}
+	return journal.remove()
+}
+
+// planInterruptedError reports a journal left by a plan execution which did
+// not complete, blocking new executions until it is resumed.
+type planInterruptedError struct {
+	project string
+}
+
+func (e *planInterruptedError) Error() string {
+	return fmt.Sprintf("a previous execution of the plan for project %q was interrupted", e.project)
+}
+
+// resumeInterruptedPlan recovers from a plan execution which did not complete,
+// leaving temporary containers behind: recreations which already removed the
+// replaced container are completed by renaming the replacement, the others are
+// reverted by removing the replacement and restarting the replaced container.
+// The reconciliation which follows then recreates them again as needed.
+// Without resume, only the reverts are applied: a journal with recreations to
+// complete is an error.
+func (s *composeService) resumeInterruptedPlan(ctx context.Context, projectName string, resume bool) error {
+	if s.dryRun {
+		return nil
+	}
+	journal, err := openPlanJournal(projectName)
+	if err != nil {
+		return err
+	}
+	found, err := journal.load()
+	if err != nil || !found {
+		return err
+	}
+	pending := journal.pending()
+	if !resume && slices.ContainsFunc(pending, journal.removed) {
+		return fmt.Errorf("%w, run `up --resume` to complete the containers it left being recreated", &planInterruptedError{project: projectName})
+	}
+	for _, g := range pending {
+		if err := s.resumeRecreate(ctx, g, "resumed"); err != nil {
+			return err
+		}
+	}
+	return journal.remove()
+}
+
+func (s *composeService) resumeRecreate(ctx context.Context, g journalGroup, details string) error {
+	name := "Container " + g.FinalName
+	replaced, err := s.apiClient().ContainerInspect(ctx, g.Replaced, client.ContainerInspectOptions{})
+	if errdefs.IsNotFound(err) {
+		// the replaced container is gone, complete the recreation
+		_, err := s.apiClient().ContainerRename(ctx, g.TmpName, client.ContainerRenameOptions{NewName: g.FinalName})
+		if errdefs.IsNotFound(err) {
+			// renamed, or never created: the reconciliation takes it from there
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.events.On(newEvent(name, api.Done, "Recreated", details))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	reverted := false
+	_, err = s.apiClient().ContainerRemove(ctx, g.TmpName, client.ContainerRemoveOptions{Force: true})
+	switch {
+	case err == nil:
+		reverted = true
+	case !errdefs.IsNotFound(err):
+		s.events.On(errorEvent(name, err.Error()))
+		return err
+	}
+	if g.Running && !replaced.Container.State.Running {
+		startMx.Lock()
+		_, err = s.apiClient().ContainerStart(ctx, g.Replaced, client.ContainerStartOptions{})
+		startMx.Unlock()
+		if err != nil {
+			s.events.On(errorEvent(name, err.Error()))
+			return err
+		}
+		reverted = true
+	}
+	if reverted {
+		s.events.On(newEvent(name, api.Done, "Reverted", details))
+	}
+	return nil
+}
+
+// startPlanJournal journals the execution of plan, or returns nil if it has no
+// recreation to journal. Journaling is best effort: failing to write the
+// journal does not fail the plan.
+func startPlanJournal(projectName string, plan *Plan) *planJournal {
+	journal, err := openPlanJournal(projectName)
+	if err == nil {
+		var journaled bool
+		journaled, err = journal.start(plan)
+		if err == nil && !journaled {
+			return nil
+		}
+	}
+	if err != nil {
+		logrus.Warnf("failed to write plan journal: %v", err)
+		return nil
+	}
+	return journal
+}
+
+// journalNodeDone records the completion of a node, if the plan is journaled.
+func (exec *planExecutor) journalNodeDone(nodeID int) {
+	if exec.journal == nil {
+		return
+	}
+	if err := exec.journal.done(nodeID); err != nil {
+		logrus.Warnf("failed to update plan journal: %v", err)
+	}
+}
+
+// closeJournal removes the journal once the plan completed or was rolled
+// back: there is nothing to resume.
+func (exec *planExecutor) closeJournal() {
+	if exec.journal == nil {
+		return
+	}
+	if err := exec.journal.remove(); err != nil {
+		logrus.Warnf("failed to remove plan journal: %v", err)
+	}
+}
+
+// settleJournal resolves the recreations a failed plan left half done, once
+// all its nodes returned: the replacement is removed if the replaced container
+// is still there, and renamed otherwise. Only an interrupted execution leaves
+// the recreations which removed the replaced container in the journal, for
+// `up --resume` to complete them.
+func (exec *planExecutor) settleJournal(ctx context.Context) {
+	if exec.journal == nil {
+		return
+	}
+	interrupted := ctx.Err() != nil
+	var kept []journalGroup
+	for _, g := range exec.journal.pending() {
+		if interrupted && exec.journal.removed(g) {
+			kept = append(kept, g)
+			continue
+		}
+		if err := exec.compose.resumeRecreate(context.WithoutCancel(ctx), g, ""); err != nil {
+			logrus.Warnf("failed to recover container %s: %v", g.FinalName, err)
+			kept = append(kept, g)
+		}
+	}
+	if err := exec.journal.keep(kept); err != nil {
+		logrus.Warnf("failed to update plan journal: %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/mocks"
+)
+
+// recreatePlan returns the recreation of web:1, from the creation of its
+// replacement tmp-web-1 if withCreate is set, else from the removal of the
+// replaced container.
+func recreatePlan(withCreate bool) *Plan {
+	service := types.ServiceConfig{Name: "web"}
+	old := container.Summary{ID: "old", State: container.StateRunning, Names: []string{"/test-web-1"}}
+	plan := &Plan{}
+	var deps []*PlanNode
+	createNodeID := 42
+	if withCreate {
+		deps = append(deps, plan.addNode(Operation{
+			Type:       OpCreateContainer,
+			ResourceID: "service:web:1",
+			Service:    &service,
+			Number:     1,
+			Name:       "tmp-web-1",
+		}, "recreate:web:1"))
+		createNodeID = deps[0].ID
+	}
+	remove := plan.addNode(Operation{
+		Type:       OpRemoveContainer,
+		ResourceID: "service:web:1",
+		Container:  &old,
+	}, "recreate:web:1", deps...)
+	plan.addNode(Operation{
+		Type:         OpRenameContainer,
+		ResourceID:   "service:web:1",
+		Name:         "test-web-1",
+		CreateNodeID: createNodeID,
+	}, "recreate:web:1", remove)
+	return plan
+}
+
+func TestExecutePlanJournal(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	run := func(t *testing.T, ctx context.Context, expect func(apiClient *mocks.MockAPIClient)) (*planJournal, error) {
+		t.Helper()
+		svc, apiClient := newTestService(t)
+		expect(apiClient)
+
+		plan := recreatePlan(false)
+		exec := svc.newPlanExecutor(&types.Project{Name: "test"}, emptyObservedState("test"))
+		exec.journal = startPlanJournal("test", plan)
+		// the replacement was created by a previous stage of the plan
+		exec.pctx.set(42, operationResult{ContainerID: "new"})
+		assert.NilError(t, exec.journal.done(42))
+		err := exec.run(ctx, plan)
+
+		journal, jErr := openPlanJournal("test")
+		assert.NilError(t, jErr)
+		return journal, err
+	}
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		journal, err := run(t, ctx, func(apiClient *mocks.MockAPIClient) {
+			apiClient.EXPECT().ContainerRemove(gomock.Any(), "old", gomock.Any()).Return(client.ContainerRemoveResult{}, nil)
+			apiClient.EXPECT().ContainerRename(gomock.Any(), "new", gomock.Any()).
+				DoAndReturn(func(context.Context, string, client.ContainerRenameOptions) (client.ContainerRenameResult, error) {
+					cancel()
+					return client.ContainerRenameResult{}, context.Canceled
+				})
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		found, err := journal.load()
+		assert.NilError(t, err)
+		assert.Assert(t, found)
+		assert.DeepEqual(t, journal.state.Completed, []int{42, 1})
+		assert.Equal(t, len(journal.pending()), 1)
+		assert.NilError(t, journal.remove())
+	})
+
+	t.Run("failed after removal", func(t *testing.T) {
+		journal, err := run(t, t.Context(), func(apiClient *mocks.MockAPIClient) {
+			apiClient.EXPECT().ContainerRemove(gomock.Any(), "old", gomock.Any()).Return(client.ContainerRemoveResult{}, nil)
+			apiClient.EXPECT().ContainerRename(gomock.Any(), "new", gomock.Any()).Return(client.ContainerRenameResult{}, errors.New("failed"))
+			// the recreation is completed
+			apiClient.EXPECT().ContainerInspect(gomock.Any(), "old", gomock.Any()).Return(client.ContainerInspectResult{}, notFoundError{})
+			apiClient.EXPECT().ContainerRename(gomock.Any(), gomock.Any(), client.ContainerRenameOptions{NewName: "test-web-1"}).
+				Return(client.ContainerRenameResult{}, nil)
+		})
+		assert.ErrorContains(t, err, "failed")
+		found, err := journal.load()
+		assert.NilError(t, err)
+		assert.Assert(t, !found)
+	})
+
+	t.Run("failed before removal", func(t *testing.T) {
+		journal, err := run(t, t.Context(), func(apiClient *mocks.MockAPIClient) {
+			apiClient.EXPECT().ContainerRemove(gomock.Any(), "old", gomock.Any()).Return(client.ContainerRemoveResult{}, errors.New("failed"))
+			// the recreation is reverted
+			apiClient.EXPECT().ContainerInspect(gomock.Any(), "old", gomock.Any()).Return(client.ContainerInspectResult{
+				Container: container.InspectResponse{State: &container.State{Running: true}},
+			}, nil)
+			apiClient.EXPECT().ContainerRemove(gomock.Any(), gomock.Any(), client.ContainerRemoveOptions{Force: true}).
+				Return(client.ContainerRemoveResult{}, nil)
+		})
+		assert.ErrorContains(t, err, "failed")
+		found, err := journal.load()
+		assert.NilError(t, err)
+		assert.Assert(t, !found)
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		journal, err := run(t, t.Context(), func(apiClient *mocks.MockAPIClient) {
+			apiClient.EXPECT().ContainerRemove(gomock.Any(), "old", gomock.Any()).Return(client.ContainerRemoveResult{}, nil)
+			apiClient.EXPECT().ContainerRename(gomock.Any(), "new", gomock.Any()).Return(client.ContainerRenameResult{}, nil)
+		})
+		assert.NilError(t, err)
+		found, err := journal.load()
+		assert.NilError(t, err)
+		assert.Assert(t, !found)
+	})
+}
+
+func TestPlanJournalRecordsCreatedReplacements(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	journal := startPlanJournal("test", recreatePlan(true))
+	assert.Assert(t, journal != nil)
+
+	// nothing to resume until the replacement is created
+	found, err := journal.load()
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+
+	assert.NilError(t, journal.done(1))
+	found, err = journal.load()
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, journal.state.Groups[0].TmpName, "tmp-web-1")
+}
+
+func TestResumeInterruptedPlanCompletes(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	svc, apiClient := newTestService(t)
+	journal := startPlanJournal("test", recreatePlan(true))
+	assert.Assert(t, journal != nil)
+	assert.NilError(t, journal.done(1))
+	assert.NilError(t, journal.done(2))
+
+	// the journal blocks a new execution until it is resumed
+	err := svc.resumeInterruptedPlan(t.Context(), "test", false)
+	assert.ErrorContains(t, err, "run `up --resume`")
+
+	// the replaced container is gone: the recreation is completed
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "old", gomock.Any()).Return(client.ContainerInspectResult{}, notFoundError{})
+	apiClient.EXPECT().ContainerRename(gomock.Any(), "tmp-web-1", client.ContainerRenameOptions{NewName: "test-web-1"}).
+		Return(client.ContainerRenameResult{}, nil)
+	err = svc.resumeInterruptedPlan(t.Context(), "test", true)
+	assert.NilError(t, err)
+
+	found, err := journal.load()
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+}
+
+func TestResumeInterruptedPlanReverts(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	svc, apiClient := newTestService(t)
+	journal := startPlanJournal("test", recreatePlan(true))
+	assert.Assert(t, journal != nil)
+	assert.NilError(t, journal.done(1))
+
+	// the replaced container was stopped but not removed: it is restored,
+	// without waiting for a resume
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "old", gomock.Any()).Return(client.ContainerInspectResult{
+		Container: container.InspectResponse{State: &container.State{Running: false}},
+	}, nil)
+	apiClient.EXPECT().ContainerRemove(gomock.Any(), "tmp-web-1", client.ContainerRemoveOptions{Force: true}).
+		Return(client.ContainerRemoveResult{}, nil)
+	apiClient.EXPECT().ContainerStart(gomock.Any(), "old", gomock.Any()).Return(client.ContainerStartResult{}, nil)
+
+	err := svc.resumeInterruptedPlan(t.Context(), "test", false)
+	assert.NilError(t, err)
+
+	found, err := journal.load()
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+}
+
+func TestPlanJournalSkipsPlansWithoutRecreation(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	plan := &Plan{}
+	plan.addNode(Operation{Type: OpCreateVolume, ResourceID: "volume:data", Name: "test_data"}, "")
+	assert.Assert(t, startPlanJournal("test", plan) == nil)
+
+	svc, _ := newTestService(t)
+	assert.NilError(t, svc.resumeInterruptedPlan(t.Context(), "test", false))
+}
+
+func TestWatchCreateHintsToResumeInterruptedPlan(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	journal := startPlanJournal("test", recreatePlan(true))
+	assert.Assert(t, journal != nil)
+	assert.NilError(t, journal.done(1))
+	assert.NilError(t, journal.done(2))
+
+	svc, apiClient := newTestService(t)
+	apiClient.EXPECT().Ping(gomock.Any(), gomock.Any()).Return(client.PingResult{APIVersion: "1.44"}, nil).AnyTimes()
+	apiClient.EXPECT().ClientVersion().Return("1.44").AnyTimes()
+	err := svc.watchCreate(t.Context(), &types.Project{Name: "test"}, api.CreateOptions{})
+	var interrupted *planInterruptedError
+	assert.Assert(t, errors.As(err, &interrupted))
+	assert.ErrorContains(t, err, "run `docker compose up --resume` from another terminal")
+}
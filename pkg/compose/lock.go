@@ -0,0 +1,220 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"go.yaml.in/yaml/v4"
+)
+
+// LockFileName is the name of the image lock file, in the project working
+// directory
+const LockFileName = "compose.lock"
+
+const lockFileHeader = "# Generated by docker compose lock, do not edit.\n"
+
+// ImageLock pins the images a project pulls to their registry digest.
+type ImageLock struct {
+	// Images holds the digest of the images, by fully qualified reference
+	Images map[string]digest.Digest `yaml:"images"`
+}
+
+// LockFilePath returns the path of the image lock file of a project.
+func LockFilePath(project *types.Project) string {
+	return filepath.Join(project.WorkingDir, LockFileName)
+}
+
+// LoadImageLock reads an image lock file. The error wraps fs.ErrNotExist
+// when there is none.
+func LoadImageLock(path string) (*ImageLock, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lock ImageLock
+	if err := yaml.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	for image, d := range lock.Images {
+		if err := d.Validate(); err != nil {
+			return nil, fmt.Errorf("reading %s: image %s: %w", path, image, err)
+		}
+	}
+	return &lock, nil
+}
+
+// Write writes the image lock to path.
+func (l *ImageLock) Write(path string) error {
+	content, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(lockFileHeader), content...), 0o644)
+}
+
+// ResolveImageLock resolves the registry digest of the images of a project:
+// the images of the services, of their pre_start hooks and of their image
+// volumes. Built images and images already pinned to a digest are not
+// locked.
+func ResolveImageLock(project *types.Project, resolve func(reference.Named) (digest.Digest, error)) (*ImageLock, error) {
+	lock := &ImageLock{Images: map[string]digest.Digest{}}
+	var errs []error
+	for _, image := range lockableImages(project) {
+		named, err := reference.ParseDockerRef(image)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, pinned := named.(reference.Canonical); pinned {
+			continue
+		}
+		if _, ok := lock.Images[named.String()]; ok {
+			continue
+		}
+		d, err := resolve(named)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolving image %s: %w", image, err))
+			continue
+		}
+		lock.Images[named.String()] = d
+	}
+	return lock, errors.Join(errs...)
+}
+
+// VerifyImageLock checks the images of a project are locked, and that their
+// registry digest is still the locked one.
+func VerifyImageLock(project *types.Project, lock *ImageLock, resolve func(reference.Named) (digest.Digest, error)) error {
+	resolved, err := ResolveImageLock(project, resolve)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, image := range slices.Sorted(maps.Keys(resolved.Images)) {
+		locked, ok := lock.Images[image]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("image %s is not locked", image))
+		case locked != resolved.Images[image]:
+			errs = append(errs, fmt.Errorf("image %s resolves to %s, locked to %s", image, resolved.Images[image], locked))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyImageLock pins the images of a project to their locked digest, and
+// returns the images the lock has no digest for.
+func ApplyImageLock(project *types.Project, lock *ImageLock) (*types.Project, []string, error) {
+	var unlocked []string
+	for _, image := range lockableImages(project) {
+		if _, ok := lockedImage(lock, image); !ok && !slices.Contains(unlocked, image) {
+			unlocked = append(unlocked, image)
+		}
+	}
+	slices.Sort(unlocked)
+
+	pin := func(image string) string {
+		if locked, ok := lockedImage(lock, image); ok {
+			return locked
+		}
+		return image
+	}
+	pinned, err := project.WithServicesTransform(func(_ string, service types.ServiceConfig) (types.ServiceConfig, error) {
+		if service.Image != "" && service.Build == nil {
+			service.Image = pin(service.Image)
+		}
+		// pin copies of the hooks and volumes, leaving project untouched
+		service.PreStart = slices.Clone(service.PreStart)
+		for i, hook := range service.PreStart {
+			if hook.Image != "" {
+				service.PreStart[i].Image = pin(hook.Image)
+			}
+		}
+		service.Volumes = slices.Clone(service.Volumes)
+		for i, volume := range service.Volumes {
+			if isLockableImageVolume(project, volume) {
+				service.Volumes[i].Source = pin(volume.Source)
+			}
+		}
+		return service, nil
+	})
+	return pinned, unlocked, err
+}
+
+// lockedImage returns the reference of an image pinned to its locked digest.
+// Images already pinned to a digest are locked as is.
+func lockedImage(lock *ImageLock, image string) (string, bool) {
+	named, err := reference.ParseDockerRef(image)
+	if err != nil {
+		return image, false
+	}
+	if _, pinned := named.(reference.Canonical); pinned {
+		return image, true
+	}
+	d, ok := lock.Images[named.String()]
+	if !ok {
+		return image, false
+	}
+	canonical, err := reference.WithDigest(named, d)
+	if err != nil {
+		return image, false
+	}
+	return canonical.String(), true
+}
+
+// lockableImages lists the images a project pulls.
+func lockableImages(project *types.Project) []string {
+	var images []string
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if service.Image != "" && service.Build == nil {
+			images = append(images, service.Image)
+		}
+		for _, hook := range service.PreStart {
+			if hook.Image != "" {
+				images = append(images, hook.Image)
+			}
+		}
+		for _, volume := range service.Volumes {
+			if isLockableImageVolume(project, volume) {
+				images = append(images, volume.Source)
+			}
+		}
+	}
+	return images
+}
+
+// isLockableImageVolume tells whether a volume mounts an image pulled from a
+// registry, rather than the image of another service.
+func isLockableImageVolume(project *types.Project, volume types.ServiceVolumeConfig) bool {
+	if volume.Type != types.VolumeTypeImage || volume.Source == "" {
+		return false
+	}
+	if _, ok := project.Services[volume.Source]; ok {
+		return false
+	}
+	_, ok := project.DisabledServices[volume.Source]
+	return !ok
+}
@@ -0,0 +1,129 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+)
+
+const (
+	nginxDigest = digest.Digest("sha256:1111111111111111111111111111111111111111111111111111111111111111")
+	busyDigest  = digest.Digest("sha256:2222222222222222222222222222222222222222222222222222222222222222")
+	dataDigest  = digest.Digest("sha256:3333333333333333333333333333333333333333333333333333333333333333")
+)
+
+func lockTestProject() *types.Project {
+	return &types.Project{
+		Name:       "myproject",
+		WorkingDir: "/src",
+		Services: types.Services{
+			"web": {
+				Name:     "web",
+				Image:    "nginx:1.27",
+				PreStart: []types.ServiceHook{{Image: "busybox"}},
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeImage, Source: "registry.example.com/data:v1", Target: "/data"},
+					{Type: types.VolumeTypeImage, Source: "app", Target: "/app"},
+				},
+			},
+			"proxy":  {Name: "proxy", Image: "docker.io/library/nginx:1.27"},
+			"app":    {Name: "app", Image: "myapp", Build: &types.BuildConfig{Context: "."}},
+			"pinned": {Name: "pinned", Image: "redis@" + string(busyDigest)},
+		},
+	}
+}
+
+func TestResolveImageLock(t *testing.T) {
+	digests := map[string]digest.Digest{
+		"docker.io/library/nginx:1.27":     nginxDigest,
+		"docker.io/library/busybox:latest": busyDigest,
+		"registry.example.com/data:v1":     dataDigest,
+	}
+	var resolved []string
+	lock, err := ResolveImageLock(lockTestProject(), func(named reference.Named) (digest.Digest, error) {
+		resolved = append(resolved, named.String())
+		return digests[named.String()], nil
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, lock.Images, map[string]digest.Digest{
+		"docker.io/library/nginx:1.27":     nginxDigest,
+		"docker.io/library/busybox:latest": busyDigest,
+		"registry.example.com/data:v1":     dataDigest,
+	})
+	assert.Equal(t, len(resolved), 3, "each image is resolved once")
+
+	path := filepath.Join(t.TempDir(), LockFileName)
+	assert.NilError(t, lock.Write(path))
+	loaded, err := LoadImageLock(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, loaded, lock)
+
+	_, err = LoadImageLock(filepath.Join(t.TempDir(), LockFileName))
+	assert.Assert(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestApplyImageLock(t *testing.T) {
+	lock := &ImageLock{Images: map[string]digest.Digest{
+		"docker.io/library/nginx:1.27": nginxDigest,
+		"registry.example.com/data:v1": dataDigest,
+	}}
+	original := lockTestProject()
+	project, unlocked, err := ApplyImageLock(original, lock)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, unlocked, []string{"busybox"})
+	// the project is left unchanged
+	assert.Equal(t, original.Services["web"].Volumes[0].Source, "registry.example.com/data:v1")
+
+	web := project.Services["web"]
+	assert.Equal(t, web.Image, "docker.io/library/nginx:1.27@"+string(nginxDigest))
+	assert.Equal(t, web.PreStart[0].Image, "busybox")
+	assert.Equal(t, web.Volumes[0].Source, "registry.example.com/data:v1@"+string(dataDigest))
+	assert.Equal(t, web.Volumes[1].Source, "app")
+	assert.Equal(t, project.Services["proxy"].Image, "docker.io/library/nginx:1.27@"+string(nginxDigest))
+	assert.Equal(t, project.Services["app"].Image, "myapp")
+	assert.Equal(t, project.Services["pinned"].Image, "redis@"+string(busyDigest))
+}
+
+func TestVerifyImageLock(t *testing.T) {
+	project := &types.Project{Services: types.Services{
+		"web": {Name: "web", Image: "nginx:1.27"},
+		"db":  {Name: "db", Image: "postgres:17"},
+	}}
+	lock := &ImageLock{Images: map[string]digest.Digest{
+		"docker.io/library/nginx:1.27": nginxDigest,
+	}}
+	resolve := func(named reference.Named) (digest.Digest, error) {
+		return busyDigest, nil
+	}
+	err := VerifyImageLock(project, lock, resolve)
+	assert.Error(t, err, "image docker.io/library/nginx:1.27 resolves to "+string(busyDigest)+", locked to "+string(nginxDigest)+
+		"\nimage docker.io/library/postgres:17 is not locked")
+
+	lock.Images = map[string]digest.Digest{
+		"docker.io/library/nginx:1.27":  busyDigest,
+		"docker.io/library/postgres:17": busyDigest,
+	}
+	assert.NilError(t, VerifyImageLock(project, lock, resolve))
+}
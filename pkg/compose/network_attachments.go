@@ -0,0 +1,182 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/network"
+)
+
+// observedEndpoint holds the settings of a container's attachment to a
+// network which are reconciled on the live container, see
+// planNetworkAttachments.
+type observedEndpoint struct {
+	NetworkID   string
+	Aliases     []string
+	IPv4Address string
+	IPv6Address string
+}
+
+func newObservedEndpoint(settings *network.EndpointSettings) observedEndpoint {
+	e := observedEndpoint{
+		NetworkID: settings.NetworkID,
+		Aliases:   settings.Aliases,
+	}
+	if settings.IPAMConfig != nil {
+		e.IPv4Address = addrString(settings.IPAMConfig.IPv4Address)
+		e.IPv6Address = addrString(settings.IPAMConfig.IPv6Address)
+	}
+	return e
+}
+
+func addrString(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+	return addr.String()
+}
+
+// endpointChanges lists the settings of the live endpoint which differ from
+// the expected ones, named after the service network attributes. Engines
+// before API 1.45 add the container short ID to the aliases, it is ignored.
+func endpointChanges(expected *network.EndpointSettings, live observedEndpoint, containerID string) []string {
+	var changes []string
+	shortID := containerID[:min(12, len(containerID))]
+	liveAliases := slices.DeleteFunc(slices.Clone(live.Aliases), func(alias string) bool { return alias == shortID })
+	expectedAliases := slices.Clone(expected.Aliases)
+	slices.Sort(liveAliases)
+	slices.Sort(expectedAliases)
+	if !slices.Equal(slices.Compact(liveAliases), slices.Compact(expectedAliases)) {
+		changes = append(changes, "aliases changed")
+	}
+	var ipv4, ipv6 string
+	if expected.IPAMConfig != nil {
+		ipv4 = addrString(expected.IPAMConfig.IPv4Address)
+		ipv6 = addrString(expected.IPAMConfig.IPv6Address)
+	}
+	if ipv4 != live.IPv4Address {
+		changes = append(changes, "ipv4_address changed")
+	}
+	if ipv6 != live.IPv6Address {
+		changes = append(changes, "ipv6_address changed")
+	}
+	return changes
+}
+
+// planNetworkAttachments reconciles the networks oc is attached to with the
+// ones of service, on the live container: it is connected to the networks it
+// misses, disconnected from the project networks the service no longer uses,
+// and reconnected to the networks whose aliases or static addresses changed.
+// Networks the project does not declare, connected by other means, are left
+// untouched.
+// Only containers carrying an immutable configuration hash, which leaves the
+// attachments, aliases and static addresses out, are reconciled this way:
+// others are recreated, as are containers whose other endpoint settings
+// changed (see immutableEndpointSettings). Networks being
+// recreated are left to planRecreateNetworks, which reconnects the containers.
+func (r *reconciler) planNetworkAttachments(service types.ServiceConfig, oc *ObservedContainer, deps []*PlanNode) ([]*PlanNode, error) {
+	if oc.ImmutableConfigHash == "" || oc.Endpoints == nil || service.NetworkMode != "" {
+		return nil, nil
+	}
+	resID := fmt.Sprintf("service:%s:%d", service.Name, oc.Number)
+	var nodes []*PlanNode
+	connect := func(name, cause string, divergences []string, endpoint *network.EndpointSettings, deps ...*PlanNode) {
+		nodes = append(nodes, r.plan.addNode(Operation{
+			Type:        OpConnectNetwork,
+			ResourceID:  resID,
+			Cause:       cause,
+			Divergences: divergences,
+			Container:   &oc.Summary,
+			Name:        name,
+			Endpoint:    endpoint,
+		}, "", deps...))
+	}
+	disconnect := func(network, cause string) *PlanNode {
+		node := r.plan.addNode(Operation{
+			Type:       OpDisconnectNetwork,
+			ResourceID: resID,
+			Cause:      cause,
+			Container:  &oc.Summary,
+			Name:       network,
+		}, "", deps...)
+		nodes = append(nodes, node)
+		return node
+	}
+
+	expected := map[string]bool{}
+	for i, key := range service.NetworksByPriority() {
+		name := r.project.Networks[key].Name
+		expected[name] = true
+		if r.recreatedNetworks[key] {
+			continue
+		}
+		endpoint, err := createEndpointSettings(r.project, service, oc.Number, key, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 && endpoint.MacAddress.String() == "" {
+			if endpoint.MacAddress, err = parseMACAddr(service.MacAddress); err != nil {
+				return nil, err
+			}
+		}
+
+		live, attached := oc.Endpoints[name]
+		if observed, ok := r.resolvedNetworks[key]; ok && attached && observed.ID != "swarm" && live.NetworkID != observed.ID {
+			// attached to a former network with the same name
+			connect(name, fmt.Sprintf("network %s recreated", key), nil, endpoint,
+				disconnect(live.NetworkID, fmt.Sprintf("network %s recreated", key)))
+			continue
+		}
+		if !attached {
+			var divergences []string
+			if r.options.Explain {
+				divergences = []string{fmt.Sprintf("networks.%s added", key)}
+			}
+			connect(name, fmt.Sprintf("network %s added", key), divergences, endpoint, deps...)
+			continue
+		}
+		if changes := endpointChanges(endpoint, live, oc.ID); len(changes) > 0 {
+			var divergences []string
+			if r.options.Explain {
+				divergences = changes
+			}
+			cause := fmt.Sprintf("network %s settings changed", key)
+			connect(name, cause, divergences, endpoint, disconnect(name, cause))
+		}
+	}
+
+	projectNetworks := map[string]string{}
+	for key, n := range r.project.Networks {
+		projectNetworks[n.Name] = key
+	}
+	for _, name := range sortedKeys(oc.Endpoints) {
+		key, ok := projectNetworks[name]
+		if !ok || expected[name] {
+			// networks the project does not declare are left untouched
+			continue
+		}
+		node := disconnect(name, fmt.Sprintf("network %s removed", key))
+		if r.options.Explain {
+			node.Operation.Divergences = []string{fmt.Sprintf("networks.%s removed", key)}
+		}
+	}
+	return nodes, nil
+}
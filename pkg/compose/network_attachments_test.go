@@ -0,0 +1,211 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+// networksProject returns a project declaring networks front, back and
+// monitoring, running service web attached to the given live endpoints.
+func networksProject(t *testing.T, svc, created types.ServiceConfig, endpoints map[string]observedEndpoint) (*types.Project, *ObservedState) {
+	t.Helper()
+	project, observed := inPlaceProject(t, svc, created, expectedUpdatableResources(created))
+	project.Networks = types.Networks{}
+	for i, key := range []string{"front", "back", "monitoring"} {
+		n := types.NetworkConfig{Name: "myproject_" + key}
+		project.Networks[key] = n
+		hash, err := NetworkHash(&n)
+		assert.NilError(t, err)
+		observed.Networks[key] = []ObservedNetwork{{ID: fmt.Sprintf("n%d", i+1), Name: n.Name, ConfigHash: hash}}
+	}
+	observed.Containers["web"][0].Endpoints = endpoints
+	return project, observed
+}
+
+func webEndpoint(networkID string, aliases ...string) observedEndpoint {
+	return observedEndpoint{NetworkID: networkID, Aliases: append([]string{"myproject-web-1", "web"}, aliases...)}
+}
+
+func TestReconcileContainers_NetworkAttachments(t *testing.T) {
+	created := types.ServiceConfig{
+		Name: "web", Image: "nginx", Scale: intPtr(1),
+		Networks: map[string]*types.ServiceNetworkConfig{"front": nil, "back": nil},
+	}
+	attached := map[string]observedEndpoint{
+		"myproject_front": webEndpoint("n1"),
+		"myproject_back":  webEndpoint("n2"),
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		project, observed := networksProject(t, created, created, attached)
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Assert(t, plan.IsEmpty())
+	})
+
+	t.Run("network added", func(t *testing.T) {
+		svc := created
+		svc.Networks = map[string]*types.ServiceNetworkConfig{"front": nil, "back": nil, "monitoring": nil}
+		project, observed := networksProject(t, svc, created, attached)
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, ConnectNetwork, network monitoring added
+`)+"\n")
+		endpoint := plan.Nodes[0].Operation.Endpoint
+		assert.DeepEqual(t, endpoint.Aliases, []string{"myproject-web-1", "web"})
+	})
+
+	t.Run("network removed", func(t *testing.T) {
+		svc := created
+		svc.Networks = map[string]*types.ServiceNetworkConfig{"front": nil}
+		project, observed := networksProject(t, svc, created, attached)
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, DisconnectNetwork, network back removed
+`)+"\n")
+	})
+
+	t.Run("alias changed", func(t *testing.T) {
+		svc := created
+		svc.Networks = map[string]*types.ServiceNetworkConfig{
+			"front": {Aliases: []string{"www"}},
+			"back":  nil,
+		}
+		project, observed := networksProject(t, svc, created, attached)
+
+		opts := defaultReconcileOptions()
+		opts.Explain = true
+		plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, DisconnectNetwork, network front settings changed
+[1] -> #2 service:web:1, ConnectNetwork, network front settings changed
+`)+"\n")
+		assert.DeepEqual(t, plan.Nodes[1].Operation.Divergences, []string{"aliases changed"})
+		assert.DeepEqual(t, plan.Nodes[1].Operation.Endpoint.Aliases, []string{"myproject-web-1", "web", "www"})
+	})
+
+	t.Run("mac address changed", func(t *testing.T) {
+		svc := created
+		svc.Networks = map[string]*types.ServiceNetworkConfig{
+			"front": {MacAddress: "02:42:ac:11:00:02"},
+			"back":  nil,
+		}
+		project, observed := networksProject(t, svc, created, attached)
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[2] -> #3 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[3] -> #4 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+`)+"\n")
+	})
+
+	t.Run("container short ID alias ignored", func(t *testing.T) {
+		project, observed := networksProject(t, created, created, map[string]observedEndpoint{
+			"myproject_front": webEndpoint("n1", "c1aabbccddee"),
+			"myproject_back":  webEndpoint("n2"),
+		})
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Assert(t, plan.IsEmpty())
+	})
+
+	t.Run("undeclared network left attached", func(t *testing.T) {
+		project, observed := networksProject(t, created, created, map[string]observedEndpoint{
+			"myproject_front": webEndpoint("n1"),
+			"myproject_back":  webEndpoint("n2"),
+			"bridge":          {NetworkID: "b1"},
+		})
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Assert(t, plan.IsEmpty())
+	})
+
+	t.Run("immutable field changed", func(t *testing.T) {
+		svc := created
+		svc.Networks = map[string]*types.ServiceNetworkConfig{"front": nil}
+		svc.Image = "nginx:2"
+		project, observed := networksProject(t, svc, created, attached)
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[2] -> #3 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[3] -> #4 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+`)+"\n")
+	})
+
+	t.Run("no immutable hash label", func(t *testing.T) {
+		svc := created
+		svc.Networks = map[string]*types.ServiceNetworkConfig{"front": nil, "back": nil, "monitoring": nil}
+		project, observed := networksProject(t, svc, created, attached)
+		observed.Containers["web"][0].ImmutableConfigHash = ""
+		observed.Containers["web"][0].Endpoints = nil
+
+		plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(plan.String(), "CreateContainer"), plan.String())
+	})
+}
+
+func TestExecutePlanConnectNetworkEndpoint(t *testing.T) {
+	svc, apiClient := newTestService(t)
+
+	endpoint := &network.EndpointSettings{Aliases: []string{"test-web-1", "web"}}
+	apiClient.EXPECT().NetworkConnect(gomock.Any(), "test_front", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, opts client.NetworkConnectOptions) (client.NetworkConnectResult, error) {
+			assert.Equal(t, opts.Container, "c1")
+			assert.Equal(t, opts.EndpointConfig, endpoint)
+			return client.NetworkConnectResult{}, nil
+		})
+
+	plan := &Plan{}
+	plan.addNode(Operation{
+		Type:       OpConnectNetwork,
+		ResourceID: "service:web:1",
+		Cause:      "network front added",
+		Container:  &container.Summary{ID: "c1", Names: []string{"/test-web-1"}},
+		Name:       "test_front",
+		Endpoint:   endpoint,
+	}, "")
+
+	err := svc.executePlan(t.Context(), &types.Project{Name: "test"}, emptyObservedState("test"), plan)
+	assert.NilError(t, err)
+}
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"encoding/json"
 	"slices"
 	"sort"
 	"strconv"
@@ -27,6 +28,8 @@ import (
 	"github.com/containerd/errdefs"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/compose/v5/pkg/api"
 )
@@ -103,19 +106,28 @@ func (s *ObservedState) selectVolume(key, desiredName string) (ObservedVolume, [
 // ObservedContainer holds the relevant state extracted from a running or stopped
 // container, with label values pre-parsed for efficient comparison.
 type ObservedContainer struct {
-	ID                string
-	Name              string
-	State             container.ContainerState // "running", "exited", "created", "restarting", etc.
-	ConfigHash        string                   // label com.docker.compose.config-hash
-	ImageDigest       string                   // label com.docker.compose.image
-	ImageVolumeDigest string                   // label com.docker.compose.image-volume-digest
-	Number            int                      // label com.docker.compose.container-number
+	ID                  string
+	Name                string
+	State               container.ContainerState // "running", "exited", "created", "restarting", etc.
+	ConfigHash          string                   // label com.docker.compose.config-hash
+	ImageDigest         string                   // label com.docker.compose.image
+	ImageVolumeDigest   string                   // label com.docker.compose.image-volume-digest
+	ImmutableConfigHash string                   // label com.docker.compose.immutable-config-hash
+	FieldHashes         map[string]string        // label com.docker.compose.config-field-hashes
+	Number              int                      // label com.docker.compose.container-number
 
 	// ConnectedNetworks maps network IDs found in the container's network
 	// settings. Key is the network name as seen by Docker, value is the
 	// network ID.
 	ConnectedNetworks map[string]string
 
+	// Resources holds the live settings which can be updated in place, and
+	// Endpoints the live network attachments, by network name. Only the
+	// containers which may be updated in place are inspected for them, see
+	// inspectUpdatableResources, others are nil.
+	Resources *updatableResources
+	Endpoints map[string]observedEndpoint
+
 	// Raw summary kept for the executor which needs it to call Moby APIs.
 	Summary container.Summary
 }
@@ -175,6 +187,9 @@ func (s *composeService) collectObservedState(ctx context.Context, project *type
 			state.Orphans = append(state.Orphans, toObservedContainer(ctr))
 		}
 	}
+	if err := s.inspectUpdatableResources(ctx, project, state); err != nil {
+		return nil, err
+	}
 
 	// --- Networks ---
 	networkList, err := s.apiClient().NetworkList(ctx, client.NetworkListOptions{
@@ -227,6 +242,56 @@ func (s *composeService) collectObservedState(ctx context.Context, project *type
 	return state, nil
 }
 
+// inspectUpdatableResources records the live settings and network attachments
+// of the service containers which can be updated in place, so that the
+// reconciler can update them rather than recreating the containers. Only the
+// containers whose configuration diverged while their immutable configuration
+// hash still matches are inspected: those up to date need no update, and the
+// others are recreated anyway. Containers are inspected concurrently, as
+// ContainerList does not report their HostConfig nor their endpoints'
+// configuration.
+func (s *composeService) inspectUpdatableResources(ctx context.Context, project *types.Project, state *ObservedState) error {
+	byService := state.containersByService()
+	eg, ctx := errgroup.WithContext(ctx)
+	for name, containers := range state.Containers {
+		service, ok := project.Services[name]
+		if !ok {
+			continue
+		}
+		hashes, err := expectedServiceHashes(service, byService)
+		if err != nil {
+			return err
+		}
+		for i := range containers {
+			oc := &containers[i]
+			if oc.ConfigHash == hashes.config || oc.ImmutableConfigHash == "" || oc.ImmutableConfigHash != hashes.immutable {
+				continue
+			}
+			eg.Go(func() error {
+				inspected, err := s.apiClient().ContainerInspect(ctx, oc.ID, client.ContainerInspectOptions{})
+				if errdefs.IsNotFound(err) {
+					// removed meanwhile, the reconciler recreates it
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				oc.Resources = observedUpdatableResources(inspected.Container.HostConfig)
+				oc.Endpoints = map[string]observedEndpoint{}
+				if settings := inspected.Container.NetworkSettings; settings != nil {
+					for name, endpoint := range settings.Networks {
+						if endpoint != nil {
+							oc.Endpoints[name] = newObservedEndpoint(endpoint)
+						}
+					}
+				}
+				return nil
+			})
+		}
+	}
+	return eg.Wait()
+}
+
 // discoverUnmanagedNetworks augments the observed state with networks that match
 // a declared network by name but carry no compose label — pre-label Compose or
 // manually created networks, missed by the label-filtered NetworkList. Each is
@@ -321,6 +386,12 @@ func (s *composeService) discoverUnmanagedVolumes(ctx context.Context, project *
 func toObservedContainer(c container.Summary) ObservedContainer {
 	number, _ := strconv.Atoi(c.Labels[api.ContainerNumberLabel])
 
+	var fieldHashes map[string]string
+	if label, ok := c.Labels[api.ConfigFieldHashesLabel]; ok {
+		// an invalid label only prevents explaining a recreation
+		_ = json.Unmarshal([]byte(label), &fieldHashes)
+	}
+
 	networks := map[string]string{}
 	if c.NetworkSettings != nil {
 		for name, settings := range c.NetworkSettings.Networks {
@@ -329,15 +400,17 @@ func toObservedContainer(c container.Summary) ObservedContainer {
 	}
 
 	return ObservedContainer{
-		ID:                c.ID,
-		Name:              getCanonicalContainerName(c),
-		State:             c.State,
-		ConfigHash:        c.Labels[api.ConfigHashLabel],
-		ImageDigest:       c.Labels[api.ImageDigestLabel],
-		ImageVolumeDigest: c.Labels[api.ImageVolumeDigestLabel],
-		Number:            number,
-		ConnectedNetworks: networks,
-		Summary:           c,
+		ID:                  c.ID,
+		Name:                getCanonicalContainerName(c),
+		State:               c.State,
+		ConfigHash:          c.Labels[api.ConfigHashLabel],
+		ImageDigest:         c.Labels[api.ImageDigestLabel],
+		ImageVolumeDigest:   c.Labels[api.ImageVolumeDigestLabel],
+		ImmutableConfigHash: c.Labels[api.ImmutableConfigHashLabel],
+		FieldHashes:         fieldHashes,
+		Number:              number,
+		ConnectedNetworks:   networks,
+		Summary:             c,
 	}
 }
 
@@ -413,3 +486,77 @@ func (s *ObservedState) containersByService() map[string]Containers {
 	}
 	return result
 }
+
+// fingerprint digests the parts of the observed state the reconciler bases its
+// decisions on. A saved plan records it so that applying the plan can detect
+// the project resources changed since it was computed. Entries are sorted so
+// the fingerprint does not depend on the daemon's list order.
+func (s *ObservedState) fingerprint() (string, error) {
+	type containerEntry struct {
+		ID, Name, State, ConfigHash, ImageDigest, ImageVolumeDigest string
+		Number                                                      int
+		Networks                                                    []string
+		Resources                                                   *updatableResources
+		Endpoints                                                   map[string]observedEndpoint
+	}
+	toEntries := func(containers []ObservedContainer) []containerEntry {
+		entries := make([]containerEntry, len(containers))
+		for i, c := range containers {
+			networks := make([]string, 0, len(c.ConnectedNetworks))
+			for _, id := range c.ConnectedNetworks {
+				networks = append(networks, id)
+			}
+			sort.Strings(networks)
+			entries[i] = containerEntry{
+				ID:                c.ID,
+				Name:              c.Name,
+				State:             string(c.State),
+				ConfigHash:        c.ConfigHash,
+				ImageDigest:       c.ImageDigest,
+				ImageVolumeDigest: c.ImageVolumeDigest,
+				Number:            c.Number,
+				Networks:          networks,
+				Resources:         c.Resources,
+				Endpoints:         c.Endpoints,
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+		return entries
+	}
+
+	containers := map[string][]containerEntry{}
+	for service, observed := range s.Containers {
+		containers[service] = toEntries(observed)
+	}
+	networks := map[string][]ObservedNetwork{}
+	for key, observed := range s.Networks {
+		sorted := slices.Clone(observed)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		networks[key] = sorted
+	}
+	volumes := map[string][]ObservedVolume{}
+	for key, observed := range s.Volumes {
+		sorted := slices.Clone(observed)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		volumes[key] = sorted
+	}
+
+	// encoding/json sorts map keys, which makes the encoding canonical
+	b, err := json.Marshal(struct {
+		Project    string
+		Containers map[string][]containerEntry
+		Orphans    []containerEntry
+		Networks   map[string][]ObservedNetwork
+		Volumes    map[string][]ObservedVolume
+	}{
+		Project:    s.ProjectName,
+		Containers: containers,
+		Orphans:    toEntries(s.Orphans),
+		Networks:   networks,
+		Volumes:    volumes,
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest.SHA256.FromBytes(b).String(), nil
+}
@@ -620,3 +620,107 @@ func TestEmitRunningEvents(t *testing.T) {
 		assert.Equal(t, len(events.resources), 0)
 	})
 }
+
+func TestObservedStateFingerprint(t *testing.T) {
+	web1 := ObservedContainer{
+		ID: "aaa", Name: "p-web-1", State: container.StateRunning, ConfigHash: "h1", Number: 1,
+		ConnectedNetworks: map[string]string{"front": "n1", "back": "n2"},
+	}
+	web2 := ObservedContainer{ID: "bbb", Name: "p-web-2", State: container.StateRunning, ConfigHash: "h1", Number: 2}
+	state := func(containers ...ObservedContainer) *ObservedState {
+		return &ObservedState{
+			ProjectName: "p",
+			Containers:  map[string][]ObservedContainer{"web": containers},
+			Networks: map[string][]ObservedNetwork{
+				"default": {{ID: "n1", Name: "p_default"}, {ID: "n3", Name: "p_default_dup"}},
+			},
+			Volumes: map[string][]ObservedVolume{},
+		}
+	}
+
+	ref, err := state(web1, web2).fingerprint()
+	assert.NilError(t, err)
+
+	t.Run("independent of list order", func(t *testing.T) {
+		reordered := state(web2, web1)
+		reordered.Networks["default"] = []ObservedNetwork{{ID: "n3", Name: "p_default_dup"}, {ID: "n1", Name: "p_default"}}
+		fp, err := reordered.fingerprint()
+		assert.NilError(t, err)
+		assert.Equal(t, fp, ref)
+	})
+
+	t.Run("changes with container state", func(t *testing.T) {
+		stopped := web2
+		stopped.State = container.StateExited
+		fp, err := state(web1, stopped).fingerprint()
+		assert.NilError(t, err)
+		assert.Assert(t, fp != ref)
+	})
+
+	t.Run("changes with container config", func(t *testing.T) {
+		updated := web2
+		updated.ConfigHash = "h2"
+		fp, err := state(web1, updated).fingerprint()
+		assert.NilError(t, err)
+		assert.Assert(t, fp != ref)
+	})
+}
+
+// TestCollectObservedState_InspectsUpdatableResources verifies that only
+// containers whose configuration diverged while their immutable configuration
+// hash still matches are inspected for the live settings the reconciler may
+// update in place.
+func TestCollectObservedState_InspectsUpdatableResources(t *testing.T) {
+	svc, apiClient := newTestService(t)
+	project := &types.Project{Name: "myproject", Services: types.Services{"web": {Name: "web"}}}
+	hashes, err := expectedServiceHashes(project.Services["web"], nil)
+	assert.NilError(t, err)
+
+	labels := func(number string, hash string, immutable string) map[string]string {
+		l := map[string]string{
+			api.ServiceLabel:         "web",
+			api.ProjectLabel:         "myproject",
+			api.ConfigHashLabel:      hash,
+			api.ContainerNumberLabel: number,
+			api.OneoffLabel:          "False",
+		}
+		if immutable != "" {
+			l[api.ImmutableConfigHashLabel] = immutable
+		}
+		return l
+	}
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).Return(client.ContainerListResult{
+		Items: []container.Summary{
+			{ID: "c1", Names: []string{"/myproject-web-1"}, Labels: labels("1", "outdated", hashes.immutable)},
+			{ID: "c2", Names: []string{"/myproject-web-2"}, Labels: labels("2", "outdated", "")},
+			{ID: "c3", Names: []string{"/myproject-web-3"}, Labels: labels("3", hashes.config, hashes.immutable)},
+			{ID: "c4", Names: []string{"/myproject-web-4"}, Labels: labels("4", "outdated", "other")},
+		},
+	}, nil)
+	pids := int64(-1)
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "c1", gomock.Any()).Return(client.ContainerInspectResult{
+		Container: container.InspectResponse{
+			ID: "c1",
+			HostConfig: &container.HostConfig{
+				Resources:     container.Resources{Memory: 1 << 30, PidsLimit: &pids},
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyAlways},
+			},
+		},
+	}, nil)
+	apiClient.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return(client.NetworkListResult{}, nil)
+	apiClient.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(client.VolumeListResult{}, nil)
+
+	state, err := svc.collectObservedState(t.Context(), project)
+	assert.NilError(t, err)
+
+	containers := state.Containers["web"]
+	assert.Equal(t, len(containers), 4)
+	assert.Equal(t, containers[0].ImmutableConfigHash, hashes.immutable)
+	assert.DeepEqual(t, containers[0].Resources, &updatableResources{
+		Memory:        1 << 30,
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyAlways},
+	})
+	for _, oc := range containers[1:] {
+		assert.Assert(t, oc.Resources == nil, oc.Name)
+	}
+}
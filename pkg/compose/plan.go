@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
@@ -25,6 +26,9 @@ import (
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+
+	"github.com/docker/compose/v5/pkg/api"
 )
 
 // OperationType identifies the kind of atomic operation in a reconciliation plan.
@@ -50,6 +54,8 @@ const (
 	OpStopContainer   OperationType = 22
 	OpRemoveContainer OperationType = 23
 	OpRenameContainer OperationType = 24
+	OpWaitContainer   OperationType = 25
+	OpUpdateContainer OperationType = 26
 
 	// Provider operations
 	OpRunProvider OperationType = 30
@@ -80,6 +86,10 @@ func (o OperationType) String() string {
 		return "RemoveContainer"
 	case OpRenameContainer:
 		return "RenameContainer"
+	case OpWaitContainer:
+		return "WaitContainer"
+	case OpUpdateContainer:
+		return "UpdateContainer"
 	case OpRunProvider:
 		return "RunProvider"
 	default:
@@ -92,23 +102,36 @@ type Operation struct {
 	Type       OperationType
 	ResourceID string // e.g. "service:web:1", "network:backend", "volume:data"
 	Cause      string // why this operation is needed
+	// Divergences lists, for a container recreated or updated in place, the
+	// differences with its expected configuration ("environment.FOO changed",
+	// "image digest changed", ...). Only recorded with ReconcileOptions.Explain.
+	Divergences []string
 
 	// Resource-specific data (only the relevant fields are set per operation type)
-	Service      *types.ServiceConfig // for container operations
-	Container    *container.Summary   // existing container (for stop/remove)
-	Inherited    *container.Summary   // container to inherit anonymous volumes from (for create-as-replacement)
-	Number       int                  // container replica number (for create)
-	Name         string               // target container/resource name
-	Network      *types.NetworkConfig // for network operations
-	Volume       *types.VolumeConfig  // for volume operations
-	Timeout      *time.Duration       // for stop operations
-	CreateNodeID int                  // for OpRenameContainer: ID of the CreateContainer node whose result to rename
+	Service      *types.ServiceConfig      // for container operations
+	Container    *container.Summary        // existing container (for stop/remove)
+	Inherited    *container.Summary        // container to inherit anonymous volumes from (for create-as-replacement)
+	Replaces     *container.Summary        // container restored if the plan fails (for create-as-replacement, with rollback on failure)
+	Number       int                       // container replica number (for create)
+	Name         string                    // target container/resource name
+	Network      *types.NetworkConfig      // for network operations
+	Volume       *types.VolumeConfig       // for volume operations
+	Endpoint     *network.EndpointSettings // for OpConnectNetwork: aliases and addresses of the container on the network
+	Timeout      *time.Duration            // for stop operations
+	CreateNodeID int                       // for OpRenameContainer, OpStartContainer, OpWaitContainer: ID of the CreateContainer node whose result to act on
 	// BestEffort marks an operation whose failure must not abort the plan. It is
 	// used for the optional removal of the old network on a rename: if the
 	// network is still in use (by non-Compose containers) the removal is skipped
 	// with a warning instead of failing — the new network already carries a
 	// different name, so the migration does not depend on the old one going away.
 	BestEffort bool
+
+	// Rolling update settings (deploy.update_config) for OpWaitContainer: how
+	// long the container must stay healthy, the pause before the next batch,
+	// and what to do if the container does not become healthy.
+	Monitor       time.Duration
+	Delay         time.Duration
+	FailureAction string
 }
 
 // PlanNode is a single node in the reconciliation DAG. It represents one
@@ -151,32 +174,125 @@ func (p *Plan) addNode(op Operation, group string, deps ...*PlanNode) *PlanNode
 //	[2] -> #3 service:web:1, StopContainer, replaced by #2 [recreate:web:1]
 func (p *Plan) String() string {
 	var sb strings.Builder
-	for _, node := range p.Nodes {
-		depIDs := make([]int, len(node.DependsOn))
-		for i, d := range node.DependsOn {
-			depIDs[i] = d.ID
-		}
-		sort.Ints(depIDs)
-		deps := make([]string, len(depIDs))
-		for i, id := range depIDs {
-			deps[i] = strconv.Itoa(id)
-		}
-		fmt.Fprintf(&sb, "[%s] -> #%d %s, %s, %s",
-			strings.Join(deps, ","),
-			node.ID,
-			node.Operation.ResourceID,
-			node.Operation.Type,
-			node.Operation.Cause,
-		)
-		if node.Group != "" {
-			fmt.Fprintf(&sb, " [%s]", node.Group)
-		}
+	for _, node := range p.toAPIPlan().Nodes {
+		sb.WriteString(formatPlanNode(node))
 		sb.WriteByte('\n')
 	}
 	return sb.String()
 }
 
+// formatPlanNode renders a plan node on a single line, in the format of
+// Plan.String.
+func formatPlanNode(node api.PlanNode) string {
+	deps := make([]string, len(node.DependsOn))
+	for i, id := range node.DependsOn {
+		deps[i] = strconv.Itoa(id)
+	}
+	line := fmt.Sprintf("[%s] -> #%d %s, %s, %s", strings.Join(deps, ","), node.ID, node.ResourceID, node.Operation, node.Cause)
+	if node.Group != "" {
+		line += fmt.Sprintf(" [%s]", node.Group)
+	}
+	return line
+}
+
+// dependencyIDs returns the sorted IDs of the nodes this node depends on.
+func (n *PlanNode) dependencyIDs() []int {
+	ids := make([]int, len(n.DependsOn))
+	for i, d := range n.DependsOn {
+		ids[i] = d.ID
+	}
+	sort.Ints(ids)
+	return ids
+}
+
 // IsEmpty returns true if the plan contains no operations.
 func (p *Plan) IsEmpty() bool {
 	return len(p.Nodes) == 0
 }
+
+// Plan computes the reconciliation plan `up` would execute for project,
+// without applying it. Unless options.EnsureImages is set, images are neither
+// pulled nor built: containers are compared with the images available
+// locally. Destructive decisions `up` would confirm interactively (recreating
+// a diverged volume) are reported as planned.
+func (s *composeService) Plan(ctx context.Context, project *types.Project, options api.PlanOptions) (*api.Plan, error) {
+	createOpts := options.Create
+	if len(createOpts.Services) == 0 {
+		createOpts.Services = project.ServiceNames()
+	}
+
+	if err := project.CheckContainerNameUnicity(); err != nil {
+		return nil, err
+	}
+
+	// Keep the model as loaded: the steps below enrich project in place with
+	// runtime details (image digests, API socket) that Apply recomputes.
+	model, err := cloneProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.EnsureImages {
+		err = s.ensureImagesExists(ctx, project, createOpts.Build, createOpts.QuietPull)
+	} else {
+		err = s.labelLocalImageDigests(ctx, project)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	project, err = s.useAPISocket(project)
+	if err != nil {
+		return nil, err
+	}
+
+	observed, plan, err := s.observeAndReconcile(ctx, project, createOpts, AlwaysOkPrompt())
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := observed.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+
+	result := plan.toAPIPlan()
+	result.ObservedState = fingerprint
+	result.Project = model
+	result.Options = createOpts
+	return result, nil
+}
+
+// labelLocalImageDigests labels services with the digest of their image as
+// available locally, without pulling nor building anything.
+func (s *composeService) labelLocalImageDigests(ctx context.Context, project *types.Project) error {
+	images, pinnedDigests, err := s.getLocalImagesDigests(ctx, project)
+	if err != nil {
+		return err
+	}
+	s.labelImageDigests(ctx, project, images, pinnedDigests)
+	return nil
+}
+
+// cloneProject returns a deep copy of project.
+func cloneProject(project *types.Project) (*types.Project, error) {
+	return project.WithServicesTransform(func(_ string, service types.ServiceConfig) (types.ServiceConfig, error) {
+		return service, nil
+	})
+}
+
+// toAPIPlan converts the plan into its public, serializable representation.
+func (p *Plan) toAPIPlan() *api.Plan {
+	nodes := make([]api.PlanNode, len(p.Nodes))
+	for i, node := range p.Nodes {
+		nodes[i] = api.PlanNode{
+			ID:          node.ID,
+			Operation:   node.Operation.Type.String(),
+			ResourceID:  node.Operation.ResourceID,
+			Cause:       node.Operation.Cause,
+			Divergences: node.Operation.Divergences,
+			DependsOn:   node.dependencyIDs(),
+			Group:       node.Group,
+		}
+	}
+	return &api.Plan{Nodes: nodes}
+}
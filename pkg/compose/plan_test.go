@@ -17,9 +17,12 @@
 package compose
 
 import (
+	"encoding/json"
 	"testing"
 
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
 )
 
 func TestOperationTypeString(t *testing.T) {
@@ -38,6 +41,8 @@ func TestOperationTypeString(t *testing.T) {
 		{OpStopContainer, "StopContainer"},
 		{OpRemoveContainer, "RemoveContainer"},
 		{OpRenameContainer, "RenameContainer"},
+		{OpWaitContainer, "WaitContainer"},
+		{OpUpdateContainer, "UpdateContainer"},
 		{OpRunProvider, "RunProvider"},
 		{OperationType(999), "Unknown(999)"},
 	}
@@ -138,3 +143,28 @@ func TestPlanAddNodeAutoIncrements(t *testing.T) {
 	assert.Equal(t, n3.DependsOn[0].ID, 1)
 	assert.Equal(t, n3.DependsOn[1].ID, 2)
 }
+
+func TestPlanToAPIPlan(t *testing.T) {
+	p := &Plan{}
+	nw := p.addNode(Operation{Type: OpCreateNetwork, ResourceID: "network:default", Cause: "not found"}, "")
+	vol := p.addNode(Operation{Type: OpCreateVolume, ResourceID: "volume:data", Cause: "not found"}, "")
+	p.addNode(Operation{Type: OpCreateContainer, ResourceID: "service:web:1", Cause: "config changed (tmpName)"}, "recreate:web:1", vol, nw)
+
+	assert.DeepEqual(t, p.toAPIPlan(), &api.Plan{Nodes: []api.PlanNode{
+		{ID: 1, Operation: "CreateNetwork", ResourceID: "network:default", Cause: "not found", DependsOn: []int{}},
+		{ID: 2, Operation: "CreateVolume", ResourceID: "volume:data", Cause: "not found", DependsOn: []int{}},
+		{ID: 3, Operation: "CreateContainer", ResourceID: "service:web:1", Cause: "config changed (tmpName)", DependsOn: []int{1, 2}, Group: "recreate:web:1"},
+	}})
+}
+
+func TestPlanToAPIPlanJSON(t *testing.T) {
+	p := &Plan{}
+	nw := p.addNode(Operation{Type: OpCreateNetwork, ResourceID: "network:default", Cause: "not found"}, "")
+	p.addNode(Operation{Type: OpCreateContainer, ResourceID: "service:web:1", Cause: "no existing container"}, "", nw)
+
+	out, err := json.Marshal(p.toAPIPlan())
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), `{"Nodes":[`+
+		`{"ID":1,"Operation":"CreateNetwork","ResourceID":"network:default","Cause":"not found","DependsOn":[]},`+
+		`{"ID":2,"Operation":"CreateContainer","ResourceID":"service:web:1","Cause":"no existing container","DependsOn":[1]}]}`)
+}
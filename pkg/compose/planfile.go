@@ -0,0 +1,165 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// planFileVersion is bumped on incompatible changes to the plan file format.
+const planFileVersion = 1
+
+// planFile is the on-disk representation of a saved plan. The project model is
+// stored as YAML; the Compose labels attached to services by the project
+// loader are not part of the model, so they are stored aside.
+type planFile struct {
+	Version          int
+	ProjectName      string
+	WorkingDir       string
+	Model            string
+	Labels           map[string]types.Labels `json:",omitempty"`
+	DisabledServices []string                `json:",omitempty"`
+	Options          planFileOptions
+	ObservedState    string
+	Nodes            []api.PlanNode
+}
+
+// planFileOptions holds the subset of api.CreateOptions a saved plan is
+// applied with. Build options are left out: images are resolved when the plan
+// is computed.
+type planFileOptions struct {
+	Services             []string
+	RemoveOrphans        bool
+	IgnoreOrphans        bool
+	Recreate             string
+	RecreateDependencies string
+	Inherit              bool
+	Timeout              *time.Duration `json:",omitempty"`
+	QuietPull            bool
+	SkipProviders        bool
+	RollbackOnFailure    bool
+}
+
+// SavePlan writes a plan computed by Plan, so that it can be applied later on
+// with Apply after being loaded back by LoadPlan.
+func SavePlan(w io.Writer, plan *api.Plan) error {
+	if plan.Project == nil {
+		return errors.New("plan has no project model")
+	}
+	model, err := plan.Project.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	labels := map[string]types.Labels{}
+	for name, service := range plan.Project.Services {
+		if len(service.CustomLabels) > 0 {
+			labels[name] = service.CustomLabels
+		}
+	}
+	options := plan.Options
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(planFile{
+		Version:          planFileVersion,
+		ProjectName:      plan.Project.Name,
+		WorkingDir:       plan.Project.WorkingDir,
+		Model:            string(model),
+		Labels:           labels,
+		DisabledServices: plan.Project.DisabledServiceNames(),
+		Options: planFileOptions{
+			Services:             options.Services,
+			RemoveOrphans:        options.RemoveOrphans,
+			IgnoreOrphans:        options.IgnoreOrphans,
+			Recreate:             options.Recreate,
+			RecreateDependencies: options.RecreateDependencies,
+			Inherit:              options.Inherit,
+			Timeout:              options.Timeout,
+			QuietPull:            options.QuietPull,
+			SkipProviders:        options.SkipProviders,
+			RollbackOnFailure:    options.RollbackOnFailure,
+		},
+		ObservedState: plan.ObservedState,
+		Nodes:         plan.Nodes,
+	})
+}
+
+// LoadPlan reads a plan written by SavePlan.
+func LoadPlan(ctx context.Context, r io.Reader) (*api.Plan, error) {
+	var f planFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid plan file: %w", err)
+	}
+	if f.Version != planFileVersion {
+		return nil, fmt.Errorf("unsupported plan file version %d", f.Version)
+	}
+
+	// The model was saved fully resolved: it must not be interpolated again
+	// (escaped `$` would be expanded), and all its services are enabled
+	// whatever profiles they declare.
+	project, err := loader.LoadWithContext(ctx, types.ConfigDetails{
+		WorkingDir: f.WorkingDir,
+		ConfigFiles: []types.ConfigFile{
+			{Filename: "plan.yaml", Content: []byte(f.Model)},
+		},
+	}, func(options *loader.Options) {
+		options.SetProjectName(f.ProjectName, true)
+		options.SkipInterpolation = true
+		options.Profiles = []string{"*"}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan file: %w", err)
+	}
+	for name, service := range project.Services {
+		service.CustomLabels = f.Labels[name]
+		project.Services[name] = service
+	}
+	if len(f.DisabledServices) > 0 {
+		project.DisabledServices = types.Services{}
+		for _, name := range f.DisabledServices {
+			project.DisabledServices[name] = types.ServiceConfig{Name: name}
+		}
+	}
+
+	options := f.Options
+	return &api.Plan{
+		Nodes:         f.Nodes,
+		ObservedState: f.ObservedState,
+		Project:       project,
+		Options: api.CreateOptions{
+			Services:             options.Services,
+			RemoveOrphans:        options.RemoveOrphans,
+			IgnoreOrphans:        options.IgnoreOrphans,
+			Recreate:             options.Recreate,
+			RecreateDependencies: options.RecreateDependencies,
+			Inherit:              options.Inherit,
+			Timeout:              options.Timeout,
+			QuietPull:            options.QuietPull,
+			SkipProviders:        options.SkipProviders,
+			RollbackOnFailure:    options.RollbackOnFailure,
+		},
+	}, nil
+}
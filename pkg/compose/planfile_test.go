@@ -0,0 +1,119 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+func TestSaveLoadPlan(t *testing.T) {
+	tmpDir := t.TempDir()
+	composeFile := filepath.Join(tmpDir, "compose.yaml")
+	err := os.WriteFile(composeFile, []byte(`
+name: test-project
+services:
+  web:
+    image: nginx:latest
+    command: echo $$HOME
+    ports:
+      - "8080:80"
+    depends_on:
+      - db
+    volumes:
+      - data:/data
+  db:
+    image: postgres:latest
+    environment:
+      POSTGRES_PASSWORD: secret
+  debug:
+    image: busybox
+    profiles: [debug]
+volumes:
+  data: {}
+`), 0o644)
+	assert.NilError(t, err)
+
+	service, err := NewComposeService(nil)
+	assert.NilError(t, err)
+	project, err := service.LoadProject(t.Context(), api.ProjectLoadOptions{
+		ConfigPaths: []string{composeFile},
+	})
+	assert.NilError(t, err)
+
+	timeout := 5 * time.Second
+	plan := &api.Plan{
+		Nodes: []api.PlanNode{
+			{ID: 1, Operation: "CreateVolume", ResourceID: "volume:data", Cause: "not found", DependsOn: []int{}},
+		},
+		ObservedState: "sha256:abc",
+		Project:       project,
+		Options: api.CreateOptions{
+			Services:      []string{"web", "db"},
+			RemoveOrphans: true,
+			Recreate:      api.RecreateForce,
+			Timeout:       &timeout,
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NilError(t, SavePlan(&buf, plan))
+	loaded, err := LoadPlan(t.Context(), &buf)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, loaded.Nodes, plan.Nodes)
+	assert.Equal(t, loaded.ObservedState, plan.ObservedState)
+	assert.DeepEqual(t, loaded.Options, plan.Options)
+	assert.Equal(t, loaded.Project.Name, project.Name)
+	assert.Assert(t, is.Len(loaded.Project.Services, 2))
+	assert.Check(t, is.Contains(loaded.Project.DisabledServices, "debug"))
+	for name, s := range project.Services {
+		want, err := ServiceHash(s)
+		assert.NilError(t, err)
+		got, err := ServiceHash(loaded.Project.Services[name])
+		assert.NilError(t, err)
+		assert.Equal(t, got, want, "service %s", name)
+		assert.DeepEqual(t, loaded.Project.Services[name].CustomLabels, s.CustomLabels)
+	}
+}
+
+func TestLoadPlanUnsupportedVersion(t *testing.T) {
+	_, err := LoadPlan(t.Context(), strings.NewReader(`{"Version": 42}`))
+	assert.ErrorContains(t, err, "unsupported plan file version 42")
+}
+
+func TestDiffPlanNodes(t *testing.T) {
+	network := api.PlanNode{ID: 1, Operation: "CreateNetwork", ResourceID: "network:default", Cause: "not found", DependsOn: []int{}}
+	web := api.PlanNode{ID: 2, Operation: "CreateContainer", ResourceID: "service:web:1", Cause: "no existing container", DependsOn: []int{1}}
+	db := api.PlanNode{ID: 2, Operation: "CreateContainer", ResourceID: "service:db:1", Cause: "no existing container", DependsOn: []int{1}}
+
+	diff := diffPlanNodes([]api.PlanNode{network, web}, []api.PlanNode{network, db})
+	assert.Equal(t, diff, "- [1] -> #2 service:web:1, CreateContainer, no existing container\n"+
+		"+ [1] -> #2 service:db:1, CreateContainer, no existing container\n")
+
+	diff = diffPlanNodes([]api.PlanNode{network}, []api.PlanNode{network})
+	assert.Equal(t, diff, "planned operations are unchanged, but the resources they apply to were modified\n")
+}
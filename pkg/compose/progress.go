@@ -87,6 +87,12 @@ func builtEvent(id string) api.Resource {
 	return newEvent("Image "+id, api.Done, api.StatusBuilt)
 }
 
+// upToDateEvent creates a new up to date (done) Resource, for an image whose
+// build was skipped
+func upToDateEvent(id string) api.Resource {
+	return newEvent("Image "+id, api.Done, api.StatusUpToDate)
+}
+
 // waiting creates a new waiting event; kept as a named func for use as a function value.
 func waiting(id string) api.Resource {
 	return newEvent(id, api.Working, api.StatusWaiting)
@@ -42,6 +42,9 @@ func toReconcileOptions(options api.CreateOptions) ReconcileOptions {
 		Timeout:              options.Timeout,
 		RemoveOrphans:        options.RemoveOrphans,
 		SkipProviders:        options.SkipProviders,
+		RollbackOnFailure:    options.RollbackOnFailure,
+		Explain:              options.Explain,
+		NoStart:              options.NoStart,
 	}
 }
 
@@ -54,6 +57,9 @@ type ReconcileOptions struct {
 	Timeout              *time.Duration // for stop operations
 	RemoveOrphans        bool
 	SkipProviders        bool
+	RollbackOnFailure    bool // keep replaced containers until replacements are healthy, restore them on failure
+	Explain              bool // record why containers are recreated or updated, see Operation.Divergences
+	NoStart              bool // containers are not started once created, no rolling update is planned
 }
 
 // reconciler compares a types.Project (desired state) with an ObservedState
@@ -88,6 +94,10 @@ type reconciler struct {
 	// reconcileContainers later recreates the same container, its RemoveContainer
 	// must wait for these reconnects so they don't race the removal.
 	connectNodes map[string][]*PlanNode // container ID → reconnect nodes
+	// recreatedNetworks is the set of compose network keys planRecreateNetworks
+	// recreates: planNetworkAttachments leaves the containers attached to them
+	// to its reconnects.
+	recreatedNetworks map[string]bool
 
 	// recreatedServices is the set of services with at least one container
 	// scheduled for recreation in the current plan. Services iterate in
@@ -114,22 +124,7 @@ type reconciler struct {
 // The prompt function is consulted while planning to confirm destructive
 // decisions (see the reconciler.prompt field).
 func reconcile(_ context.Context, project *types.Project, observed *ObservedState, options ReconcileOptions, prompt Prompt) (*Plan, error) {
-	r := &reconciler{
-		project:                     project,
-		observed:                    observed,
-		options:                     options,
-		prompt:                      prompt,
-		plan:                        &Plan{},
-		networkNodes:                map[string]*PlanNode{},
-		volumeNodes:                 map[string]*PlanNode{},
-		serviceNodes:                map[string]*PlanNode{},
-		stoppedByPlan:               map[string]*PlanNode{},
-		connectNodes:                map[string][]*PlanNode{},
-		recreatedServices:           map[string]bool{},
-		observedContainersByService: observed.containersByService(),
-	}
-
-	r.resolveObserved()
+	r := newReconciler(project, observed, options, prompt)
 
 	if err := r.reconcileNetworks(); err != nil {
 		return nil, err
@@ -150,6 +145,28 @@ func reconcile(_ context.Context, project *types.Project, observed *ObservedStat
 	return r.plan, nil
 }
 
+// newReconciler returns a reconciler with an empty plan, the observed
+// networks and volumes already resolved.
+func newReconciler(project *types.Project, observed *ObservedState, options ReconcileOptions, prompt Prompt) *reconciler {
+	r := &reconciler{
+		project:                     project,
+		observed:                    observed,
+		options:                     options,
+		prompt:                      prompt,
+		plan:                        &Plan{},
+		networkNodes:                map[string]*PlanNode{},
+		volumeNodes:                 map[string]*PlanNode{},
+		serviceNodes:                map[string]*PlanNode{},
+		stoppedByPlan:               map[string]*PlanNode{},
+		connectNodes:                map[string][]*PlanNode{},
+		recreatedNetworks:           map[string]bool{},
+		recreatedServices:           map[string]bool{},
+		observedContainersByService: observed.containersByService(),
+	}
+	r.resolveObserved()
+	return r
+}
+
 // resolveObserved selects, for every declared network and volume, the single
 // live resource that matches it best (see selectNetwork/selectVolume) and stores
 // it in resolvedNetworks/resolvedVolumes — the only observed views the rest of
@@ -254,6 +271,7 @@ func (r *reconciler) planRecreateNetworks(keys []string) {
 		observed := r.resolvedNetworks[key]
 		desired := r.project.Networks[key]
 		containers := r.containersForServices(r.servicesUsingNetwork(key))
+		r.recreatedNetworks[key] = true
 
 		// Stop then disconnect every attached container.
 		var disconnectNodes []*PlanNode
@@ -670,19 +688,35 @@ func (r *reconciler) reconcileService(service types.ServiceConfig) error {
 	// Precompute once per service: mustRecreate is called twice per container
 	// (sortContainers + main loop) and the hash/cascade inputs depend on the
 	// service, not the container.
-	expectedHash, err := serviceHashWithResolvedRefs(service, r.observedContainersByService)
+	hashes, err := expectedServiceHashes(service, r.observedContainersByService)
 	if err != nil {
 		return err
 	}
 	parentRecreated := r.parentNamespaceRecreated(service)
+	if r.options.Explain {
+		hashes.fields, err = serviceFieldHashes(withResolvedServiceRefs(service, r.observedContainersByService))
+		if err != nil {
+			return err
+		}
+	}
 
 	// Sort containers: obsolete first, then by number descending, then reverse
 	// to get the same ordering as the existing convergence code.
-	r.sortContainers(containers, service, expectedHash, parentRecreated, strategy)
+	r.sortContainers(containers, service, hashes, parentRecreated, strategy)
 
 	// Collect dependency nodes that container creation should depend on
 	infraDeps := r.infrastructureDeps(service)
 
+	rolling, err := newRollingUpdate(service, r.options.RollbackOnFailure)
+	if err != nil {
+		return err
+	}
+	if r.options.NoStart {
+		// A rolling update starts the replacements: containers which are not
+		// started afterward are recreated as is.
+		rolling = nil
+	}
+
 	var lastNode *PlanNode
 
 	// Process existing containers
@@ -707,13 +741,40 @@ func (r *reconciler) reconcileService(service types.ServiceConfig) error {
 			continue
 		}
 
-		if r.mustRecreate(service, expectedHash, parentRecreated, oc, strategy) {
-			lastNode = r.planRecreateContainer(service, &containers[i], infraDeps)
+		if r.mustRecreate(service, hashes, parentRecreated, oc, strategy) {
+			var divergences []string
+			if r.options.Explain {
+				divergences = r.explainRecreate(service, hashes, parentRecreated, oc, strategy)
+			}
+			// Only a running replica is replaced by a running one: others are
+			// recreated as is, the rolling update must not start them.
+			if rolling != nil && oc.State == container.StateRunning {
+				lastNode = r.planRollingRecreate(rolling, &containers[i], infraDeps, divergences)
+			} else {
+				lastNode = r.planRecreateContainer(service, &containers[i], infraDeps, divergences)
+			}
 			r.recreatedServices[service.Name] = true
 			continue
 		}
 
-		// Container is up-to-date
+		// Container is up-to-date, or only its resources, restart policy or
+		// networks changed and are updated in place
+		startDeps := infraDeps
+		if strategy != api.RecreateNever {
+			var updates []*PlanNode
+			if node := r.planUpdateContainer(service, &containers[i], infraDeps); node != nil {
+				updates = append(updates, node)
+			}
+			attachments, err := r.planNetworkAttachments(service, &containers[i], infraDeps)
+			if err != nil {
+				return err
+			}
+			updates = append(updates, attachments...)
+			if len(updates) > 0 {
+				lastNode = updates[len(updates)-1]
+				startDeps = updates
+			}
+		}
 		switch oc.State {
 		case container.StateRunning, container.StateCreated, container.StateRestarting, container.StateExited:
 			// Nothing to do (exited containers are left as-is, matching convergence.go behavior)
@@ -724,7 +785,13 @@ func (r *reconciler) reconcileService(service types.ServiceConfig) error {
 				ResourceID: fmt.Sprintf("service:%s:%d", service.Name, oc.Number),
 				Cause:      "not running",
 				Container:  &containers[i].Summary,
-			}, "", infraDeps...)
+			}, "", startDeps...)
+		}
+	}
+
+	if rolling != nil {
+		if node := r.finishRollingUpdate(rolling); node != nil {
+			lastNode = node
 		}
 	}
 
@@ -751,32 +818,52 @@ func (r *reconciler) reconcileService(service types.ServiceConfig) error {
 }
 
 // mustRecreate decides whether oc must be recreated to match expected. The
-// expectedHash and parentRecreated inputs are precomputed once per service by
+// hashes and parentRecreated inputs are precomputed once per service by
 // reconcileService — see expectedConfigHash and parentNamespaceRecreated for
-// the rationale (issue #13878).
-func (r *reconciler) mustRecreate(expected types.ServiceConfig, expectedHash string, parentRecreated bool, oc ObservedContainer, policy string) bool {
+// the rationale (issue #13878). A diverged configuration hash does not force
+// recreation when only settings updatable in place changed, see
+// updatableInPlace.
+func (r *reconciler) mustRecreate(expected types.ServiceConfig, hashes expectedHashes, parentRecreated bool, oc ObservedContainer, policy string) bool {
+	return len(r.recreateCauses(expected, hashes, parentRecreated, oc, policy, false)) > 0
+}
+
+// recreateCauses lists why oc must be recreated, see mustRecreate, or returns
+// nil if it is up to date. Unless explain is set, it stops at the first cause
+// and doesn't detail which fields of the configuration changed.
+func (r *reconciler) recreateCauses(expected types.ServiceConfig, hashes expectedHashes, parentRecreated bool, oc ObservedContainer, policy string, explain bool) []string {
 	switch policy {
 	case api.RecreateNever:
-		return false
+		return nil
 	case api.RecreateForce:
-		return true
+		return []string{"recreation forced"}
+	}
+	var causes []string
+	// diverged records the causes of a divergence, and tells whether to stop
+	diverged := func(cause ...string) bool {
+		causes = append(causes, cause...)
+		return !explain
 	}
-	if parentRecreated {
-		return true
+	if parentRecreated && diverged("a service it shares namespaces or volumes with is recreated") {
+		return causes
 	}
-	if oc.ConfigHash != expectedHash {
-		return true
+	if oc.ConfigHash != hashes.config && !updatableInPlace(expected, hashes, oc) && diverged(configChanges(expected, hashes, oc, explain)...) {
+		return causes
 	}
-	if oc.ImageDigest != expected.CustomLabels[api.ImageDigestLabel] {
-		return true
+	if oc.ImageDigest != expected.CustomLabels[api.ImageDigestLabel] && diverged("image digest changed") {
+		return causes
 	}
-	if oc.ImageVolumeDigest != expected.CustomLabels[api.ImageVolumeDigestLabel] {
-		return true
+	if oc.ImageVolumeDigest != expected.CustomLabels[api.ImageVolumeDigestLabel] && diverged("image volume digest changed") {
+		return causes
 	}
-	if oc.State == container.StateRunning && r.hasNetworkMismatch(expected, oc) {
-		return true
+	// containers carrying an immutable hash are reconnected instead, see
+	// planNetworkAttachments
+	if oc.State == container.StateRunning && oc.ImmutableConfigHash == "" && r.hasNetworkMismatch(expected, oc) && diverged("not connected to all its networks") {
+		return causes
 	}
-	return r.hasVolumeMismatch(expected, oc)
+	if r.hasVolumeMismatch(expected, oc) {
+		diverged("not mounting all its volumes")
+	}
+	return causes
 }
 
 // parentNamespaceRecreated reports whether any namespace- or volume-sharing
@@ -812,10 +899,29 @@ func (r *reconciler) parentNamespaceRecreated(service types.ServiceConfig) bool
 // service.Networks (a map) is left shared because resolveServiceReferences does
 // not touch it; revisit if that changes.
 func serviceHashWithResolvedRefs(service types.ServiceConfig, containers map[string]Containers) (string, error) {
+	return ServiceHash(withResolvedServiceRefs(service, containers))
+}
+
+// expectedServiceHashes returns the configuration hashes persisted at create
+// time, see serviceHashWithResolvedRefs.
+func expectedServiceHashes(service types.ServiceConfig, containers map[string]Containers) (expectedHashes, error) {
+	resolved := withResolvedServiceRefs(service, containers)
+	config, err := ServiceHash(resolved)
+	if err != nil {
+		return expectedHashes{}, err
+	}
+	immutable, err := immutableServiceHash(resolved)
+	if err != nil {
+		return expectedHashes{}, err
+	}
+	return expectedHashes{config: config, immutable: immutable}, nil
+}
+
+func withResolvedServiceRefs(service types.ServiceConfig, containers map[string]Containers) types.ServiceConfig {
 	resolved := service
 	resolved.VolumesFrom = slices.Clone(service.VolumesFrom)
 	_ = resolveServiceReferences(&resolved, containers)
-	return ServiceHash(resolved)
+	return resolved
 }
 
 // hasNetworkMismatch checks if the container is not connected to all expected networks.
@@ -871,7 +977,7 @@ func (r *reconciler) hasVolumeMismatch(expected types.ServiceConfig, oc Observed
 
 // planRecreateContainer decomposes container recreation into 4 atomic operations:
 // CreateContainer(tmpName) → StopContainer → RemoveContainer → RenameContainer
-func (r *reconciler) planRecreateContainer(service types.ServiceConfig, oc *ObservedContainer, infraDeps []*PlanNode) *PlanNode {
+func (r *reconciler) planRecreateContainer(service types.ServiceConfig, oc *ObservedContainer, infraDeps []*PlanNode, divergences []string) *PlanNode {
 	resID := fmt.Sprintf("service:%s:%d", service.Name, oc.Number)
 	group := fmt.Sprintf("recreate:%s:%d", service.Name, oc.Number)
 	tmpName := fmt.Sprintf("%s_%s", oc.ID[:min(12, len(oc.ID))], getContainerName(r.project.Name, service, oc.Number))
@@ -890,13 +996,14 @@ func (r *reconciler) planRecreateContainer(service types.ServiceConfig, oc *Obse
 
 	// 1. Create new container with temporary name
 	createNode := r.plan.addNode(Operation{
-		Type:       OpCreateContainer,
-		ResourceID: resID,
-		Cause:      "config changed (tmpName)",
-		Service:    &serviceCopy,
-		Inherited:  inherited,
-		Number:     oc.Number,
-		Name:       tmpName,
+		Type:        OpCreateContainer,
+		ResourceID:  resID,
+		Cause:       "config changed (tmpName)",
+		Divergences: divergences,
+		Service:     &serviceCopy,
+		Inherited:   inherited,
+		Number:      oc.Number,
+		Name:        tmpName,
 	}, group, allDeps...)
 
 	// 2. Stop old container. If an earlier stage of the plan (e.g.
@@ -1014,10 +1121,10 @@ func (r *reconciler) infrastructureDeps(service types.ServiceConfig) []*PlanNode
 //
 // mustRecreate is evaluated once per container before sorting to avoid
 // quadratic re-evaluation in the comparator.
-func (r *reconciler) sortContainers(containers []ObservedContainer, service types.ServiceConfig, expectedHash string, parentRecreated bool, policy string) {
+func (r *reconciler) sortContainers(containers []ObservedContainer, service types.ServiceConfig, hashes expectedHashes, parentRecreated bool, policy string) {
 	obsolete := make(map[string]bool, len(containers))
 	for _, oc := range containers {
-		obsolete[oc.ID] = r.mustRecreate(service, expectedHash, parentRecreated, oc, policy)
+		obsolete[oc.ID] = r.mustRecreate(service, hashes, parentRecreated, oc, policy)
 	}
 	sort.Slice(containers, func(i, j int) bool {
 		obsi, obsj := obsolete[containers[i].ID], obsolete[containers[j].ID]
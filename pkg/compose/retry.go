@@ -0,0 +1,186 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+)
+
+// Classes of transient Engine errors a RetryPolicy can retry.
+const (
+	// RetryOnUnavailable retries when the Engine, or one of its components,
+	// is temporarily unavailable
+	RetryOnUnavailable = "unavailable"
+	// RetryOnTimeout retries operations which timed out
+	RetryOnTimeout = "timeout"
+	// RetryOnBusy retries when a resource is still in use by the kernel
+	// ("device or resource busy"), typically a volume still mounted
+	RetryOnBusy = "busy"
+	// RetryOnConflict retries when the Engine reports a conflicting
+	// operation, typically a concurrent creation or removal
+	RetryOnConflict = "conflict"
+)
+
+// RetryClasses lists the error classes a RetryPolicy accepts
+var RetryClasses = []string{RetryOnUnavailable, RetryOnTimeout, RetryOnBusy, RetryOnConflict}
+
+// RetryPolicy configures how the plan executor retries an operation which
+// failed with a transient Engine error.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times the operation is attempted, the
+	// first one included. Values lower than 2 disable retries.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each next one
+	Backoff time.Duration
+	// MaxBackoff caps the delay between two attempts, if set
+	MaxBackoff time.Duration
+	// Retryable lists the error classes (RetryOnUnavailable, RetryOnTimeout,
+	// ...) which are retried
+	Retryable []string
+}
+
+// DefaultRetryPolicies returns a retry policy per operation type, retrying the
+// errors each operation is known to fail with transiently under load. Waiting
+// for a container to be healthy and running providers are not retried: they
+// have their own timeouts. Creating a container or a network is not retried
+// on a timeout, see retrySafe.
+func DefaultRetryPolicies(maxAttempts int, backoff time.Duration) map[OperationType]RetryPolicy {
+	policy := func(classes ...string) RetryPolicy {
+		return RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+			MaxBackoff:  30 * time.Second,
+			Retryable:   classes,
+		}
+	}
+	return map[OperationType]RetryPolicy{
+		OpCreateNetwork:     policy(RetryOnUnavailable),
+		OpRemoveNetwork:     policy(RetryOnUnavailable, RetryOnTimeout, RetryOnConflict),
+		OpDisconnectNetwork: policy(RetryOnUnavailable, RetryOnTimeout, RetryOnConflict),
+		OpConnectNetwork:    policy(RetryOnUnavailable, RetryOnTimeout, RetryOnConflict),
+		OpCreateVolume:      policy(RetryOnUnavailable, RetryOnTimeout),
+		OpRemoveVolume:      policy(RetryOnUnavailable, RetryOnTimeout, RetryOnBusy, RetryOnConflict),
+		OpCreateContainer:   policy(RetryOnUnavailable),
+		OpStartContainer:    policy(RetryOnUnavailable, RetryOnTimeout, RetryOnBusy),
+		OpStopContainer:     policy(RetryOnUnavailable, RetryOnTimeout),
+		OpRemoveContainer:   policy(RetryOnUnavailable, RetryOnTimeout, RetryOnBusy, RetryOnConflict),
+		OpRenameContainer:   policy(RetryOnUnavailable, RetryOnTimeout),
+		OpUpdateContainer:   policy(RetryOnUnavailable, RetryOnTimeout),
+	}
+}
+
+// WithRetryPolicies configures the retry policy of each operation type the
+// plan executor performs. Operations without a policy are not retried.
+func WithRetryPolicies(policies map[OperationType]RetryPolicy) Option {
+	return func(s *composeService) error {
+		for _, policy := range policies {
+			for _, class := range policy.Retryable {
+				if !slices.Contains(RetryClasses, class) {
+					return fmt.Errorf("unknown retryable error class %q, expected one of %s", class, strings.Join(RetryClasses, ", "))
+				}
+			}
+		}
+		s.retryPolicies = policies
+		return nil
+	}
+}
+
+// retries tells whether err is in one of the error classes the policy retries.
+func (p RetryPolicy) retries(err error) bool {
+	for _, class := range p.Retryable {
+		if errorClass(class)(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// retrySafe tells whether an operation which failed with err can be attempted
+// again, whatever its retry policy. Creations are not idempotent: a creation
+// which timed out may have completed on the Engine side, and a conflict
+// creating a network may come from a creation which did, network names not
+// being unique. Retrying them would fail on the name conflict for a container,
+// or create a duplicate network.
+func retrySafe(op OperationType, err error) bool {
+	switch op {
+	case OpCreateContainer:
+		return !errorClass(RetryOnTimeout)(err)
+	case OpCreateNetwork:
+		return !errorClass(RetryOnTimeout)(err) && !errorClass(RetryOnConflict)(err)
+	default:
+		return true
+	}
+}
+
+func errorClass(class string) func(error) bool {
+	switch class {
+	case RetryOnUnavailable:
+		return errdefs.IsUnavailable
+	case RetryOnTimeout:
+		return func(err error) bool {
+			var netErr net.Error
+			return errdefs.IsDeadlineExceeded(err) || (errors.As(err, &netErr) && netErr.Timeout())
+		}
+	case RetryOnBusy:
+		return func(err error) bool {
+			return strings.Contains(err.Error(), "device or resource busy")
+		}
+	case RetryOnConflict:
+		return errdefs.IsConflict
+	default:
+		return func(error) bool { return false }
+	}
+}
+
+// delay returns the delay before the given retry, starting at 1.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < retry && (p.MaxBackoff == 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 {
+		d = min(d, p.MaxBackoff)
+	}
+	return d
+}
+
+// executeNodeWithRetry executes a node, retrying it as long as it fails with
+// an error its operation type's retry policy accepts. Retries are reported as
+// progress events.
+func (exec *planExecutor) executeNodeWithRetry(ctx context.Context, node *PlanNode, groups *groupTracker) error {
+	policy := exec.compose.retryPolicies[node.Operation.Type]
+	for attempt := 1; ; attempt++ {
+		err := exec.executeNode(ctx, node)
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.retries(err) || !retrySafe(node.Operation.Type, err) {
+			return err
+		}
+		groups.onNodeRetry(node, exec.compose.events, attempt+1, policy.MaxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-exec.compose.clock.After(policy.delay(attempt)):
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/client"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, policy.delay(1), time.Second)
+	assert.Equal(t, policy.delay(2), 2*time.Second)
+	assert.Equal(t, policy.delay(3), 4*time.Second)
+	assert.Equal(t, policy.delay(4), 5*time.Second)
+	assert.Equal(t, policy.delay(100), 5*time.Second)
+}
+
+func TestRetryPolicyRetries(t *testing.T) {
+	policy := RetryPolicy{Retryable: []string{RetryOnBusy, RetryOnConflict}}
+	assert.Assert(t, policy.retries(errors.New("remove /var/lib/docker/volumes/data: device or resource busy")))
+	assert.Assert(t, policy.retries(conflictError{}))
+	assert.Assert(t, !policy.retries(notFoundError{}))
+}
+
+func TestRetrySafe(t *testing.T) {
+	assert.Assert(t, !retrySafe(OpCreateContainer, context.DeadlineExceeded))
+	assert.Assert(t, retrySafe(OpCreateContainer, conflictError{}))
+	assert.Assert(t, !retrySafe(OpCreateNetwork, context.DeadlineExceeded))
+	assert.Assert(t, !retrySafe(OpCreateNetwork, conflictError{}))
+	assert.Assert(t, retrySafe(OpRemoveNetwork, conflictError{}))
+	assert.Assert(t, retrySafe(OpStartContainer, context.DeadlineExceeded))
+}
+
+func TestWithRetryPoliciesRejectsUnknownClass(t *testing.T) {
+	svc := &composeService{}
+	err := WithRetryPolicies(map[OperationType]RetryPolicy{
+		OpRemoveVolume: {MaxAttempts: 3, Retryable: []string{"flaky"}},
+	})(svc)
+	assert.ErrorContains(t, err, `unknown retryable error class "flaky"`)
+}
+
+func TestExecutePlanRetriesTransientErrors(t *testing.T) {
+	busy := errors.New("remove /var/lib/docker/volumes/test_data: device or resource busy")
+	run := func(t *testing.T, errs ...error) (*recordingEventProcessor, error) {
+		t.Helper()
+		svc, apiClient := newTestService(t)
+		events := &recordingEventProcessor{}
+		svc.events = events
+		svc.retryPolicies = map[OperationType]RetryPolicy{
+			OpRemoveVolume: {MaxAttempts: 3, Backoff: time.Millisecond, Retryable: []string{RetryOnBusy}},
+		}
+		for _, err := range errs {
+			apiClient.EXPECT().VolumeRemove(gomock.Any(), "test_data", gomock.Any()).Return(client.VolumeRemoveResult{}, err)
+		}
+
+		plan := &Plan{}
+		plan.addNode(Operation{Type: OpRemoveVolume, ResourceID: "volume:data", Name: "test_data"}, "")
+		return events, svc.executePlan(t.Context(), &types.Project{Name: "test"}, emptyObservedState("test"), plan)
+	}
+
+	t.Run("succeeds after retries", func(t *testing.T) {
+		events, err := run(t, busy, busy, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, events.contains("Volume test_data: Retrying (2/3)"), events.summary())
+		assert.Assert(t, events.contains("Volume test_data: Retrying (3/3)"), events.summary())
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		_, err := run(t, busy, busy, busy)
+		assert.Assert(t, errors.Is(err, busy))
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		events, err := run(t, conflictError{})
+		assert.ErrorContains(t, err, "conflict")
+		assert.Assert(t, !events.contains("Volume test_data: Retrying (2/3)"), events.summary())
+	})
+}
@@ -0,0 +1,255 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+)
+
+// deploy.update_config values, as defined by the Compose specification.
+const (
+	updateOrderStartFirst = "start-first"
+	updateOrderStopFirst  = "stop-first"
+
+	updateFailurePause    = "pause"
+	updateFailureContinue = "continue"
+	updateFailureRollback = "rollback"
+)
+
+// rollingUpdate plans the recreation of the diverged running replicas of a
+// service declaring deploy.update_config: replicas are replaced by batches of
+// `parallelism`, each replacement is started and must become healthy (or
+// running, without a healthcheck) before the next batch starts, `delay` later.
+type rollingUpdate struct {
+	service     types.ServiceConfig
+	config      types.UpdateConfig
+	parallelism int    // 0: all replicas in a single batch
+	cause       string // cause of the start and wait operations
+
+	// gate holds the wait nodes of the previous batch, the current batch
+	// waits for them. batch holds those of the current batch.
+	gate  []*PlanNode
+	batch []*PlanNode
+
+	// pending holds the replicas whose replaced container is only removed
+	// once all batches succeeded, when failure_action is rollback.
+	pending []pendingRemoval
+}
+
+type pendingRemoval struct {
+	oc         *ObservedContainer
+	createNode *PlanNode
+	deps       []*PlanNode
+}
+
+// newRollingUpdate returns the rolling update of service, or nil if it does
+// not declare deploy.update_config. With rollbackOnFailure, failure_action is
+// always rollback, and services without update_config are updated as a
+// single batch: their replaced containers are kept until all replacements are
+// healthy, and restored if the plan fails.
+func newRollingUpdate(service types.ServiceConfig, rollbackOnFailure bool) (*rollingUpdate, error) {
+	if service.Deploy == nil || service.Deploy.UpdateConfig == nil {
+		if !rollbackOnFailure {
+			return nil, nil
+		}
+		return &rollingUpdate{
+			service: service,
+			config:  types.UpdateConfig{FailureAction: updateFailureRollback},
+			cause:   "rollback on failure",
+		}, nil
+	}
+	config := *service.Deploy.UpdateConfig
+	switch config.Order {
+	case "", updateOrderStopFirst, updateOrderStartFirst:
+	default:
+		return nil, fmt.Errorf("service %q: unsupported update_config.order %q", service.Name, config.Order)
+	}
+	switch config.FailureAction {
+	case "", updateFailurePause, updateFailureContinue, updateFailureRollback:
+	default:
+		return nil, fmt.Errorf("service %q: unsupported update_config.failure_action %q", service.Name, config.FailureAction)
+	}
+	if rollbackOnFailure {
+		config.FailureAction = updateFailureRollback
+	}
+	parallelism := 1
+	if config.Parallelism != nil {
+		parallelism = int(*config.Parallelism)
+	}
+	return &rollingUpdate{
+		service:     service,
+		config:      config,
+		parallelism: parallelism,
+		cause:       "rolling update",
+	}, nil
+}
+
+// nextReplica opens a new batch if the current one is full, and returns the
+// nodes the replica creation must wait for.
+func (u *rollingUpdate) nextReplica() []*PlanNode {
+	if u.parallelism > 0 && len(u.batch) == u.parallelism {
+		// the delay applies between batches, set it once a next one exists
+		for _, node := range u.batch {
+			node.Operation.Delay = time.Duration(u.config.Delay)
+		}
+		u.gate, u.batch = u.batch, nil
+	}
+	return u.gate
+}
+
+// planRollingRecreate plans the replacement of oc as part of a rolling update:
+//
+//	stop-first:  CreateContainer(tmpName) → StopContainer → StartContainer → WaitContainer → RemoveContainer → RenameContainer
+//	start-first: CreateContainer(tmpName) → StartContainer → WaitContainer → StopContainer → RemoveContainer → RenameContainer
+//
+// With start-first, both containers run side by side until the replacement is
+// healthy, which requires the service not to bind fixed host ports.
+func (r *reconciler) planRollingRecreate(u *rollingUpdate, oc *ObservedContainer, infraDeps []*PlanNode, divergences []string) *PlanNode {
+	service := u.service
+	resID := fmt.Sprintf("service:%s:%d", service.Name, oc.Number)
+	group := fmt.Sprintf("recreate:%s:%d", service.Name, oc.Number)
+	tmpName := fmt.Sprintf("%s_%s", oc.ID[:min(12, len(oc.ID))], getContainerName(r.project.Name, service, oc.Number))
+	serviceCopy := service // copy for pointer stability
+
+	allDeps := append(slices.Clone(infraDeps), r.planStopDependents(service)...)
+	allDeps = append(allDeps, u.nextReplica()...)
+
+	var inherited *container.Summary
+	if r.options.Inherit {
+		inherited = &oc.Summary
+	}
+	var replaces *container.Summary
+	if u.config.FailureAction == updateFailureRollback {
+		replaces = &oc.Summary
+	}
+
+	createNode := r.plan.addNode(Operation{
+		Type:        OpCreateContainer,
+		ResourceID:  resID,
+		Cause:       "config changed (tmpName)",
+		Divergences: divergences,
+		Service:     &serviceCopy,
+		Inherited:   inherited,
+		Replaces:    replaces,
+		Number:      oc.Number,
+		Name:        tmpName,
+	}, group, allDeps...)
+
+	// Reuse a Stop already planned for this container, see planRecreateContainer.
+	stopNode, alreadyStopped := r.stoppedByPlan[oc.ID]
+	stop := func(deps ...*PlanNode) *PlanNode {
+		if alreadyStopped {
+			return stopNode
+		}
+		node := r.plan.addNode(Operation{
+			Type:       OpStopContainer,
+			ResourceID: resID,
+			Cause:      fmt.Sprintf("replaced by #%d", createNode.ID),
+			Container:  &oc.Summary,
+			Timeout:    r.options.Timeout,
+		}, group, deps...)
+		r.stoppedByPlan[oc.ID] = node
+		return node
+	}
+	start := func(deps ...*PlanNode) *PlanNode {
+		startNode := r.plan.addNode(Operation{
+			Type:         OpStartContainer,
+			ResourceID:   resID,
+			Cause:        u.cause,
+			Service:      &serviceCopy,
+			CreateNodeID: createNode.ID,
+		}, group, deps...)
+		waitNode := r.plan.addNode(Operation{
+			Type:          OpWaitContainer,
+			ResourceID:    resID,
+			Cause:         u.cause,
+			Service:       &serviceCopy,
+			CreateNodeID:  createNode.ID,
+			FailureAction: u.config.FailureAction,
+			Monitor:       time.Duration(u.config.Monitor),
+		}, group, startNode)
+		return waitNode
+	}
+
+	var waitNode *PlanNode
+	// The replaced container is removed once its replacement is healthy and
+	// it is stopped. A reused stop node does not depend on the wait node.
+	var removeDeps []*PlanNode
+	if u.config.Order == updateOrderStartFirst {
+		waitNode = start(createNode)
+		stopNode = stop(waitNode)
+		removeDeps = []*PlanNode{stopNode}
+		if alreadyStopped {
+			removeDeps = append(removeDeps, waitNode)
+		}
+	} else {
+		stopNode = stop(createNode)
+		waitNode = start(stopNode)
+		removeDeps = []*PlanNode{waitNode}
+	}
+	u.batch = append(u.batch, waitNode)
+
+	removeDeps = append(removeDeps, r.connectNodes[oc.ID]...)
+	if u.config.FailureAction == updateFailureRollback {
+		// Keep the replaced container until all batches succeeded, to restore
+		// it if a later batch fails.
+		u.pending = append(u.pending, pendingRemoval{oc: oc, createNode: createNode, deps: removeDeps})
+		return waitNode
+	}
+	return r.planFinalizeRecreate(service, oc, createNode, group, removeDeps...)
+}
+
+// finishRollingUpdate completes the plan of a rolling update once all
+// replicas were planned, and returns its last node (nil if none).
+func (r *reconciler) finishRollingUpdate(u *rollingUpdate) *PlanNode {
+	var last *PlanNode
+	for _, p := range u.pending {
+		group := fmt.Sprintf("recreate:%s:%d", u.service.Name, p.oc.Number)
+		deps := p.deps
+		for _, node := range u.batch {
+			if !slices.Contains(deps, node) {
+				deps = append(deps, node)
+			}
+		}
+		last = r.planFinalizeRecreate(u.service, p.oc, p.createNode, group, deps...)
+	}
+	return last
+}
+
+// planFinalizeRecreate plans the removal of the replaced container oc and the
+// rename of its replacement, created by createNode, to the final name.
+func (r *reconciler) planFinalizeRecreate(service types.ServiceConfig, oc *ObservedContainer, createNode *PlanNode, group string, deps ...*PlanNode) *PlanNode {
+	resID := fmt.Sprintf("service:%s:%d", service.Name, oc.Number)
+	removeNode := r.plan.addNode(Operation{
+		Type:       OpRemoveContainer,
+		ResourceID: resID,
+		Cause:      fmt.Sprintf("replaced by #%d", createNode.ID),
+		Container:  &oc.Summary,
+	}, group, deps...)
+	return r.plan.addNode(Operation{
+		Type:         OpRenameContainer,
+		ResourceID:   resID,
+		Cause:        "finalize recreate",
+		Name:         getContainerName(r.project.Name, service, oc.Number),
+		CreateNodeID: createNode.ID,
+	}, group, removeNode)
+}
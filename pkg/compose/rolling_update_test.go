@@ -0,0 +1,321 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// rollingUpdateProject returns a project whose web service declares config as
+// update_config, with replicas outdated containers observed.
+func rollingUpdateProject(config types.UpdateConfig, replicas int) (*types.Project, *ObservedState) {
+	svc := types.ServiceConfig{
+		Name:  "web",
+		Scale: intPtr(replicas),
+		Deploy: &types.DeployConfig{
+			UpdateConfig: &config,
+		},
+	}
+	var containers []ObservedContainer
+	for i := 1; i <= replicas; i++ {
+		id := fmt.Sprintf("c%daabbccddee", i)
+		containers = append(containers, ObservedContainer{
+			ID: id, Number: i, State: container.StateRunning, ConfigHash: "oldhash",
+			Summary: container.Summary{
+				ID: id, State: container.StateRunning,
+				Names: []string{fmt.Sprintf("/myproject-web-%d", i)},
+				Labels: map[string]string{
+					api.ServiceLabel: "web", api.ContainerNumberLabel: fmt.Sprint(i), api.ConfigHashLabel: "oldhash",
+				},
+			},
+		})
+	}
+	project := &types.Project{
+		Name:     "myproject",
+		Services: types.Services{"web": svc},
+	}
+	observed := &ObservedState{
+		ProjectName: "myproject",
+		Containers:  map[string][]ObservedContainer{"web": containers},
+		Networks:    map[string][]ObservedNetwork{},
+		Volumes:     map[string][]ObservedVolume{},
+	}
+	return project, observed
+}
+
+func uint64Ptr(i uint64) *uint64 {
+	return &i
+}
+
+func TestReconcileRollingUpdate_Batches(t *testing.T) {
+	project, observed := rollingUpdateProject(types.UpdateConfig{
+		Parallelism: uint64Ptr(2),
+		Delay:       types.Duration(10 * time.Second),
+	}, 3)
+
+	plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.NilError(t, err)
+
+	// replicas 1 and 2 are replaced together, replica 3 once both are healthy
+	assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[2] -> #3 service:web:1, StartContainer, rolling update [recreate:web:1]
+[3] -> #4 service:web:1, WaitContainer, rolling update [recreate:web:1]
+[4] -> #5 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[5] -> #6 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+[] -> #7 service:web:2, CreateContainer, config changed (tmpName) [recreate:web:2]
+[7] -> #8 service:web:2, StopContainer, replaced by #7 [recreate:web:2]
+[8] -> #9 service:web:2, StartContainer, rolling update [recreate:web:2]
+[9] -> #10 service:web:2, WaitContainer, rolling update [recreate:web:2]
+[10] -> #11 service:web:2, RemoveContainer, replaced by #7 [recreate:web:2]
+[11] -> #12 service:web:2, RenameContainer, finalize recreate [recreate:web:2]
+[4,10] -> #13 service:web:3, CreateContainer, config changed (tmpName) [recreate:web:3]
+[13] -> #14 service:web:3, StopContainer, replaced by #13 [recreate:web:3]
+[14] -> #15 service:web:3, StartContainer, rolling update [recreate:web:3]
+[15] -> #16 service:web:3, WaitContainer, rolling update [recreate:web:3]
+[16] -> #17 service:web:3, RemoveContainer, replaced by #13 [recreate:web:3]
+[17] -> #18 service:web:3, RenameContainer, finalize recreate [recreate:web:3]
+`)+"\n")
+
+	// the delay only applies between batches
+	assert.Equal(t, plan.Nodes[3].Operation.Delay, 10*time.Second)
+	assert.Equal(t, plan.Nodes[9].Operation.Delay, 10*time.Second)
+	assert.Equal(t, plan.Nodes[15].Operation.Delay, time.Duration(0))
+}
+
+func TestReconcileRollingUpdate_StartFirst(t *testing.T) {
+	project, observed := rollingUpdateProject(types.UpdateConfig{
+		Order: updateOrderStartFirst,
+	}, 1)
+
+	plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.NilError(t, err)
+
+	assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StartContainer, rolling update [recreate:web:1]
+[2] -> #3 service:web:1, WaitContainer, rolling update [recreate:web:1]
+[3] -> #4 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[4] -> #5 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[5] -> #6 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+`)+"\n")
+}
+
+func TestReconcileRollingUpdate_NoStart(t *testing.T) {
+	project, observed := rollingUpdateProject(types.UpdateConfig{
+		Order: updateOrderStartFirst,
+	}, 1)
+	options := defaultReconcileOptions()
+	options.NoStart = true
+
+	plan, err := reconcile(t.Context(), project, observed, options, noPrompt)
+	assert.NilError(t, err)
+
+	// the replacement is not started, as with update_config unset
+	assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[2] -> #3 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[3] -> #4 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+`)+"\n")
+}
+
+func TestReconcileRollingUpdate_Rollback(t *testing.T) {
+	project, observed := rollingUpdateProject(types.UpdateConfig{
+		FailureAction: updateFailureRollback,
+	}, 2)
+
+	plan, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.NilError(t, err)
+
+	// replaced containers are only removed once all batches are healthy
+	assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[2] -> #3 service:web:1, StartContainer, rolling update [recreate:web:1]
+[3] -> #4 service:web:1, WaitContainer, rolling update [recreate:web:1]
+[4] -> #5 service:web:2, CreateContainer, config changed (tmpName) [recreate:web:2]
+[5] -> #6 service:web:2, StopContainer, replaced by #5 [recreate:web:2]
+[6] -> #7 service:web:2, StartContainer, rolling update [recreate:web:2]
+[7] -> #8 service:web:2, WaitContainer, rolling update [recreate:web:2]
+[4,8] -> #9 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[9] -> #10 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+[8] -> #11 service:web:2, RemoveContainer, replaced by #5 [recreate:web:2]
+[11] -> #12 service:web:2, RenameContainer, finalize recreate [recreate:web:2]
+`)+"\n")
+
+	// replaced containers are restored if the plan fails before they are removed
+	assert.Equal(t, plan.Nodes[0].Operation.Replaces.ID, "c1aabbccddee")
+	assert.Equal(t, plan.Nodes[4].Operation.Replaces.ID, "c2aabbccddee")
+}
+
+func TestReconcileRollbackOnFailure(t *testing.T) {
+	project, observed := rollingUpdateProject(types.UpdateConfig{}, 2)
+	project.Services["web"] = types.ServiceConfig{Name: "web", Scale: intPtr(2)}
+	// an exited replica is recreated as is
+	observed.Containers["web"][1].State = container.StateExited
+
+	opts := defaultReconcileOptions()
+	opts.RollbackOnFailure = true
+	plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+
+	assert.Equal(t, plan.String(), strings.TrimSpace(`
+[] -> #1 service:web:1, CreateContainer, config changed (tmpName) [recreate:web:1]
+[1] -> #2 service:web:1, StopContainer, replaced by #1 [recreate:web:1]
+[2] -> #3 service:web:1, StartContainer, rollback on failure [recreate:web:1]
+[3] -> #4 service:web:1, WaitContainer, rollback on failure [recreate:web:1]
+[] -> #5 service:web:2, CreateContainer, config changed (tmpName) [recreate:web:2]
+[5] -> #6 service:web:2, StopContainer, replaced by #5 [recreate:web:2]
+[6] -> #7 service:web:2, RemoveContainer, replaced by #5 [recreate:web:2]
+[7] -> #8 service:web:2, RenameContainer, finalize recreate [recreate:web:2]
+[4] -> #9 service:web:1, RemoveContainer, replaced by #1 [recreate:web:1]
+[9] -> #10 service:web:1, RenameContainer, finalize recreate [recreate:web:1]
+`)+"\n")
+	assert.Equal(t, plan.Nodes[0].Operation.Replaces.ID, "c1aabbccddee")
+	assert.Assert(t, plan.Nodes[4].Operation.Replaces == nil)
+}
+
+func TestReconcileRollbackOnFailure_OverridesFailureAction(t *testing.T) {
+	project, observed := rollingUpdateProject(types.UpdateConfig{FailureAction: updateFailureContinue}, 1)
+
+	opts := defaultReconcileOptions()
+	opts.RollbackOnFailure = true
+	plan, err := reconcile(t.Context(), project, observed, opts, noPrompt)
+	assert.NilError(t, err)
+
+	assert.Equal(t, plan.Nodes[3].Operation.Type, OpWaitContainer)
+	assert.Equal(t, plan.Nodes[3].Operation.FailureAction, updateFailureRollback)
+	assert.Equal(t, plan.Nodes[0].Operation.Replaces.ID, "c1aabbccddee")
+}
+
+func TestReconcileRollingUpdate_InvalidConfig(t *testing.T) {
+	project, observed := rollingUpdateProject(types.UpdateConfig{Order: "random"}, 1)
+
+	_, err := reconcile(t.Context(), project, observed, defaultReconcileOptions(), noPrompt)
+	assert.ErrorContains(t, err, `unsupported update_config.order "random"`)
+}
+
+// rollingWaitPlan returns a plan waiting for the replacement container "new1"
+// of replica web-1, as created by the first node.
+func rollingWaitPlan(svc *composeService, action string) (*planExecutor, *Plan) {
+	service := types.ServiceConfig{Name: "web"}
+	old := container.Summary{
+		ID: "old1", State: container.StateRunning, Names: []string{"/test-web-1"},
+		Labels: map[string]string{api.ServiceLabel: "web", api.ContainerNumberLabel: "1"},
+	}
+	exec := svc.newPlanExecutor(&types.Project{Name: "test"}, emptyObservedState("test"))
+	exec.containersByService["web"] = Containers{{ID: "new1", Names: []string{"/old1_test-web-1"}}}
+
+	plan := &Plan{}
+	plan.nextID = 1 // node #1 is the (already executed) creation of new1
+	exec.pctx.set(1, operationResult{ContainerID: "new1"})
+	op := Operation{
+		Type:          OpWaitContainer,
+		ResourceID:    "service:web:1",
+		Service:       &service,
+		CreateNodeID:  1,
+		FailureAction: action,
+	}
+	if action == updateFailureRollback {
+		// registered by the (already executed) creation of new1
+		exec.replaced["old1"] = replacedContainer{container: old, createNodeID: 1}
+	}
+	plan.addNode(op, "recreate:web:1")
+	return exec, plan
+}
+
+func inspectState(state container.State) client.ContainerInspectResult {
+	return client.ContainerInspectResult{Container: container.InspectResponse{State: &state}}
+}
+
+func TestExecWaitContainer(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		svc, apiClient := newTestService(t)
+		exec, plan := rollingWaitPlan(svc, "")
+		gomock.InOrder(
+			apiClient.EXPECT().ContainerInspect(gomock.Any(), "new1", gomock.Any()).
+				Return(inspectState(container.State{Running: true, Health: &container.Health{Status: container.Starting}}), nil),
+			apiClient.EXPECT().ContainerInspect(gomock.Any(), "new1", gomock.Any()).
+				Return(inspectState(container.State{Running: true, Health: &container.Health{Status: container.Healthy}}), nil),
+		)
+		assert.NilError(t, exec.run(t.Context(), plan))
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		svc, apiClient := newTestService(t)
+		exec, plan := rollingWaitPlan(svc, "")
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "new1", gomock.Any()).
+			Return(inspectState(container.State{Running: true, Health: &container.Health{Status: container.Unhealthy}}), nil)
+		err := exec.run(t.Context(), plan)
+		assert.Error(t, err, `rolling update of service "web" paused: container old1_test-web-1 is unhealthy`)
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		svc, apiClient := newTestService(t)
+		exec, plan := rollingWaitPlan(svc, updateFailureContinue)
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "new1", gomock.Any()).
+			Return(inspectState(container.State{Restarting: true}), nil)
+		assert.NilError(t, exec.run(t.Context(), plan))
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		svc, apiClient := newTestService(t)
+		exec, plan := rollingWaitPlan(svc, updateFailureRollback)
+		apiClient.EXPECT().ContainerInspect(gomock.Any(), "new1", gomock.Any()).
+			Return(inspectState(container.State{Status: container.StateExited, ExitCode: 3}), nil)
+		gomock.InOrder(
+			apiClient.EXPECT().ContainerRemove(gomock.Any(), "new1", gomock.Any()).
+				Return(client.ContainerRemoveResult{}, nil),
+			apiClient.EXPECT().ContainerStart(gomock.Any(), "old1", gomock.Any()).
+				Return(client.ContainerStartResult{}, nil),
+		)
+		err := exec.run(t.Context(), plan)
+		assert.Error(t, err, `service "web": container old1_test-web-1 exited (3), rolled back`)
+	})
+}
+
+func TestExecutePlanRollbackSkipsRemovedContainers(t *testing.T) {
+	svc, apiClient := newTestService(t)
+	exec, plan := rollingWaitPlan(svc, updateFailureRollback)
+	// old1 is removed before the failure: its replacement can't be rolled back
+	old := exec.replaced["old1"].container
+	wait := plan.Nodes[0]
+	remove := plan.addNode(Operation{Type: OpRemoveContainer, ResourceID: "service:web:1", Container: &old}, "")
+	wait.DependsOn = []*PlanNode{remove}
+	plan.Nodes = []*PlanNode{remove, wait}
+
+	apiClient.EXPECT().ContainerRemove(gomock.Any(), "old1", gomock.Any()).
+		Return(client.ContainerRemoveResult{}, nil)
+	apiClient.EXPECT().ContainerInspect(gomock.Any(), "new1", gomock.Any()).
+		Return(inspectState(container.State{Running: true, Health: &container.Health{Status: container.Unhealthy}}), nil)
+
+	err := exec.run(t.Context(), plan)
+	assert.Error(t, err, `service "web": container old1_test-web-1 is unhealthy`)
+}
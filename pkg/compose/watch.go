@@ -15,6 +15,7 @@
 package compose
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -34,6 +35,7 @@ import (
 	ccli "github.com/docker/cli/cli/command/container"
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/moby/buildkit/util/progress/progressui"
+	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"github.com/sirupsen/logrus"
@@ -66,8 +68,13 @@ func NewWatcher(project *types.Project, options api.UpOptions, w WatchFunc, cons
 			return &Watcher{
 				project: project,
 				options: api.WatchOptions{
-					LogTo: consumer,
-					Build: build,
+					LogTo:           consumer,
+					Build:           build,
+					ReloadProject:   options.Start.ReloadProject,
+					IncludedFiles:   options.Start.IncludedFiles,
+					QuietPeriod:     options.Start.WatchQuietPeriod,
+					RebuildInterval: options.Start.WatchRebuildInterval,
+					ImagesInterval:  options.Start.WatchImagesInterval,
 				},
 				watchFn: w,
 				errCh:   make(chan error),
@@ -111,12 +118,13 @@ func (w *Watcher) Stop() error {
 	return err
 }
 
-// getSyncImplementation returns an appropriate sync implementation for the
-// project.
+// defaultSyncer returns the sync implementation of the services not
+// selecting one.
 //
 // Currently, an implementation that batches files and transfers them using
-// the Moby `Untar` API.
-func (s *composeService) getSyncImplementation(project *types.Project) (sync.Syncer, error) {
+// the Moby `Untar` API. With COMPOSE_EXPERIMENTAL_WATCH_MANIFEST set, only the
+// files whose content differs from the copy in the container are transferred.
+func (s *composeService) defaultSyncer(project *types.Project) (sync.Syncer, error) {
 	var useTar bool
 	if useTarEnv, ok := os.LookupEnv("COMPOSE_EXPERIMENTAL_WATCH_TAR"); ok {
 		useTar, _ = strconv.ParseBool(useTarEnv)
@@ -127,6 +135,9 @@ func (s *composeService) getSyncImplementation(project *types.Project) (sync.Syn
 		return nil, errors.New("no available sync implementation")
 	}
 
+	if useManifest, _ := strconv.ParseBool(os.Getenv("COMPOSE_EXPERIMENTAL_WATCH_MANIFEST")); useManifest {
+		return sync.NewManifest(project.Name, tarDockerClient{s: s}), nil
+	}
 	return sync.NewTar(project.Name, tarDockerClient{s: s}), nil
 }
 
@@ -143,6 +154,16 @@ type watchRule struct {
 	include watch.PathMatcher
 	ignore  watch.PathMatcher
 	service string
+	// quietPeriod overrides WatchOptions.QuietPeriod if set
+	quietPeriod time.Duration
+	// rebuildInterval overrides WatchOptions.RebuildInterval if set
+	rebuildInterval time.Duration
+	// reverseSyncInterval is the delay between two snapshots of the target
+	// files in the container when they are synced back to the host, zero if
+	// they aren't
+	reverseSyncInterval time.Duration
+	// permissions overrides the owner and permissions of the synced files
+	permissions *sync.Permissions
 }
 
 func (r watchRule) Matches(event watch.FileEvent) *sync.PathMapping {
@@ -183,10 +204,20 @@ func (r watchRule) Matches(event watch.FileEvent) *sync.PathMapping {
 	return &sync.PathMapping{
 		HostPath:      hostPath,
 		ContainerPath: containerPath,
+		Permissions:   r.permissions,
 	}
 }
 
 func (s *composeService) watch(ctx context.Context, project *types.Project, options api.WatchOptions) (func() error, error) {
+	if options.ReloadProject != nil {
+		return s.watchWithReload(ctx, project, options)
+	}
+	return s.watchProject(ctx, project, options)
+}
+
+// watchProject watches the files of the services with a develop section and
+// applies their watch rules.
+func (s *composeService) watchProject(ctx context.Context, project *types.Project, options api.WatchOptions) (func() error, error) {
 	var err error
 	if project, err = project.WithSelectedServices(options.Services); err != nil {
 		return nil, err
@@ -233,27 +264,44 @@ func (s *composeService) watch(ctx context.Context, project *types.Project, opti
 		rules = append(rules, serviceWatchRules...)
 	}
 
-	if len(paths) == 0 {
+	if len(paths) == 0 && options.ImagesInterval == 0 {
 		return nil, fmt.Errorf("none of the selected services is configured for watch, consider setting a 'develop' section")
 	}
 
-	watcher, err := watch.NewWatcher(paths)
-	if err != nil {
-		return nil, err
-	}
+	var watcher watch.Notify
+	if len(paths) > 0 {
+		watcher, err = s.newFileWatcher(project, paths, rules)
+		if err != nil {
+			return nil, err
+		}
 
-	err = watcher.Start()
-	if err != nil {
-		return nil, err
-	}
+		err = watcher.Start()
+		if err != nil {
+			return nil, err
+		}
 
-	eg.Go(func() error {
-		return s.watchEvents(ctx, project, options, watcher, syncer, rules)
-	})
+		reverse := newReverseSync(project.Name, rules, tarDockerClient{s: s})
+		if reverse != nil {
+			eg.Go(func() error {
+				return reverse.run(ctx, s, options)
+			})
+		}
+		eg.Go(func() error {
+			return s.watchEvents(ctx, project, options, watcher, syncer, rules, reverse)
+		})
+	}
+	if options.ImagesInterval > 0 {
+		eg.Go(func() error {
+			return s.watchImages(ctx, project, options)
+		})
+	}
 	options.LogTo.Log(api.WatchLogger, "Watch enabled")
 
 	return func() error {
 		err := eg.Wait()
+		if watcher == nil {
+			return err
+		}
 		if werr := watcher.Close(); werr != nil {
 			logrus.Debugf("Error closing Watcher: %v", werr)
 		}
@@ -340,6 +388,11 @@ func getWatchRules(config *types.DevelopConfig, service types.ServiceConfig) ([]
 			return nil, err
 		}
 
+		gitIgnore, err := gitIgnoreMatcher(trigger)
+		if err != nil {
+			return nil, err
+		}
+
 		var include watch.PathMatcher
 		if len(trigger.Include) == 0 {
 			include = watch.AnyMatcher{}
@@ -350,6 +403,23 @@ func getWatchRules(config *types.DevelopConfig, service types.ServiceConfig) ([]
 			}
 		}
 
+		quietPeriod, err := durationExtension(trigger, watchQuietPeriodExtension)
+		if err != nil {
+			return nil, err
+		}
+		rebuildInterval, err := durationExtension(trigger, watchRebuildIntervalExtension)
+		if err != nil {
+			return nil, err
+		}
+		reverseSyncInterval, err := reverseSyncExtension(trigger)
+		if err != nil {
+			return nil, err
+		}
+		permissions, err := permissionsExtension(trigger)
+		if err != nil {
+			return nil, err
+		}
+
 		rules = append(rules, watchRule{
 			Trigger: trigger,
 			include: include,
@@ -358,23 +428,49 @@ func getWatchRules(config *types.DevelopConfig, service types.ServiceConfig) ([]
 				watch.EphemeralPathMatcher(),
 				dotGitIgnore,
 				ignore,
+				gitIgnore,
 			),
-			service: service.Name,
+			service:             service.Name,
+			quietPeriod:         quietPeriod,
+			rebuildInterval:     rebuildInterval,
+			reverseSyncInterval: reverseSyncInterval,
+			permissions:         permissions,
 		})
 	}
 	return rules, nil
 }
 
+// watchGitIgnoreExtension makes a watch rule also ignore the files git ignores
+const watchGitIgnoreExtension = "x-gitignore"
+
+// gitIgnoreMatcher returns the matcher of the files git ignores if the watch
+// rule opted into it.
+func gitIgnoreMatcher(trigger types.Trigger) (watch.PathMatcher, error) {
+	v, ok := trigger.Extensions[watchGitIgnoreExtension]
+	if !ok {
+		return watch.EmptyMatcher{}, nil
+	}
+	enabled, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("watch rule %s: %s must be a boolean, got %v", trigger.Path, watchGitIgnoreExtension, v)
+	}
+	if !enabled {
+		return watch.EmptyMatcher{}, nil
+	}
+	return watch.NewGitIgnoreMatcher(trigger.Path)
+}
+
 func isSync(trigger types.Trigger) bool {
 	return trigger.Action == types.WatchActionSync || trigger.Action == types.WatchActionSyncRestart
 }
 
-func (s *composeService) watchEvents(ctx context.Context, project *types.Project, options api.WatchOptions, watcher watch.Notify, syncer sync.Syncer, rules []watchRule) error {
+func (s *composeService) watchEvents(ctx context.Context, project *types.Project, options api.WatchOptions, watcher watch.Notify, syncer sync.Syncer, rules []watchRule, reverse *reverseSync) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	// debounce and group filesystem events so that we capture IDE saving many files as one "batch" event
-	batchEvents := watch.BatchDebounceEvents(ctx, s.clock, watcher.Events())
+	batchEvents := s.debounceWatchEvents(ctx, watcher.Events(), rules, options.QuietPeriod)
+	throttle := newRebuildThrottle(s.clock, rules, options.RebuildInterval)
 
 	for {
 		select {
@@ -392,19 +488,37 @@ func (s *composeService) watchEvents(ctx context.Context, project *types.Project
 			}
 			_ = watcher.Close()
 			return err
-		case batch, ok := <-batchEvents:
+		case <-throttle.C():
+			services := throttle.due()
+			if len(services) == 0 {
+				continue
+			}
+			err := s.watchAction(options, string(types.WatchActionRebuild), services, nil, func() error {
+				return s.rebuild(ctx, project, services, options)
+			})
+			throttle.rebuilt(services)
+			if err != nil {
+				logrus.Warnf("Error handling changed files: %v", err)
+			}
+		case rb, ok := <-batchEvents:
 			if !ok {
 				options.LogTo.Log(api.WatchLogger, "Watch disabled")
 				_ = watcher.Close()
 				return nil
 			}
+			batch := reverse.dropEchoes(rb.events)
+			if len(batch) == 0 {
+				continue
+			}
 			if len(batch) > 1000 {
 				logrus.Warnf("Very large batch of file changes detected: %d files. This may impact performance.", len(batch))
 				options.LogTo.Log(api.WatchLogger, "Large batch of file changes detected. If you just switched branches, this is expected.")
 			}
-			start := time.Now()
+			start := s.clock.Now()
 			logrus.Debugf("batch start: count[%d]", len(batch))
-			err := s.handleWatchBatch(ctx, project, options, batch, rules, syncer)
+			s.emitWatchEvent(options, api.WatchEvent{Type: api.WatchEventBatch, Files: batchFiles(batch)})
+			err := s.handleWatchBatch(ctx, project, options, batch, rb.rules, syncer, throttle)
+			s.emitWatchEvent(options, api.WatchEvent{Type: api.WatchEventBatchDone, Duration: s.clock.Since(start), Error: err})
 			if err != nil {
 				logrus.Warnf("Error handling changed files: %v", err)
 				// If context was canceled, exit immediately
@@ -413,7 +527,7 @@ func (s *composeService) watchEvents(ctx context.Context, project *types.Project
 					return ctx.Err()
 				}
 			}
-			logrus.Debugf("batch complete: duration[%s] count[%d]", time.Since(start), len(batch))
+			logrus.Debugf("batch complete: duration[%s] count[%d]", s.clock.Since(start), len(batch))
 		}
 	}
 }
@@ -484,9 +598,21 @@ func (t tarDockerClient) ContainersForService(ctx context.Context, projectName s
 }
 
 func (t tarDockerClient) Exec(ctx context.Context, containerID string, cmd []string, in io.Reader) error {
+	return t.exec(ctx, containerID, cmd, in, nil)
+}
+
+func (t tarDockerClient) ExecOutput(ctx context.Context, containerID string, cmd []string) ([]byte, error) {
+	var out bytes.Buffer
+	err := t.exec(ctx, containerID, cmd, nil, &out)
+	return out.Bytes(), err
+}
+
+// exec runs cmd in the container, forwarding its error output. Its standard
+// output is captured to stdout if set, discarded otherwise.
+func (t tarDockerClient) exec(ctx context.Context, containerID string, cmd []string, in io.Reader, stdout io.Writer) error {
 	execCreateResp, err := t.s.apiClient().ExecCreate(ctx, containerID, client.ExecCreateOptions{
 		Cmd:          cmd,
-		AttachStdout: false,
+		AttachStdout: stdout != nil,
 		AttachStderr: true,
 		AttachStdin:  in != nil,
 		TTY:          false,
@@ -514,6 +640,10 @@ func (t tarDockerClient) Exec(ctx context.Context, containerID string, cmd []str
 		})
 	}
 	eg.Go(func() error {
+		if stdout != nil {
+			_, err := stdcopy.StdCopy(stdout, t.s.stderr(), conn.Reader)
+			return err
+		}
 		_, err := io.Copy(t.s.stdout(), conn.Reader)
 		return err
 	})
@@ -546,6 +676,72 @@ func (t tarDockerClient) Exec(ctx context.Context, containerID string, cmd []str
 	return nil
 }
 
+// ExecStream starts cmd as root in the container, forwarding its error output.
+func (t tarDockerClient) ExecStream(ctx context.Context, containerID string, cmd []string) (io.WriteCloser, io.ReadCloser, error) {
+	execCreateResp, err := t.s.apiClient().ExecCreate(ctx, containerID, client.ExecCreateOptions{
+		User:         "0",
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+		AttachStdin:  true,
+		TTY:          false,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// attaching starts the command
+	conn, err := t.s.apiClient().ExecAttach(ctx, execCreateResp.ID, client.ExecAttachOptions{
+		TTY: false,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stdout, w := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(w, t.s.stderr(), conn.Reader)
+		_ = w.CloseWithError(err)
+	}()
+	return execStdin{conn: conn}, execStdout{PipeReader: stdout, conn: conn}, nil
+}
+
+// execStdin writes to the standard input of an exec, closing it on Close.
+type execStdin struct {
+	conn client.ExecAttachResult
+}
+
+func (in execStdin) Write(p []byte) (int, error) {
+	return in.conn.Conn.Write(p)
+}
+
+func (in execStdin) Close() error {
+	return in.conn.CloseWrite()
+}
+
+// execStdout reads the standard output of an exec, releasing the connection
+// on Close.
+type execStdout struct {
+	*io.PipeReader
+	conn client.ExecAttachResult
+}
+
+func (out execStdout) Close() error {
+	out.conn.Close()
+	return out.PipeReader.Close()
+}
+
+// CopyFrom returns a tar archive of a path in the container.
+func (t tarDockerClient) CopyFrom(ctx context.Context, containerID, path string) (io.ReadCloser, error) {
+	res, err := t.s.apiClient().CopyFromContainer(ctx, containerID, client.CopyFromContainerOptions{
+		SourcePath: path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Content, nil
+}
+
 func (t tarDockerClient) Untar(ctx context.Context, id string, archive io.ReadCloser) error {
 	_, err := t.s.apiClient().CopyToContainer(ctx, id, client.CopyToContainerOptions{
 		DestinationPath: "/",
@@ -555,12 +751,13 @@ func (t tarDockerClient) Untar(ctx context.Context, id string, archive io.ReadCl
 	return err
 }
 
-func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Project, options api.WatchOptions, batch []watch.FileEvent, rules []watchRule, syncer sync.Syncer) error {
+func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Project, options api.WatchOptions, batch []watch.FileEvent, rules []watchRule, syncer sync.Syncer, throttle *rebuildThrottle) error {
 	var (
 		restart   = map[string]bool{}
 		syncfiles = map[string][]*sync.PathMapping{}
 		exec      = map[string][]int{}
 		rebuild   = map[string]bool{}
+		matches   = map[int][]string{}
 	)
 	for _, event := range batch {
 		for i, rule := range rules {
@@ -568,6 +765,7 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 			if mapping == nil {
 				continue
 			}
+			matches[i] = append(matches[i], mapping.HostPath)
 
 			switch rule.Action {
 			case types.WatchActionRebuild:
@@ -588,28 +786,60 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 		}
 	}
 
+	for _, i := range utils.MapKeys(matches) {
+		s.emitWatchEvent(options, api.WatchEvent{
+			Type:     api.WatchEventMatch,
+			Services: []string{rules[i].service},
+			Path:     rules[i].Path,
+			Action:   string(rules[i].Action),
+			Files:    matches[i],
+		})
+	}
+
 	logrus.Debugf("watch actions: rebuild %d sync %d restart %d", len(rebuild), len(syncfiles), len(restart))
 
 	if len(rebuild) > 0 {
-		err := s.rebuild(ctx, project, utils.MapKeys(rebuild), options)
-		if err != nil {
-			return err
+		services, delayed := throttle.allow(utils.MapKeys(rebuild))
+		for _, service := range utils.MapKeys(delayed) {
+			options.LogTo.Log(api.WatchLogger, fmt.Sprintf("Rebuild of service %q delayed by %s to respect its rebuild interval", service, delayed[service].Round(time.Millisecond)))
+		}
+		if len(services) > 0 {
+			err := s.watchAction(options, string(types.WatchActionRebuild), services, nil, func() error {
+				return s.rebuild(ctx, project, services, options)
+			})
+			throttle.rebuilt(services)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	for serviceName, pathMappings := range syncfiles {
 		writeWatchSyncMessage(options.LogTo, serviceName, pathMappings)
-		err := syncer.Sync(ctx, serviceName, pathMappings)
+		// a file matched by several rules is synced to each of their targets
+		var files []string
+		seen := map[string]bool{}
+		for _, mapping := range pathMappings {
+			if !seen[mapping.HostPath] {
+				seen[mapping.HostPath] = true
+				files = append(files, mapping.HostPath)
+			}
+		}
+		err := s.watchAction(options, string(types.WatchActionSync), []string{serviceName}, files, func() error {
+			return syncer.Sync(ctx, serviceName, pathMappings)
+		})
 		if err != nil {
 			return err
 		}
 	}
 	if len(restart) > 0 {
 		services := utils.MapKeys(restart)
-		err := s.restart(ctx, project.Name, api.RestartOptions{
-			Services: services,
-			Project:  project,
-			NoDeps:   false,
+		err := s.watchAction(options, string(types.WatchActionRestart), services, nil, func() error {
+			return s.restart(ctx, project.Name, api.RestartOptions{
+				Services: services,
+				Project:  project,
+				NoDeps:   false,
+			})
 		})
 		if err != nil {
 			return err
@@ -623,10 +853,15 @@ func (s *composeService) handleWatchBatch(ctx context.Context, project *types.Pr
 	for service, rulesToExec := range exec {
 		slices.Sort(rulesToExec)
 		for _, i := range slices.Compact(rulesToExec) {
-			err := s.exec(ctx, project, service, rules[i].Exec, eg)
-			if err != nil {
-				return err
-			}
+			eg.Go(func() error {
+				return s.watchAction(options, api.WatchActionExec, []string{service}, nil, func() error {
+					var containers errgroup.Group
+					if err := s.exec(ctx, project, service, rules[i].Exec, &containers); err != nil {
+						return err
+					}
+					return containers.Wait()
+				})
+			})
 		}
 	}
 	return eg.Wait()
@@ -693,7 +928,7 @@ func (s *composeService) rebuild(ctx context.Context, project *types.Project, se
 
 	options.LogTo.Log(api.WatchLogger, fmt.Sprintf("service(s) %q successfully built", services))
 
-	err = s.create(ctx, project, api.CreateOptions{
+	err = s.watchCreate(ctx, project, api.CreateOptions{
 		Services:      services,
 		Inherit:       true,
 		Recreate:      api.RecreateForce,
@@ -719,6 +954,50 @@ func (s *composeService) rebuild(ctx context.Context, project *types.Project, se
 	return nil
 }
 
+// watchCreate converges services after a change detected by watch. Watch can't
+// resume a plan left interrupted, the user is told to do it from another
+// terminal.
+func (s *composeService) watchCreate(ctx context.Context, project *types.Project, options api.CreateOptions) error {
+	err := s.create(ctx, project, options)
+	var interrupted *planInterruptedError
+	if errors.As(err, &interrupted) {
+		return fmt.Errorf("%w, run `docker compose up --resume` from another terminal to complete or revert the containers it left being recreated, watch then applies changes again", interrupted)
+	}
+	return err
+}
+
+// emitWatchEvent sends an event to the watch OnEvent callback, if any.
+func (s *composeService) emitWatchEvent(options api.WatchOptions, event api.WatchEvent) {
+	if options.OnEvent == nil {
+		return
+	}
+	event.Time = s.clock.Now()
+	options.OnEvent(event)
+}
+
+// watchAction applies an action and reports it as a WatchEventAction.
+func (s *composeService) watchAction(options api.WatchOptions, action string, services, files []string, fn func() error) error {
+	start := s.clock.Now()
+	err := fn()
+	s.emitWatchEvent(options, api.WatchEvent{
+		Type:     api.WatchEventAction,
+		Services: services,
+		Action:   action,
+		Files:    files,
+		Duration: s.clock.Since(start),
+		Error:    err,
+	})
+	return err
+}
+
+func batchFiles(batch []watch.FileEvent) []string {
+	files := make([]string, len(batch))
+	for i, event := range batch {
+		files[i] = string(event)
+	}
+	return files
+}
+
 // writeWatchSyncMessage prints out a message about the sync for the changed paths.
 func writeWatchSyncMessage(log api.LogConsumer, serviceName string, pathMappings []*sync.PathMapping) {
 	if logrus.IsLevelEnabled(logrus.DebugLevel) {
@@ -784,17 +1063,29 @@ func (s *composeService) initialSync(ctx context.Context, service types.ServiceC
 	if err != nil {
 		return err
 	}
+	gitIgnore, err := gitIgnoreMatcher(trigger)
+	if err != nil {
+		return err
+	}
 	// FIXME .dockerignore
 	ignoreInitialSync := watch.NewCompositeMatcher(
 		dockerIgnores,
 		watch.EphemeralPathMatcher(),
 		dotGitIgnore,
-		triggerIgnore)
+		triggerIgnore,
+		gitIgnore)
 
 	pathsToCopy, err := s.initialSyncFiles(service, trigger, ignoreInitialSync)
 	if err != nil {
 		return err
 	}
+	permissions, err := permissionsExtension(trigger)
+	if err != nil {
+		return err
+	}
+	for _, p := range pathsToCopy {
+		p.Permissions = permissions
+	}
 
 	return syncer.Sync(ctx, service.Name, pathsToCopy)
 }
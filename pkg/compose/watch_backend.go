@@ -0,0 +1,159 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/sirupsen/logrus"
+
+	pathutil "github.com/docker/compose/v5/internal/paths"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+// Environment variables selecting the file watcher backend, they override the
+// project x-watch extension
+const (
+	// WatchBackendEnvVar selects the backend, watch.BackendNative or watch.BackendPoll
+	WatchBackendEnvVar = "COMPOSE_WATCH_BACKEND"
+	// WatchPollIntervalEnvVar sets the delay between two scans of the polling backend
+	WatchPollIntervalEnvVar = "COMPOSE_WATCH_POLL_INTERVAL"
+	// WatchPollHashEnvVar makes the polling backend compare the files content
+	WatchPollHashEnvVar = "COMPOSE_WATCH_POLL_HASH"
+)
+
+// watchBackendConfig is the file watcher configuration, set by the project
+// x-watch extension:
+//
+//	x-watch:
+//	  backend: poll
+//	  poll_interval: 2s
+//	  poll_hash: true
+type watchBackendConfig struct {
+	Backend      string `mapstructure:"backend"`
+	PollInterval string `mapstructure:"poll_interval"`
+	PollHash     bool   `mapstructure:"poll_hash"`
+}
+
+// loadWatchBackendConfig reads the file watcher configuration from the
+// project, then the environment.
+func loadWatchBackendConfig(project *types.Project) (watchBackendConfig, error) {
+	config := watchBackendConfig{Backend: watch.BackendNative}
+	if x, ok := project.Extensions["x-watch"]; ok {
+		if err := mapstructure.Decode(x, &config); err != nil {
+			return config, fmt.Errorf("invalid x-watch extension: %w", err)
+		}
+	}
+	if backend, ok := os.LookupEnv(WatchBackendEnvVar); ok && backend != "" {
+		config.Backend = backend
+	}
+	if interval, ok := os.LookupEnv(WatchPollIntervalEnvVar); ok && interval != "" {
+		config.PollInterval = interval
+	}
+	if hash, ok := os.LookupEnv(WatchPollHashEnvVar); ok && hash != "" {
+		b, err := strconv.ParseBool(hash)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value %q: %w", WatchPollHashEnvVar, hash, err)
+		}
+		config.PollHash = b
+	}
+	switch config.Backend {
+	case watch.BackendNative, watch.BackendPoll:
+	default:
+		return config, fmt.Errorf("unsupported watch backend %q, expected %s or %s", config.Backend, watch.BackendNative, watch.BackendPoll)
+	}
+	return config, nil
+}
+
+// newFileWatcher returns the file watcher the project is configured for,
+// monitoring paths. The polling backend skips the paths all the rules
+// covering them ignore.
+func (s *composeService) newFileWatcher(project *types.Project, paths []string, rules []watchRule) (watch.Notify, error) {
+	config, err := loadWatchBackendConfig(project)
+	if err != nil {
+		return nil, err
+	}
+	if config.Backend == watch.BackendNative {
+		return watch.NewWatcher(paths)
+	}
+
+	interval := watch.DefaultPollInterval
+	if config.PollInterval != "" {
+		interval, err = time.ParseDuration(config.PollInterval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid watch poll interval %q", config.PollInterval)
+		}
+	}
+	logrus.Debugf("watching files by polling every %s", interval)
+	return watch.NewPollingWatcher(paths, watchRulesIgnore(rules), watch.PollOptions{
+		Interval: interval,
+		Hash:     config.PollHash,
+		Clock:    s.clock,
+	})
+}
+
+// watchRulesIgnore matches the paths which are ignored by all the watch rules
+// covering them, hence don't need to be watched.
+type watchRulesIgnore []watchRule
+
+func (rules watchRulesIgnore) Matches(path string) (bool, error) {
+	covered := false
+	for _, rule := range rules {
+		if !pathutil.IsChild(rule.Path, path) {
+			continue
+		}
+		covered = true
+		included, err := rule.include.Matches(path)
+		if err != nil {
+			return false, err
+		}
+		if !included {
+			continue
+		}
+		ignored, err := rule.ignore.Matches(path)
+		if err != nil || !ignored {
+			return false, err
+		}
+	}
+	return covered, nil
+}
+
+func (rules watchRulesIgnore) MatchesEntireDir(path string) (bool, error) {
+	covered := false
+	for _, rule := range rules {
+		if pathutil.IsChild(path, rule.Path) && path != rule.Path {
+			// the directory holds a watched path
+			return false, nil
+		}
+		if !pathutil.IsChild(rule.Path, path) {
+			continue
+		}
+		covered = true
+		ignored, err := rule.ignore.MatchesEntireDir(path)
+		if err != nil || !ignored {
+			return false, err
+		}
+	}
+	return covered, nil
+}
+
+var _ watch.PathMatcher = watchRulesIgnore{}
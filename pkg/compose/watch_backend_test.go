@@ -0,0 +1,85 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+func TestLoadWatchBackendConfig(t *testing.T) {
+	t.Setenv(WatchBackendEnvVar, "")
+	t.Setenv(WatchPollIntervalEnvVar, "")
+	t.Setenv(WatchPollHashEnvVar, "")
+
+	config, err := loadWatchBackendConfig(&types.Project{})
+	assert.NilError(t, err)
+	assert.Equal(t, config, watchBackendConfig{Backend: watch.BackendNative})
+
+	project := &types.Project{Extensions: types.Extensions{
+		"x-watch": map[string]any{"backend": "poll", "poll_interval": "2s"},
+	}}
+	config, err = loadWatchBackendConfig(project)
+	assert.NilError(t, err)
+	assert.Equal(t, config, watchBackendConfig{Backend: watch.BackendPoll, PollInterval: "2s"})
+
+	t.Setenv(WatchPollIntervalEnvVar, "500ms")
+	t.Setenv(WatchPollHashEnvVar, "true")
+	config, err = loadWatchBackendConfig(project)
+	assert.NilError(t, err)
+	assert.Equal(t, config, watchBackendConfig{Backend: watch.BackendPoll, PollInterval: "500ms", PollHash: true})
+
+	t.Setenv(WatchBackendEnvVar, "inotify")
+	_, err = loadWatchBackendConfig(project)
+	assert.Error(t, err, `unsupported watch backend "inotify", expected native or poll`)
+}
+
+func TestWatchRulesIgnore(t *testing.T) {
+	rule := func(path string, ignores ...string) watchRule {
+		ignore, err := watch.NewDockerPatternMatcher(path, ignores)
+		assert.NilError(t, err)
+		return watchRule{Trigger: types.Trigger{Path: path}, include: watch.AnyMatcher{}, ignore: ignore}
+	}
+	ignore := watchRulesIgnore{
+		rule("/src", "node_modules", "dist"),
+		rule("/src/dist/assets"),
+	}
+
+	matches := func(path string) bool {
+		ignored, err := ignore.Matches(path)
+		assert.NilError(t, err)
+		return ignored
+	}
+	assert.Assert(t, !matches("/src/main.go"))
+	assert.Assert(t, matches("/src/node_modules/lib/index.js"))
+	assert.Assert(t, matches("/src/dist/index.html"))
+	// ignored by the first rule, watched by the second one
+	assert.Assert(t, !matches("/src/dist/assets/logo.png"))
+
+	entireDir := func(path string) bool {
+		ignored, err := ignore.MatchesEntireDir(path)
+		assert.NilError(t, err)
+		return ignored
+	}
+	assert.Assert(t, entireDir("/src/node_modules"))
+	assert.Assert(t, !entireDir("/src/dist"))
+	assert.Assert(t, !entireDir("/src/dist/assets"))
+}
@@ -0,0 +1,173 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// watchImages checks every options.ImagesInterval whether the images of the
+// services were updated in their registry, until ctx is done.
+func (s *composeService) watchImages(ctx context.Context, project *types.Project, options api.WatchOptions) error {
+	ticker := s.clock.NewTicker(options.ImagesInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.Chan():
+			if err := s.updateImages(ctx, project, options); err != nil && ctx.Err() == nil {
+				logrus.Warnf("Error updating images: %v", err)
+			}
+		}
+	}
+}
+
+// updateImages pulls the images updated in their registry and lets the
+// reconciler recreate the services running an outdated image.
+func (s *composeService) updateImages(ctx context.Context, project *types.Project, options api.WatchOptions) error {
+	outdated, err := s.outdatedImages(ctx, project)
+	if err != nil || len(outdated) == 0 {
+		return err
+	}
+
+	var services []string
+	for image, names := range outdated {
+		options.LogTo.Log(api.WatchLogger, fmt.Sprintf("Image %s updated in the registry, pulling...", image))
+		services = append(services, names...)
+	}
+	slices.Sort(services)
+
+	return s.watchAction(options, api.WatchActionPull, services, nil, func() error {
+		for _, names := range outdated {
+			service := project.Services[names[0]]
+			if err := s.pullServiceImage(ctx, service, true, project.Environment["DOCKER_DEFAULT_PLATFORM"]); err != nil {
+				options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Failed to pull %s. Error: %v", service.Image, err))
+				return err
+			}
+		}
+
+		err := s.watchCreate(ctx, project, api.CreateOptions{
+			Services:             services,
+			Inherit:              true,
+			Recreate:             api.RecreateDiverged,
+			RecreateDependencies: api.RecreateDiverged,
+			SkipProviders:        true,
+		})
+		if err != nil {
+			options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Failed to recreate services after image update. Error: %v", err))
+			return err
+		}
+		err = s.start(ctx, project.Name, api.StartOptions{
+			Project:  project,
+			Services: services,
+		}, nil)
+		if err != nil {
+			options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Application failed to start after image update. Error: %v", err))
+			return err
+		}
+		options.LogTo.Log(api.WatchLogger, fmt.Sprintf("service(s) %q updated", services))
+		return nil
+	})
+}
+
+// outdatedImages resolves the registry digest of the images of the services
+// and returns the services, by image, whose local image isn't the one
+// published. The container image label holds a platform-specific content
+// digest, which can't be compared with the registry digest of a
+// multi-platform image: the local image repository digests are. The
+// reconciler then compares the labels with the pulled image.
+func (s *composeService) outdatedImages(ctx context.Context, project *types.Project) (map[string][]string, error) {
+	resolve := ImageDigestResolver(ctx, s.configFile(), s.apiClient())
+	checked := map[string]bool{} // by image, whether it is outdated
+	outdated := map[string][]string{}
+	var errs []error
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if !watchesImage(service) {
+			continue
+		}
+		if _, ok := checked[service.Image]; !ok {
+			isOutdated, err := s.imageOutdated(ctx, resolve, service.Image)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			checked[service.Image] = isOutdated
+		}
+		if checked[service.Image] {
+			outdated[service.Image] = append(outdated[service.Image], name)
+		}
+	}
+	return outdated, errors.Join(errs...)
+}
+
+// imageOutdated tells whether the registry digest of an image is none of the
+// repository digests of the local image.
+func (s *composeService) imageOutdated(ctx context.Context, resolve func(reference.Named) (digest.Digest, error), image string) (bool, error) {
+	named, err := reference.ParseDockerRef(image)
+	if err != nil {
+		return false, err
+	}
+	if _, pinned := named.(reference.Canonical); pinned {
+		return false, nil
+	}
+	published, err := resolve(named)
+	if err != nil {
+		return false, err
+	}
+	inspect, err := s.apiClient().ImageInspect(ctx, image)
+	if errdefs.IsNotFound(err) {
+		logrus.Debugf("image %s outdated, not available locally", image)
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("inspecting image %s: %w", image, err)
+	}
+	upToDate := slices.ContainsFunc(inspect.RepoDigests, func(repoDigest string) bool {
+		_, d, _ := strings.Cut(repoDigest, "@")
+		return d == published.String()
+	})
+	if !upToDate {
+		logrus.Debugf("image %s outdated, registry digest is %s", image, published)
+	}
+	return !upToDate, nil
+}
+
+// watchesImage tells whether the image of a service is checked for updates:
+// built images and services which never pull are not.
+func watchesImage(service types.ServiceConfig) bool {
+	if service.Image == "" || service.Build != nil {
+		return false
+	}
+	switch service.PullPolicy {
+	case types.PullPolicyNever, types.PullPolicyBuild:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/moby/moby/api/types/image"
+	registrytypes "github.com/moby/moby/api/types/registry"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestOutdatedImages(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	apiClient, cli := prepareMocks(mockCtrl)
+	cli.EXPECT().ConfigFile().Return(&configfile.ConfigFile{}).AnyTimes()
+	tested, err := NewComposeService(cli)
+	assert.NilError(t, err)
+
+	const (
+		current   = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		published = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+	registry := map[string]string{
+		"localhost:5000/api:latest":   published,
+		"docker.io/library/redis:7":   current,
+		"localhost:5000/proxy:latest": current,
+	}
+	for ref, d := range registry {
+		apiClient.EXPECT().
+			DistributionInspect(gomock.Any(), ref, gomock.Any()).
+			Return(client.DistributionInspectResult{
+				DistributionInspect: registrytypes.DistributionInspect{
+					Descriptor: ocispec.Descriptor{Digest: digest.Digest(d)},
+				},
+			}, nil)
+	}
+	for _, img := range []string{"localhost:5000/api", "redis:7"} {
+		apiClient.EXPECT().ImageInspect(gomock.Any(), img).
+			Return(client.ImageInspectResult{InspectResponse: image.InspectResponse{
+				RepoDigests: []string{img + "@" + current},
+			}}, nil)
+	}
+	// not pulled yet
+	apiClient.EXPECT().ImageInspect(gomock.Any(), "localhost:5000/proxy").
+		Return(client.ImageInspectResult{}, notFoundError{})
+
+	project := &types.Project{Name: "myproject", Services: types.Services{
+		"api":    {Name: "api", Image: "localhost:5000/api"},
+		"worker": {Name: "worker", Image: "localhost:5000/api"},
+		"cache":  {Name: "cache", Image: "redis:7"},
+		"proxy":  {Name: "proxy", Image: "localhost:5000/proxy"},
+		"web":    {Name: "web", Image: "web", Build: &types.BuildConfig{Context: "."}},
+		"db":     {Name: "db", Image: "postgres", PullPolicy: types.PullPolicyNever},
+		"pinned": {Name: "pinned", Image: "nginx@" + published},
+	}}
+
+	// the registry is checked once per image, built and pinned images are not
+	outdated, err := tested.(*composeService).outdatedImages(t.Context(), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, outdated, map[string][]string{
+		"localhost:5000/api":   {"api", "worker"},
+		"localhost:5000/proxy": {"proxy"},
+	})
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/docker/compose/v5/internal/sync"
+)
+
+// Extensions of the sync rules overriding the owner and permissions of the
+// synced files
+const (
+	// watchOwnerExtension sets the owner of the files, as uid:gid
+	watchOwnerExtension = "x-owner"
+	// watchFileModeExtension sets the permissions of the regular files
+	watchFileModeExtension = "x-file_mode"
+	// watchDirModeExtension sets the permissions of the directories
+	watchDirModeExtension = "x-dir_mode"
+	// watchExecBitExtension preserves (default) or strips the execute
+	// permissions of the files
+	watchExecBitExtension = "x-exec_bit"
+)
+
+// permissionsExtension reads the owner and permissions the files synced by a
+// watch rule get, nil if they keep the host ones.
+func permissionsExtension(trigger types.Trigger) (*sync.Permissions, error) {
+	var (
+		p   sync.Permissions
+		set bool
+		err error
+	)
+	if v, ok := trigger.Extensions[watchOwnerExtension]; ok {
+		set = true
+		p.SetOwner = true
+		if p.UID, p.GID, err = parseOwner(v); err != nil {
+			return nil, fmt.Errorf("watch rule %s: invalid %s: %w", trigger.Path, watchOwnerExtension, err)
+		}
+	}
+	if v, ok := trigger.Extensions[watchFileModeExtension]; ok {
+		set = true
+		if p.FileMode, err = parseFileMode(v); err != nil {
+			return nil, fmt.Errorf("watch rule %s: invalid %s: %w", trigger.Path, watchFileModeExtension, err)
+		}
+	}
+	if v, ok := trigger.Extensions[watchDirModeExtension]; ok {
+		set = true
+		if p.DirMode, err = parseFileMode(v); err != nil {
+			return nil, fmt.Errorf("watch rule %s: invalid %s: %w", trigger.Path, watchDirModeExtension, err)
+		}
+	}
+	if v, ok := trigger.Extensions[watchExecBitExtension]; ok {
+		set = true
+		switch v {
+		case "preserve":
+		case "strip":
+			p.StripExec = true
+		default:
+			return nil, fmt.Errorf("watch rule %s: invalid %s %v, expected preserve or strip", trigger.Path, watchExecBitExtension, v)
+		}
+	}
+	if !set {
+		return nil, nil
+	}
+	if !isSync(trigger) && trigger.Action != types.WatchActionSyncExec {
+		return nil, fmt.Errorf("watch rule %s: %s, %s, %s and %s require a sync action", trigger.Path,
+			watchOwnerExtension, watchFileModeExtension, watchDirModeExtension, watchExecBitExtension)
+	}
+	return &p, nil
+}
+
+// parseOwner parses numeric uid:gid, names can't be resolved without the
+// container.
+func parseOwner(v any) (int, int, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, 0, fmt.Errorf("expected uid:gid, got %v", v)
+	}
+	uidStr, gidStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected uid:gid, got %q", s)
+	}
+	uid, err := strconv.ParseUint(uidStr, 10, 31)
+	if err != nil {
+		return 0, 0, fmt.Errorf("uid must be numeric, got %q", uidStr)
+	}
+	gid, err := strconv.ParseUint(gidStr, 10, 31)
+	if err != nil {
+		return 0, 0, fmt.Errorf("gid must be numeric, got %q", gidStr)
+	}
+	return int(uid), int(gid), nil
+}
+
+// parseFileMode parses an octal mode, as a string. YAML may have already
+// decoded a mode written unquoted as an integer.
+func parseFileMode(v any) (fs.FileMode, error) {
+	var mode uint64
+	switch m := v.(type) {
+	case string:
+		parsed, err := strconv.ParseUint(strings.TrimPrefix(m, "0o"), 8, 32)
+		if err != nil {
+			return 0, fmt.Errorf("expected an octal mode, got %q", m)
+		}
+		mode = parsed
+	case int:
+		mode = uint64(m)
+	case uint64:
+		mode = m
+	default:
+		return 0, fmt.Errorf("expected an octal mode, got %v", v)
+	}
+	if mode == 0 || mode > 0o777 {
+		return 0, fmt.Errorf("expected an octal mode between 1 and 0777 such as 0644, got %v", v)
+	}
+	return fs.FileMode(mode), nil
+}
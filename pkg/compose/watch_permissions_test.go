@@ -0,0 +1,84 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/internal/sync"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+func TestPermissionsExtension(t *testing.T) {
+	rules, err := getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{{
+			Path:   "/src",
+			Action: types.WatchActionSync,
+			Target: "/app",
+			Extensions: types.Extensions{
+				watchOwnerExtension:    "1000:1000",
+				watchFileModeExtension: 420, // 0644 decoded by the YAML parser
+				watchDirModeExtension:  "0755",
+				watchExecBitExtension:  "strip",
+			},
+		}},
+	}, types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	expected := &sync.Permissions{SetOwner: true, UID: 1000, GID: 1000, FileMode: 0o644, DirMode: 0o755, StripExec: true}
+	assert.DeepEqual(t, rules[0].permissions, expected)
+	assert.DeepEqual(t, rules[0].Matches(watch.NewFileEvent("/src/main.go")).Permissions, expected)
+
+	permissions, err := permissionsExtension(types.Trigger{Path: "/src", Action: types.WatchActionSync})
+	assert.NilError(t, err)
+	assert.Assert(t, permissions == nil)
+
+	for _, tc := range []struct {
+		trigger  types.Trigger
+		expected string
+	}{
+		{
+			trigger:  types.Trigger{Path: "/src", Action: types.WatchActionSync, Extensions: types.Extensions{watchOwnerExtension: "node"}},
+			expected: `watch rule /src: invalid x-owner: expected uid:gid, got "node"`,
+		},
+		{
+			trigger:  types.Trigger{Path: "/src", Action: types.WatchActionSync, Extensions: types.Extensions{watchOwnerExtension: "node:node"}},
+			expected: `watch rule /src: invalid x-owner: uid must be numeric, got "node"`,
+		},
+		{
+			trigger:  types.Trigger{Path: "/src", Action: types.WatchActionSync, Extensions: types.Extensions{watchFileModeExtension: "rw-r--r--"}},
+			expected: `watch rule /src: invalid x-file_mode: expected an octal mode, got "rw-r--r--"`,
+		},
+		{
+			trigger:  types.Trigger{Path: "/src", Action: types.WatchActionSync, Extensions: types.Extensions{watchDirModeExtension: 755}},
+			expected: `watch rule /src: invalid x-dir_mode: expected an octal mode between 1 and 0777 such as 0644, got 755`,
+		},
+		{
+			trigger:  types.Trigger{Path: "/src", Action: types.WatchActionSync, Extensions: types.Extensions{watchExecBitExtension: "keep"}},
+			expected: `watch rule /src: invalid x-exec_bit keep, expected preserve or strip`,
+		},
+		{
+			trigger:  types.Trigger{Path: "/src", Action: types.WatchActionRebuild, Extensions: types.Extensions{watchOwnerExtension: "0:0"}},
+			expected: `watch rule /src: x-owner, x-file_mode, x-dir_mode and x-exec_bit require a sync action`,
+		},
+	} {
+		_, err := permissionsExtension(tc.trigger)
+		assert.Error(t, err, tc.expected)
+	}
+}
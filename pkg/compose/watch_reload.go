@@ -0,0 +1,353 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+// projectWatch is a running watch of the services files.
+type projectWatch struct {
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// stop cancels the watch and waits for it to complete.
+func (w *projectWatch) stop() error {
+	w.cancel()
+	return <-w.done
+}
+
+func (s *composeService) startProjectWatch(ctx context.Context, project *types.Project, options api.WatchOptions) (*projectWatch, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	wait, err := s.watchProject(ctx, project, options)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	w := &projectWatch{cancel: cancel, done: make(chan error, 1)}
+	go func() {
+		w.done <- wait()
+	}()
+	return w, nil
+}
+
+// watchWithReload watches the services files as watchProject does, and the
+// project configuration files. When the latter change, the project is loaded
+// again, the services are reconciled with it and the watch restarts with the
+// new project.
+func (s *composeService) watchWithReload(ctx context.Context, project *types.Project, options api.WatchOptions) (func() error, error) {
+	current, err := s.startProjectWatch(ctx, project, options)
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.watchConfigFiles(ctx, project, options)
+	if err != nil {
+		_ = current.stop()
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.reloadLoop(ctx, project, options, current, config)
+	}()
+	return func() error {
+		return <-done
+	}, nil
+}
+
+// configWatch is a running watch of the project configuration files.
+type configWatch struct {
+	watcher watch.Notify
+	batches <-chan []watch.FileEvent
+}
+
+func (w *configWatch) close() {
+	if err := w.watcher.Close(); err != nil {
+		logrus.Debugf("Error closing configuration files watcher: %v", err)
+	}
+	// the debouncer may still be flushing a batch
+	go func() {
+		for range w.batches { //nolint:revive
+		}
+	}()
+}
+
+func (s *composeService) watchConfigFiles(ctx context.Context, project *types.Project, options api.WatchOptions) (*configWatch, error) {
+	var included []string
+	if options.IncludedFiles != nil {
+		included = options.IncludedFiles()
+	}
+	files := projectConfigFiles(project, included)
+	logrus.Debugf("watching configuration files %s", strings.Join(files, ", "))
+	watcher, err := s.newFileWatcher(project, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Start(); err != nil {
+		return nil, err
+	}
+	return &configWatch{
+		watcher: watcher,
+		batches: watch.BatchDebounceEvents(ctx, s.clock, watcher.Events()),
+	}, nil
+}
+
+func (s *composeService) reloadLoop(ctx context.Context, project *types.Project, options api.WatchOptions, current *projectWatch, config *configWatch) error {
+	defer func() {
+		config.close()
+	}()
+	for {
+		var projectDone chan error
+		if current != nil {
+			projectDone = current.done
+		}
+		select {
+		case <-ctx.Done():
+			if current != nil {
+				return current.stop()
+			}
+			return nil
+		case err := <-projectDone:
+			return err
+		case err, open := <-config.watcher.Errors():
+			if err != nil {
+				options.LogTo.Err(api.WatchLogger, "Configuration files watch disabled with errors: "+err.Error())
+			}
+			if !open {
+				if current != nil {
+					return current.stop()
+				}
+				return err
+			}
+		case batch, ok := <-config.batches:
+			if !ok {
+				if current != nil {
+					return current.stop()
+				}
+				return nil
+			}
+			var reloaded *types.Project
+			err := s.watchAction(options, api.WatchActionReload, nil, batchFiles(batch), func() (err error) {
+				reloaded, err = s.reloadProject(ctx, project, options, batch)
+				return err
+			})
+			if err != nil {
+				continue
+			}
+			if current != nil {
+				if err := current.stop(); err != nil {
+					return err
+				}
+			}
+			project = reloaded
+			current, err = s.startProjectWatch(ctx, project, options)
+			if err != nil {
+				options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Watch disabled until the configuration is fixed. Error: %v", err))
+				current = nil
+			}
+			// included or env files may have been added or removed
+			newConfig, err := s.watchConfigFiles(ctx, project, options)
+			if err != nil {
+				if current != nil {
+					_ = current.stop()
+				}
+				return err
+			}
+			config.close()
+			config = newConfig
+		}
+	}
+}
+
+// reloadProject loads the project again and reconciles the services with it.
+// The project is left unchanged on error.
+func (s *composeService) reloadProject(ctx context.Context, project *types.Project, options api.WatchOptions, batch []watch.FileEvent) (*types.Project, error) {
+	var files []string
+	for _, event := range batch {
+		files = append(files, filepath.Base(string(event)))
+	}
+	options.LogTo.Log(api.WatchLogger, fmt.Sprintf("Configuration file(s) %q changed, reloading project...", files))
+
+	reloaded, err := options.ReloadProject(ctx)
+	if err != nil {
+		options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Failed to reload project, keeping the current configuration. Error: %v", err))
+		return nil, err
+	}
+
+	changes, err := serviceChanges(project, reloaded)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		options.LogTo.Log(api.WatchLogger, "Project reloaded, no service changed")
+		return reloaded, nil
+	}
+	for _, change := range changes {
+		options.LogTo.Log(api.WatchLogger, change)
+	}
+
+	if err := s.removeServicesContainers(ctx, project, removedServices(project, reloaded)); err != nil {
+		options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Failed to remove the containers of the removed services. Error: %v", err))
+		return nil, err
+	}
+
+	err = s.watchCreate(ctx, reloaded, api.CreateOptions{
+		Build:                options.Build,
+		Services:             options.Services,
+		Inherit:              true,
+		Recreate:             api.RecreateDiverged,
+		RecreateDependencies: api.RecreateDiverged,
+		SkipProviders:        true,
+	})
+	if err != nil {
+		options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Failed to reconcile services with the reloaded project. Error: %v", err))
+		return nil, err
+	}
+	err = s.start(ctx, reloaded.Name, api.StartOptions{
+		Project:  reloaded,
+		Services: options.Services,
+	}, nil)
+	if err != nil {
+		options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Application failed to start after reload. Error: %v", err))
+	}
+	return reloaded, nil
+}
+
+// serviceChanges describes the services added, removed or modified by a new
+// version of the project.
+func serviceChanges(project, reloaded *types.Project) ([]string, error) {
+	var added, removed, modified []string
+	for name, service := range reloaded.Services {
+		previous, ok := project.Services[name]
+		if !ok {
+			added = append(added, name)
+			continue
+		}
+		// watch forces the pull policy of the services it rebuilds
+		previous.PullPolicy, service.PullPolicy = "", ""
+		before, err := ServiceHash(previous)
+		if err != nil {
+			return nil, err
+		}
+		after, err := ServiceHash(service)
+		if err != nil {
+			return nil, err
+		}
+		if before != after {
+			modified = append(modified, name)
+		}
+	}
+	removed = removedServices(project, reloaded)
+
+	var changes []string
+	for _, c := range []struct {
+		services []string
+		message  string
+	}{
+		{added, "service(s) %q added"},
+		{removed, "service(s) %q removed"},
+		{modified, "service(s) %q modified"},
+	} {
+		if len(c.services) > 0 {
+			slices.Sort(c.services)
+			changes = append(changes, fmt.Sprintf(c.message, c.services))
+		}
+	}
+	return changes, nil
+}
+
+// removedServices lists the services of project the reloaded project doesn't
+// declare anymore.
+func removedServices(project, reloaded *types.Project) []string {
+	var removed []string
+	for name := range project.Services {
+		if _, ok := reloaded.Services[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(removed)
+	return removed
+}
+
+// removeServicesContainers stops and removes the containers of services. The
+// other containers the project doesn't declare, started from another set of
+// compose files or profiles, are left untouched.
+func (s *composeService) removeServicesContainers(ctx context.Context, project *types.Project, services []string) error {
+	if len(services) == 0 {
+		return nil
+	}
+	containers, err := s.getContainers(ctx, project.Name, oneOffExclude, true, services...)
+	if err != nil {
+		return err
+	}
+	for _, name := range services {
+		service := project.Services[name]
+		if err := s.removeContainers(ctx, containers.filter(isService(name)), &service, nil, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectConfigFiles lists the files the project is loaded from: compose
+// files, the local files they include and the env files.
+func projectConfigFiles(project *types.Project, included []string) []string {
+	var files []string
+	seen := map[string]bool{}
+	add := func(file string) {
+		if file == "" || file == "-" || seen[file] {
+			return
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+
+	for _, file := range project.ComposeFiles {
+		add(file)
+	}
+	for _, file := range included {
+		add(file)
+	}
+
+	envFiles := false
+	for _, service := range project.Services {
+		if label, ok := service.CustomLabels[api.EnvironmentFileLabel]; ok {
+			for _, file := range strings.Split(label, ",") {
+				add(file)
+				envFiles = true
+			}
+		}
+		for _, envFile := range service.EnvFiles {
+			add(envFile.Path)
+		}
+	}
+	if !envFiles && project.WorkingDir != "" {
+		add(filepath.Join(project.WorkingDir, ".env"))
+	}
+	return files
+}
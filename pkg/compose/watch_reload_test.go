@@ -0,0 +1,195 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"go.uber.org/mock/gomock"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+// recordingLogger records the messages logged by watch.
+type recordingLogger struct {
+	messages []string
+}
+
+func (r *recordingLogger) Log(_, message string) {
+	r.messages = append(r.messages, message)
+}
+
+func (r *recordingLogger) Err(_, message string) {
+	r.messages = append(r.messages, "error: "+message)
+}
+
+func (r *recordingLogger) Status(_, message string) {
+	r.messages = append(r.messages, message)
+}
+
+func TestProjectConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "compose.yaml")
+	project := &types.Project{
+		WorkingDir:   dir,
+		ComposeFiles: []string{compose},
+		Services: types.Services{
+			"web": {
+				Name:     "web",
+				EnvFiles: []types.EnvFile{{Path: filepath.Join(dir, "web.env")}},
+			},
+		},
+	}
+	included := []string{
+		filepath.Join(dir, "db/compose.yaml"),
+		filepath.Join(dir, "api/compose.yaml"),
+		compose,
+	}
+	assert.DeepEqual(t, projectConfigFiles(project, included), []string{
+		compose,
+		filepath.Join(dir, "db/compose.yaml"),
+		filepath.Join(dir, "api/compose.yaml"),
+		filepath.Join(dir, "web.env"),
+		filepath.Join(dir, ".env"),
+	})
+
+	// an explicit --env-file replaces the default .env
+	project.Services["web"] = types.ServiceConfig{
+		Name:         "web",
+		CustomLabels: types.Labels{api.EnvironmentFileLabel: "/env/dev.env,/env/local.env"},
+	}
+	files := projectConfigFiles(project, nil)
+	assert.DeepEqual(t, files[len(files)-2:], []string{"/env/dev.env", "/env/local.env"})
+}
+
+func TestServiceChanges(t *testing.T) {
+	project := &types.Project{Services: types.Services{
+		"web": {Name: "web", Image: "nginx"},
+		"db":  {Name: "db", Image: "postgres"},
+		"api": {Name: "api", Image: "api", PullPolicy: types.PullPolicyBuild},
+	}}
+	reloaded := &types.Project{Services: types.Services{
+		"web":    {Name: "web", Image: "nginx:alpine"},
+		"api":    {Name: "api", Image: "api"},
+		"worker": {Name: "worker", Image: "worker"},
+	}}
+
+	changes, err := serviceChanges(project, reloaded)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes, []string{
+		`service(s) ["worker"] added`,
+		`service(s) ["db"] removed`,
+		`service(s) ["web"] modified`,
+	})
+
+	changes, err = serviceChanges(project, project)
+	assert.NilError(t, err)
+	assert.Assert(t, changes == nil)
+}
+
+func TestReloadProject(t *testing.T) {
+	project := &types.Project{Name: "myproject", Services: types.Services{
+		"web": {Name: "web", Image: "nginx"},
+	}}
+	logger := &recordingLogger{}
+	options := api.WatchOptions{
+		LogTo: logger,
+		ReloadProject: func(context.Context) (*types.Project, error) {
+			return nil, errors.New("services.web.image must be a string")
+		},
+	}
+	s := &composeService{}
+	batch := []watch.FileEvent{watch.NewFileEvent("/app/compose.yaml")}
+
+	// an invalid configuration keeps the current project
+	_, err := s.reloadProject(t.Context(), project, options, batch)
+	assert.ErrorContains(t, err, "must be a string")
+	assert.DeepEqual(t, logger.messages, []string{
+		`Configuration file(s) ["compose.yaml"] changed, reloading project...`,
+		"error: Failed to reload project, keeping the current configuration. Error: services.web.image must be a string",
+	})
+
+	logger.messages = nil
+	options.ReloadProject = func(context.Context) (*types.Project, error) {
+		return project, nil
+	}
+	reloaded, err := s.reloadProject(t.Context(), project, options, batch)
+	assert.NilError(t, err)
+	assert.Equal(t, reloaded, project)
+	assert.DeepEqual(t, logger.messages, []string{
+		`Configuration file(s) ["compose.yaml"] changed, reloading project...`,
+		"Project reloaded, no service changed",
+	})
+}
+
+func TestReloadProjectRemovesRemovedServices(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	mockCtrl := gomock.NewController(t)
+	apiClient, s := newTestComposeService(t, mockCtrl, "1.48")
+	project := &types.Project{Name: "myproject", Services: types.Services{
+		"db": {Name: "db", Image: "postgres"},
+	}}
+	reloaded := &types.Project{Name: "myproject", Services: types.Services{}}
+
+	db := container.Summary{
+		ID:    "db1",
+		Names: []string{"/myproject-db-1"},
+		State: container.StateRunning,
+		Labels: map[string]string{
+			api.ProjectLabel:         "myproject",
+			api.ServiceLabel:         "db",
+			api.ContainerNumberLabel: "1",
+		},
+	}
+	// a container of a service from another set of compose files is kept
+	other := container.Summary{
+		ID:    "other1",
+		Names: []string{"/myproject-other-1"},
+		State: container.StateRunning,
+		Labels: map[string]string{
+			api.ProjectLabel:         "myproject",
+			api.ServiceLabel:         "other",
+			api.ContainerNumberLabel: "1",
+		},
+	}
+	apiClient.EXPECT().ContainerList(gomock.Any(), gomock.Any()).
+		Return(client.ContainerListResult{Items: []container.Summary{db, other}}, nil).AnyTimes()
+	apiClient.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return(client.NetworkListResult{}, nil).AnyTimes()
+	apiClient.EXPECT().VolumeList(gomock.Any(), gomock.Any()).Return(client.VolumeListResult{}, nil).AnyTimes()
+	apiClient.EXPECT().ContainerStop(gomock.Any(), "db1", gomock.Any()).Return(client.ContainerStopResult{}, nil)
+	apiClient.EXPECT().ContainerRemove(gomock.Any(), "db1", gomock.Any()).Return(client.ContainerRemoveResult{}, nil)
+
+	logger := &recordingLogger{}
+	options := api.WatchOptions{
+		LogTo: logger,
+		ReloadProject: func(context.Context) (*types.Project, error) {
+			return reloaded, nil
+		},
+	}
+	_, err := s.reloadProject(t.Context(), project, options, []watch.FileEvent{watch.NewFileEvent("/app/compose.yaml")})
+	assert.NilError(t, err)
+	assert.Assert(t, slices.Contains(logger.messages, `service(s) ["db"] removed`), logger.messages)
+}
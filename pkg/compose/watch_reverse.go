@@ -0,0 +1,382 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	gsync "sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+const (
+	// watchReverseSyncExtension makes a sync rule also copy the files
+	// modified in the container under its target back to its path
+	watchReverseSyncExtension = "x-reverse_sync"
+	// watchReverseSyncIntervalExtension sets the delay between two snapshots
+	// of the container files
+	watchReverseSyncIntervalExtension = "x-reverse_sync_interval"
+
+	defaultReverseSyncInterval = 2 * time.Second
+)
+
+// reverseSyncExtension reads the reverse sync configuration of a watch rule.
+func reverseSyncExtension(trigger types.Trigger) (time.Duration, error) {
+	v, ok := trigger.Extensions[watchReverseSyncExtension]
+	if !ok {
+		return 0, nil
+	}
+	enabled, ok := v.(bool)
+	if !ok {
+		return 0, fmt.Errorf("watch rule %s: %s must be a boolean, got %v", trigger.Path, watchReverseSyncExtension, v)
+	}
+	if !enabled {
+		return 0, nil
+	}
+	if !isSync(trigger) && trigger.Action != types.WatchActionSyncExec {
+		return 0, fmt.Errorf("watch rule %s: %s requires a sync action", trigger.Path, watchReverseSyncExtension)
+	}
+	interval, err := durationExtension(trigger, watchReverseSyncIntervalExtension)
+	if err != nil || interval != 0 {
+		return interval, err
+	}
+	return defaultReverseSyncInterval, nil
+}
+
+// reverseSyncClient gives the reverse sync access to the containers files.
+type reverseSyncClient interface {
+	// ContainersForService lists the containers of a service
+	ContainersForService(ctx context.Context, projectName string, serviceName string) ([]container.Summary, error)
+	// ExecOutput runs cmd in the container and returns its standard output
+	ExecOutput(ctx context.Context, containerID string, cmd []string) ([]byte, error)
+	// CopyFrom returns a tar archive of a path in the container
+	CopyFrom(ctx context.Context, containerID, path string) (io.ReadCloser, error)
+}
+
+// changedFilesScript lists the regular files under $1 modified since the
+// marker file $2 was touched, then touches it. Nothing is listed until the
+// marker exists: the first run is the reference.
+const changedFilesScript = `touch "$2.next" || exit 1
+if [ -e "$2" ]; then find "$1" -type f -newer "$2" 2>/dev/null; fi
+mv "$2.next" "$2"`
+
+// reverseSync copies back to the host the files created or modified in the
+// containers under the target of the sync rules with the x-reverse_sync
+// extension. At each snapshot, the files modified in the containers since the
+// previous one are listed by a command run in them, and only those are
+// copied: a file modified on both sides since is a conflict, the host version
+// is kept. Files deleted in the container are not deleted on the host.
+type reverseSync struct {
+	projectName string
+	rules       []watchRule
+	client      reverseSyncClient
+	// states hold the reverse sync state of each rule
+	states []reverseSyncState
+
+	mu gsync.Mutex
+	// written holds the hash of the files written to the host, so that the
+	// resulting file events aren't synced back to the container
+	written map[string]string
+}
+
+type reverseSyncState struct {
+	// snapshot is the time the previous snapshot was taken
+	snapshot time.Time
+	// hashes holds the hash of the container files copied back, or found
+	// unchanged, by host path
+	hashes map[string]string
+}
+
+// newReverseSync returns nil if none of the rules has reverse sync enabled.
+func newReverseSync(projectName string, rules []watchRule, client reverseSyncClient) *reverseSync {
+	var reverseRules []watchRule
+	for _, rule := range rules {
+		if rule.reverseSyncInterval != 0 {
+			reverseRules = append(reverseRules, rule)
+		}
+	}
+	if len(reverseRules) == 0 {
+		return nil
+	}
+	states := make([]reverseSyncState, len(reverseRules))
+	for i := range states {
+		states[i].hashes = map[string]string{}
+	}
+	return &reverseSync{
+		projectName: projectName,
+		rules:       reverseRules,
+		client:      client,
+		states:      states,
+		written:     map[string]string{},
+	}
+}
+
+// run snapshots the container files of each rule at its interval until ctx
+// is done.
+func (r *reverseSync) run(ctx context.Context, s *composeService, options api.WatchOptions) error {
+	var wg gsync.WaitGroup
+	for i, rule := range r.rules {
+		wg.Go(func() {
+			ticker := s.clock.NewTicker(rule.reverseSyncInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.Chan():
+					r.syncRule(ctx, s, options, i)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return nil
+}
+
+// syncRule copies back the container files of a rule changed since its last
+// snapshot. Snapshots copying nothing aren't reported.
+func (r *reverseSync) syncRule(ctx context.Context, s *composeService, options api.WatchOptions, i int) {
+	rule := r.rules[i]
+	start := s.clock.Now()
+	copied, conflicts, err := r.sync(ctx, i)
+	if err != nil && ctx.Err() == nil {
+		// the container may be restarting, try again at the next snapshot
+		logrus.Debugf("reverse sync of %s from service %q failed: %v", rule.Target, rule.service, err)
+	}
+	if len(copied) == 0 && len(conflicts) == 0 {
+		return
+	}
+
+	if len(copied) > 0 {
+		options.LogTo.Log(api.WatchLogger, fmt.Sprintf("Synced %d file(s) back from service %q", len(copied), rule.service))
+	}
+	for _, conflict := range conflicts {
+		options.LogTo.Err(api.WatchLogger, fmt.Sprintf("Conflict on %s: modified on the host and in service %q, keeping the host version", conflict, rule.service))
+	}
+	if len(conflicts) > 0 {
+		err = errors.Join(err, fmt.Errorf("files modified on the host and in the container: %s", strings.Join(conflicts, ", ")))
+	}
+	s.emitWatchEvent(options, api.WatchEvent{
+		Type:     api.WatchEventAction,
+		Services: []string{rule.service},
+		Action:   api.WatchActionReverseSync,
+		Files:    copied,
+		Duration: s.clock.Since(start),
+		Error:    err,
+	})
+}
+
+// sync copies to the host the container files of a rule changed since the
+// previous snapshot, in each running container of the service. The first
+// snapshot is the reference, nothing is copied.
+func (r *reverseSync) sync(ctx context.Context, i int) (copied []string, conflicts []string, err error) {
+	rule := r.rules[i]
+	containers, err := r.client.ContainersForService(ctx, r.projectName, rule.service)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &r.states[i]
+	since := state.snapshot
+	state.snapshot = time.Now()
+	// the marker is named after the rule, so that the rules sharing a
+	// container keep their own
+	marker := "/tmp/.compose-reverse-sync-" + sha256Hex([]byte(rule.Path + ":" + rule.Target))[:12]
+	for _, ctr := range containers {
+		if ctr.State != container.StateRunning {
+			continue
+		}
+		out, err := r.client.ExecOutput(ctx, ctr.ID, []string{"sh", "-c", changedFilesScript, "sh", rule.Target, marker})
+		if err != nil {
+			return copied, conflicts, err
+		}
+		for _, name := range strings.Split(string(out), "\n") {
+			hostPath, ok := reverseSyncHostPath(rule, name)
+			if !ok || shouldIgnoreReverse(rule, hostPath) {
+				continue
+			}
+			written, conflict, err := r.copyBack(ctx, state, ctr.ID, name, hostPath, since)
+			if err != nil {
+				return copied, conflicts, err
+			}
+			if written {
+				copied = append(copied, hostPath)
+			}
+			if conflict {
+				conflicts = append(conflicts, hostPath)
+			}
+		}
+	}
+	return copied, conflicts, nil
+}
+
+// copyBack copies a container file listed as changed to the host, unless its
+// content is unchanged or the host file was modified since the previous
+// snapshot. The content is streamed to a temporary file, which replaces the
+// host file.
+func (r *reverseSync) copyBack(ctx context.Context, state *reverseSyncState, containerID, name, hostPath string, since time.Time) (written, conflict bool, err error) {
+	archive, err := r.client.CopyFrom(ctx, containerID, name)
+	if errdefs.IsNotFound(err) {
+		// deleted since it was listed
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	defer func() { _ = archive.Close() }()
+	reader := tar.NewReader(archive)
+	header, err := reader.Next()
+	if errors.Is(err, io.EOF) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	if header.Typeflag != tar.TypeReg {
+		return false, false, nil
+	}
+
+	dir := filepath.Dir(hostPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, false, err
+	}
+	tmp, err := os.CreateTemp(dir, ".reverse-sync-*")
+	if err != nil {
+		return false, false, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), reader)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, false, err
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	last, known := state.hashes[hostPath]
+	state.hashes[hostPath] = hash
+	if known && last == hash {
+		return false, false, nil
+	}
+	hostHash, err := fileSHA256(hostPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return false, false, err
+	case hostHash == hash:
+		return false, false, nil
+	case known && hostHash != last:
+		return false, true, nil
+	case !known:
+		info, err := os.Stat(hostPath)
+		if err != nil {
+			return false, false, err
+		}
+		if info.ModTime().After(since) {
+			return false, true, nil
+		}
+	}
+
+	r.mu.Lock()
+	r.written[hostPath] = hash
+	r.mu.Unlock()
+	if err := os.Chmod(tmp.Name(), header.FileInfo().Mode().Perm()); err != nil {
+		return false, false, err
+	}
+	return true, false, os.Rename(tmp.Name(), hostPath)
+}
+
+// dropEchoes removes from a batch the events of the files written by the
+// reverse sync, unless they were modified since.
+func (r *reverseSync) dropEchoes(batch []watch.FileEvent) []watch.FileEvent {
+	if r == nil {
+		return batch
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.written) == 0 {
+		return batch
+	}
+	return slices.DeleteFunc(batch, func(event watch.FileEvent) bool {
+		hash, ok := r.written[string(event)]
+		if !ok {
+			return false
+		}
+		delete(r.written, string(event))
+		current, err := fileSHA256(string(event))
+		return err == nil && current == hash
+	})
+}
+
+// reverseSyncHostPath maps a container path listed as changed to the host
+// path of the rule.
+func reverseSyncHostPath(rule watchRule, name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	rel, ok := strings.CutPrefix(path.Clean(name), strings.TrimSuffix(path.Clean(rule.Target), "/")+"/")
+	if !ok || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return filepath.Join(rule.Path, filepath.FromSlash(rel)), true
+}
+
+func shouldIgnoreReverse(rule watchRule, hostPath string) bool {
+	included, err := rule.include.Matches(hostPath)
+	if err != nil || !included {
+		return true
+	}
+	return shouldIgnore(hostPath, rule.ignore)
+}
+
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
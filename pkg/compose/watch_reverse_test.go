@@ -0,0 +1,210 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+// containerFiles is a reverseSyncClient serving files as the content of /app
+// in a single container. The files written since the previous listing are
+// listed as changed.
+type containerFiles struct {
+	t       *testing.T
+	files   map[string]string
+	changed map[string]bool
+	listed  bool
+	copied  []string
+}
+
+func newContainerFiles(t *testing.T, files map[string]string) *containerFiles {
+	return &containerFiles{t: t, files: files, changed: map[string]bool{}}
+}
+
+func (f *containerFiles) write(name, content string) {
+	f.files[name] = content
+	f.changed[name] = true
+}
+
+func (f *containerFiles) ContainersForService(_ context.Context, projectName, service string) ([]container.Summary, error) {
+	assert.Equal(f.t, projectName, "myproject")
+	assert.Equal(f.t, service, "web")
+	return []container.Summary{{ID: "c1", State: container.StateRunning}}, nil
+}
+
+func (f *containerFiles) ExecOutput(_ context.Context, containerID string, cmd []string) ([]byte, error) {
+	assert.Equal(f.t, containerID, "c1")
+	assert.Equal(f.t, cmd[4], "/app")
+	var out strings.Builder
+	if f.listed {
+		for _, name := range slices.Sorted(maps.Keys(f.changed)) {
+			out.WriteString("/app/" + name + "\n")
+		}
+	}
+	f.listed = true
+	clear(f.changed)
+	return []byte(out.String()), nil
+}
+
+func (f *containerFiles) CopyFrom(_ context.Context, containerID, p string) (io.ReadCloser, error) {
+	assert.Equal(f.t, containerID, "c1")
+	name := strings.TrimPrefix(p, "/app/")
+	content, ok := f.files[name]
+	if !ok {
+		return nil, notFoundError{}
+	}
+	f.copied = append(f.copied, name)
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	assert.NilError(f.t, w.WriteHeader(&tar.Header{Name: path.Base(name), Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}))
+	_, err := w.Write([]byte(content))
+	assert.NilError(f.t, err)
+	assert.NilError(f.t, w.Close())
+	return io.NopCloser(&buf), nil
+}
+
+func reverseSyncRules(t *testing.T, dir string, ignore ...string) []watchRule {
+	t.Helper()
+	rules, err := getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{{
+			Path:       dir,
+			Action:     types.WatchActionSync,
+			Target:     "/app",
+			Ignore:     ignore,
+			Extensions: types.Extensions{watchReverseSyncExtension: true},
+		}},
+	}, types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	return rules
+}
+
+func TestReverseSync(t *testing.T) {
+	dir := t.TempDir()
+	writeHost := func(name, content string) {
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	readHost := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NilError(t, err)
+		return string(content)
+	}
+	writeHost("package.json", `{"dependencies": {}}`)
+	writeHost("main.go", "package main")
+
+	rules := reverseSyncRules(t, dir, "node_modules/")
+	assert.Equal(t, rules[0].reverseSyncInterval, defaultReverseSyncInterval)
+
+	files := newContainerFiles(t, map[string]string{
+		"package.json": `{"dependencies": {}}`,
+		"main.go":      "package main",
+	})
+	reverse := newReverseSync("myproject", rules, files)
+
+	// the first snapshot is the reference
+	copied, conflicts, err := reverse.sync(t.Context(), 0)
+	assert.NilError(t, err)
+	assert.Assert(t, copied == nil && conflicts == nil)
+
+	// files created or modified in the container only are copied back, the
+	// ignored ones are not even transferred
+	files.write("package.json", `{"dependencies": {"left-pad": "1.3.0"}}`)
+	files.write("package-lock.json", `{"lockfileVersion": 3}`)
+	files.write("node_modules/left-pad/index.js", "module.exports = {}")
+	copied, conflicts, err = reverse.sync(t.Context(), 0)
+	assert.NilError(t, err)
+	assert.Assert(t, conflicts == nil)
+	assert.DeepEqual(t, copied, []string{filepath.Join(dir, "package-lock.json"), filepath.Join(dir, "package.json")})
+	assert.DeepEqual(t, files.copied, []string{"package-lock.json", "package.json"})
+	assert.Equal(t, readHost("package.json"), `{"dependencies": {"left-pad": "1.3.0"}}`)
+	_, err = os.Stat(filepath.Join(dir, "node_modules"))
+	assert.Assert(t, os.IsNotExist(err))
+
+	// the events of the copied files are not synced to the container again
+	writeHost("main.go", "package main // host")
+	batch := reverse.dropEchoes([]watch.FileEvent{
+		watch.NewFileEvent(filepath.Join(dir, "package.json")),
+		watch.NewFileEvent(filepath.Join(dir, "main.go")),
+	})
+	assert.DeepEqual(t, batch, []watch.FileEvent{watch.NewFileEvent(filepath.Join(dir, "main.go"))})
+
+	// modified on both sides: the host version is kept
+	files.write("main.go", "package main // container")
+	copied, conflicts, err = reverse.sync(t.Context(), 0)
+	assert.NilError(t, err)
+	assert.Assert(t, copied == nil)
+	assert.DeepEqual(t, conflicts, []string{filepath.Join(dir, "main.go")})
+	assert.Equal(t, readHost("main.go"), "package main // host")
+}
+
+func TestReverseSyncComparesContent(t *testing.T) {
+	dir := t.TempDir()
+	files := newContainerFiles(t, map[string]string{"version": "v1"})
+	reverse := newReverseSync("myproject", reverseSyncRules(t, dir), files)
+	_, _, err := reverse.sync(t.Context(), 0)
+	assert.NilError(t, err)
+
+	// a file of the same size, modified within the same second, is copied
+	files.write("version", "v2")
+	copied, _, err := reverse.sync(t.Context(), 0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, copied, []string{filepath.Join(dir, "version")})
+
+	// a file touched but left unchanged is not
+	files.write("version", "v2")
+	copied, _, err = reverse.sync(t.Context(), 0)
+	assert.NilError(t, err)
+	assert.Assert(t, copied == nil)
+}
+
+func TestReverseSyncExtension(t *testing.T) {
+	_, err := reverseSyncExtension(types.Trigger{
+		Path:       "/src",
+		Action:     types.WatchActionRebuild,
+		Extensions: types.Extensions{watchReverseSyncExtension: true},
+	})
+	assert.Error(t, err, "watch rule /src: x-reverse_sync requires a sync action")
+
+	interval, err := reverseSyncExtension(types.Trigger{
+		Path:   "/src",
+		Action: types.WatchActionSyncRestart,
+		Extensions: types.Extensions{
+			watchReverseSyncExtension:         true,
+			watchReverseSyncIntervalExtension: "5s",
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, interval.String(), "5s")
+
+	rules := []watchRule{{Trigger: types.Trigger{Path: "/src"}}}
+	assert.Assert(t, newReverseSync("myproject", rules, nil) == nil)
+}
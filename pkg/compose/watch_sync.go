@@ -0,0 +1,147 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	pathutil "github.com/docker/compose/v5/internal/paths"
+	"github.com/docker/compose/v5/internal/sync"
+)
+
+// watchSyncExtension selects the sync implementation of a service, in its
+// develop section
+const watchSyncExtension = "x-sync"
+
+// Sync implementations a service can select
+const (
+	// syncTar transfers each batch of files with an exec deleting the removed
+	// files and a copy request
+	syncTar = "tar"
+	// syncManifest only transfers the files whose content differs from the
+	// copy in the container
+	syncManifest = "manifest"
+	// syncExecStream streams the files to a helper process kept running in
+	// the containers
+	syncExecStream = "exec-stream"
+	// syncBind transfers nothing, the service bind mounts the synced paths
+	syncBind = "bind"
+)
+
+// getSyncImplementation returns the sync implementation of the project: the
+// syncs of the services selecting one with the x-sync extension of their
+// develop section are dispatched to it, the others to the default one.
+func (s *composeService) getSyncImplementation(project *types.Project) (sync.Syncer, error) {
+	fallback, err := s.defaultSyncer(project)
+	if err != nil {
+		return nil, err
+	}
+
+	syncers := map[string]sync.Syncer{} // by implementation, shared by the services
+	services := map[string]sync.Syncer{}
+	for name, service := range project.Services {
+		if service.Develop == nil {
+			continue
+		}
+		v, ok := service.Develop.Extensions[watchSyncExtension]
+		if !ok {
+			continue
+		}
+		impl, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("service %q: %s must be a string, got %v", name, watchSyncExtension, v)
+		}
+		if impl == syncBind {
+			if err := checkBindSync(service); err != nil {
+				return nil, err
+			}
+		}
+		syncer, ok := syncers[impl]
+		if !ok {
+			switch impl {
+			case syncTar:
+				syncer = sync.NewTar(project.Name, tarDockerClient{s: s})
+			case syncManifest:
+				syncer = sync.NewManifest(project.Name, tarDockerClient{s: s})
+			case syncExecStream:
+				syncer = sync.NewExecStream(project.Name, tarDockerClient{s: s})
+			case syncBind:
+				syncer = sync.Bind{}
+			default:
+				return nil, fmt.Errorf("service %q: unsupported %s %q, expected one of %s, %s, %s or %s",
+					name, watchSyncExtension, impl, syncTar, syncManifest, syncExecStream, syncBind)
+			}
+			syncers[impl] = syncer
+		}
+		services[name] = syncer
+	}
+	if len(services) == 0 {
+		return fallback, nil
+	}
+	return serviceSyncers{fallback: fallback, services: services}, nil
+}
+
+// serviceSyncers dispatches the syncs to the implementation of each service.
+type serviceSyncers struct {
+	fallback sync.Syncer
+	services map[string]sync.Syncer
+}
+
+func (s serviceSyncers) Sync(ctx context.Context, service string, paths []*sync.PathMapping) error {
+	if syncer, ok := s.services[service]; ok {
+		return syncer.Sync(ctx, service, paths)
+	}
+	return s.fallback.Sync(ctx, service, paths)
+}
+
+// checkBindSync verifies the sync rules of a service selecting the bind
+// implementation have their path bind mounted on their target.
+func checkBindSync(service types.ServiceConfig) error {
+	for _, trigger := range service.Develop.Watch {
+		if !isSync(trigger) && trigger.Action != types.WatchActionSyncExec {
+			continue
+		}
+		if !bindMounted(service, trigger.Path, trigger.Target) {
+			return fmt.Errorf("service %q: %s %s requires %s to be bind mounted on %s",
+				service.Name, watchSyncExtension, syncBind, trigger.Path, trigger.Target)
+		}
+	}
+	return nil
+}
+
+// bindMounted tells whether a bind mount of the service makes hostPath
+// visible at containerPath.
+func bindMounted(service types.ServiceConfig, hostPath, containerPath string) bool {
+	for _, volume := range service.Volumes {
+		if volume.Type != types.VolumeTypeBind || !pathutil.IsChild(volume.Source, hostPath) {
+			continue
+		}
+		rel, err := filepath.Rel(volume.Source, hostPath)
+		if err != nil {
+			continue
+		}
+		if path.Join(volume.Target, filepath.ToSlash(rel)) == path.Clean(containerPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/internal/sync"
+)
+
+func developWithSync(impl any, triggers ...types.Trigger) *types.DevelopConfig {
+	return &types.DevelopConfig{
+		Watch:      triggers,
+		Extensions: types.Extensions{watchSyncExtension: impl},
+	}
+}
+
+func TestGetSyncImplementation(t *testing.T) {
+	s := &composeService{}
+	appSync := types.Trigger{Path: "/src/app", Action: types.WatchActionSync, Target: "/app"}
+	project := &types.Project{Name: "myproject", Services: types.Services{
+		"api":    {Name: "api", Develop: developWithSync(syncExecStream)},
+		"worker": {Name: "worker", Develop: developWithSync(syncExecStream)},
+		"web": {
+			Name:    "web",
+			Develop: developWithSync(syncBind, appSync),
+			Volumes: []types.ServiceVolumeConfig{{Type: types.VolumeTypeBind, Source: "/src", Target: "/"}},
+		},
+		"db": {Name: "db"},
+	}}
+
+	syncer, err := s.getSyncImplementation(project)
+	assert.NilError(t, err)
+	syncers, ok := syncer.(serviceSyncers)
+	assert.Assert(t, ok)
+	assert.Assert(t, syncers.services["api"] == syncers.services["worker"], "services share an implementation")
+	_, ok = syncers.services["api"].(*sync.ExecStream)
+	assert.Assert(t, ok)
+	assert.Equal(t, syncers.services["web"], sync.Syncer(sync.Bind{}))
+	_, ok = syncers.fallback.(*sync.Tar)
+	assert.Assert(t, ok)
+	// the bind implementation transfers nothing
+	assert.NilError(t, syncers.Sync(t.Context(), "web", []*sync.PathMapping{{HostPath: "/src/app/main.go", ContainerPath: "/app/main.go"}}))
+
+	// without selection, the default implementation is used as is
+	syncer, err = s.getSyncImplementation(&types.Project{Name: "myproject", Services: types.Services{"db": {Name: "db"}}})
+	assert.NilError(t, err)
+	_, ok = syncer.(*sync.Tar)
+	assert.Assert(t, ok)
+}
+
+func TestGetSyncImplementationErrors(t *testing.T) {
+	s := &composeService{}
+	appSync := types.Trigger{Path: "/src/app", Action: types.WatchActionSync, Target: "/app"}
+	for name, tc := range map[string]struct {
+		service  types.ServiceConfig
+		expected string
+	}{
+		"unsupported": {
+			service:  types.ServiceConfig{Name: "web", Develop: developWithSync("rsync")},
+			expected: `service "web": unsupported x-sync "rsync", expected one of tar, manifest, exec-stream or bind`,
+		},
+		"not a string": {
+			service:  types.ServiceConfig{Name: "web", Develop: developWithSync(true)},
+			expected: `service "web": x-sync must be a string, got true`,
+		},
+		"not bind mounted": {
+			service: types.ServiceConfig{
+				Name:    "web",
+				Develop: developWithSync(syncBind, appSync),
+				Volumes: []types.ServiceVolumeConfig{{Type: types.VolumeTypeBind, Source: "/src/app", Target: "/srv"}},
+			},
+			expected: `service "web": x-sync bind requires /src/app to be bind mounted on /app`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.getSyncImplementation(&types.Project{Name: "myproject", Services: types.Services{"web": tc.service}})
+			assert.Error(t, err, tc.expected)
+		})
+	}
+}
@@ -17,6 +17,7 @@ package compose
 import (
 	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -144,7 +145,7 @@ func TestWatch_Sync(t *testing.T) {
 			Build: &api.BuildOptions{},
 			LogTo: stdLogger{},
 			Prune: true,
-		}, watcher, syncer, rules)
+		}, watcher, syncer, rules, nil)
 		assert.NilError(t, err)
 	}()
 
@@ -266,3 +267,73 @@ func TestPruneDanglingImagesOnRebuild(t *testing.T) {
 	tested.(*composeService).pruneDanglingImagesOnRebuild(t.Context(), "proj",
 		map[string]string{"app-image:latest": "sha256:justbuilt"})
 }
+
+type failingSyncer struct {
+	err error
+}
+
+func (f failingSyncer) Sync(context.Context, string, []*sync.PathMapping) error {
+	return f.err
+}
+
+func TestHandleWatchBatchEvents(t *testing.T) {
+	rules, err := getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{
+			{Path: "/src", Action: types.WatchActionSync, Target: "/app"},
+			{Path: "/src/static", Action: types.WatchActionSync, Target: "/srv"},
+		},
+	}, types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+
+	var events []api.WatchEvent
+	options := api.WatchOptions{
+		LogTo: stdLogger{},
+		OnEvent: func(event api.WatchEvent) {
+			events = append(events, event)
+		},
+	}
+	clock := clockwork.NewFakeClock()
+	s := &composeService{clock: clock}
+	project := &types.Project{Name: "myproject"}
+	batch := []watch.FileEvent{watch.NewFileEvent("/src/main.go"), watch.NewFileEvent("/src/static/logo.png")}
+
+	syncer := &fakeSyncer{synced: make(chan []*sync.PathMapping, 1)}
+	assert.NilError(t, s.handleWatchBatch(t.Context(), project, options, batch, rules, syncer, nil))
+	assert.DeepEqual(t, events, []api.WatchEvent{
+		{Time: clock.Now(), Type: api.WatchEventMatch, Services: []string{"web"}, Path: "/src", Action: "sync", Files: []string{"/src/main.go", "/src/static/logo.png"}},
+		{Time: clock.Now(), Type: api.WatchEventMatch, Services: []string{"web"}, Path: "/src/static", Action: "sync", Files: []string{"/src/static/logo.png"}},
+		{Time: clock.Now(), Type: api.WatchEventAction, Services: []string{"web"}, Action: "sync", Files: []string{"/src/main.go", "/src/static/logo.png"}},
+	})
+
+	events = nil
+	failure := errors.New("container is not running")
+	err = s.handleWatchBatch(t.Context(), project, options, batch[:1], rules, failingSyncer{err: failure}, nil)
+	assert.Equal(t, err, failure)
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[1].Type, api.WatchEventAction)
+	assert.Equal(t, events[1].Error, failure)
+}
+
+func TestWatchRuleGitIgnore(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("dist/\n"), 0o644))
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "dist"), 0o755))
+
+	rules, err := getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{
+			{Path: dir, Action: types.WatchActionSync, Target: "/app", Extensions: types.Extensions{watchGitIgnoreExtension: true}},
+			{Path: dir, Action: types.WatchActionSync, Target: "/srv"},
+		},
+	}, types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+
+	built := watch.NewFileEvent(filepath.Join(dir, "dist", "bundle.js"))
+	assert.Assert(t, rules[0].Matches(built) == nil)
+	assert.Assert(t, rules[1].Matches(built) != nil)
+	assert.Assert(t, rules[0].Matches(watch.NewFileEvent(filepath.Join(dir, "main.js"))) != nil)
+
+	_, err = getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{{Path: dir, Action: types.WatchActionSync, Target: "/app", Extensions: types.Extensions{watchGitIgnoreExtension: "yes"}}},
+	}, types.ServiceConfig{Name: "web"})
+	assert.ErrorContains(t, err, "x-gitignore must be a boolean")
+}
@@ -0,0 +1,248 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	gsync "sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+// Watch rule extensions tuning when their actions are applied:
+//
+//	develop:
+//	  watch:
+//	    - path: ./proto
+//	      action: rebuild
+//	      x-quiet_period: 3s
+//	      x-rebuild_interval: 30s
+const (
+	// watchQuietPeriodExtension overrides the time without file changes
+	// closing a batch of changes
+	watchQuietPeriodExtension = "x-quiet_period"
+	// watchRebuildIntervalExtension sets the minimum time between two
+	// rebuilds of the service
+	watchRebuildIntervalExtension = "x-rebuild_interval"
+)
+
+// durationExtension reads a duration from a watch rule extension.
+func durationExtension(trigger types.Trigger, name string) (time.Duration, error) {
+	v, ok := trigger.Extensions[name]
+	if !ok {
+		return 0, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("watch rule %s: %s must be a duration, got %v", trigger.Path, name, v)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("watch rule %s: invalid %s %q", trigger.Path, name, s)
+	}
+	return d, nil
+}
+
+// ruleBatch is a batch of file changes for the watch rules sharing a quiet
+// period.
+type ruleBatch struct {
+	events []watch.FileEvent
+	rules  []watchRule
+}
+
+// debounceWatchEvents groups the file events into batches per quiet period:
+// the events are batched for each group of rules sharing a quiet period,
+// independently of the other groups.
+func (s *composeService) debounceWatchEvents(ctx context.Context, input <-chan watch.FileEvent, rules []watchRule, quietPeriod time.Duration) <-chan ruleBatch {
+	if quietPeriod == 0 {
+		quietPeriod = watch.QuietPeriod
+	}
+	byPeriod := map[time.Duration][]watchRule{}
+	for _, rule := range rules {
+		period := quietPeriod
+		if rule.quietPeriod != 0 {
+			period = rule.quietPeriod
+		}
+		byPeriod[period] = append(byPeriod[period], rule)
+	}
+	periods := make([]time.Duration, 0, len(byPeriod))
+	for period := range byPeriod {
+		periods = append(periods, period)
+	}
+	slices.Sort(periods)
+
+	out := make(chan ruleBatch)
+	inputs := make([]chan watch.FileEvent, len(periods))
+	var wg gsync.WaitGroup
+	for i, period := range periods {
+		inputs[i] = make(chan watch.FileEvent)
+		batches := watch.BatchDebounceEventsWithPeriod(ctx, s.clock, inputs[i], period)
+		wg.Go(func() {
+			for batch := range batches {
+				select {
+				case out <- ruleBatch{events: batch, rules: byPeriod[period]}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		defer func() {
+			for _, in := range inputs {
+				close(in)
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-input:
+				if !ok {
+					return
+				}
+				for i, period := range periods {
+					if !slices.ContainsFunc(byPeriod[period], func(rule watchRule) bool { return rule.Matches(event) != nil }) {
+						continue
+					}
+					select {
+					case inputs[i] <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// rebuildThrottle delays the rebuilds of a service requested less than its
+// rebuild interval after the previous one completed.
+type rebuildThrottle struct {
+	clock     clockwork.Clock
+	intervals map[string]time.Duration
+	last      map[string]time.Time
+	pending   map[string]bool
+	timer     clockwork.Timer
+}
+
+func newRebuildThrottle(clock clockwork.Clock, rules []watchRule, interval time.Duration) *rebuildThrottle {
+	t := &rebuildThrottle{
+		clock:     clock,
+		intervals: map[string]time.Duration{},
+		last:      map[string]time.Time{},
+		pending:   map[string]bool{},
+	}
+	for _, rule := range rules {
+		if rule.Action != types.WatchActionRebuild {
+			continue
+		}
+		serviceInterval := interval
+		if rule.rebuildInterval != 0 {
+			serviceInterval = rule.rebuildInterval
+		}
+		t.intervals[rule.service] = max(t.intervals[rule.service], serviceInterval)
+	}
+	return t
+}
+
+// allow returns the services which can be rebuilt now, the others are
+// pending until C fires.
+func (t *rebuildThrottle) allow(services []string) (allowed []string, delayed map[string]time.Duration) {
+	if t == nil {
+		return services, nil
+	}
+	now := t.clock.Now()
+	for _, service := range services {
+		last, ok := t.last[service]
+		wait := last.Add(t.intervals[service]).Sub(now)
+		if !ok || wait <= 0 {
+			delete(t.pending, service)
+			allowed = append(allowed, service)
+			continue
+		}
+		if delayed == nil {
+			delayed = map[string]time.Duration{}
+		}
+		delayed[service] = wait
+		t.pending[service] = true
+	}
+	t.schedule()
+	return allowed, delayed
+}
+
+// rebuilt records the completion of the rebuild of services.
+func (t *rebuildThrottle) rebuilt(services []string) {
+	if t == nil {
+		return
+	}
+	now := t.clock.Now()
+	for _, service := range services {
+		t.last[service] = now
+	}
+}
+
+// due returns the pending services which can be rebuilt now.
+func (t *rebuildThrottle) due() []string {
+	pending := make([]string, 0, len(t.pending))
+	for service := range t.pending {
+		pending = append(pending, service)
+	}
+	slices.Sort(pending)
+	allowed, _ := t.allow(pending)
+	return allowed
+}
+
+// C fires when a pending rebuild is due, it is nil when none is pending.
+func (t *rebuildThrottle) C() <-chan time.Time {
+	if t == nil || t.timer == nil || len(t.pending) == 0 {
+		return nil
+	}
+	return t.timer.Chan()
+}
+
+// schedule sets the timer for the earliest pending rebuild.
+func (t *rebuildThrottle) schedule() {
+	var next time.Time
+	for service := range t.pending {
+		due := t.last[service].Add(t.intervals[service])
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	if next.IsZero() {
+		return
+	}
+	wait := next.Sub(t.clock.Now())
+	if t.timer == nil {
+		t.timer = t.clock.NewTimer(wait)
+		return
+	}
+	t.timer.Stop()
+	t.timer.Reset(wait)
+}
@@ -0,0 +1,112 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/jonboulle/clockwork"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose/v5/pkg/watch"
+)
+
+func TestWatchRulesExtensions(t *testing.T) {
+	rules, err := getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{
+			{Path: "/src", Action: types.WatchActionSync, Target: "/app"},
+			{Path: "/proto", Action: types.WatchActionRebuild, Extensions: types.Extensions{
+				watchQuietPeriodExtension:     "3s",
+				watchRebuildIntervalExtension: "30s",
+			}},
+		},
+	}, types.ServiceConfig{Name: "api"})
+	assert.NilError(t, err)
+	assert.Equal(t, rules[0].quietPeriod, time.Duration(0))
+	assert.Equal(t, rules[1].quietPeriod, 3*time.Second)
+	assert.Equal(t, rules[1].rebuildInterval, 30*time.Second)
+
+	_, err = getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{{Path: "/proto", Action: types.WatchActionRebuild, Extensions: types.Extensions{
+			watchQuietPeriodExtension: "soon",
+		}}},
+	}, types.ServiceConfig{Name: "api"})
+	assert.Error(t, err, `watch rule /proto: invalid x-quiet_period "soon"`)
+}
+
+func TestDebounceWatchEventsPerQuietPeriod(t *testing.T) {
+	rules, err := getWatchRules(&types.DevelopConfig{
+		Watch: []types.Trigger{
+			{Path: "/src", Action: types.WatchActionSync, Target: "/app"},
+			{Path: "/proto", Action: types.WatchActionRebuild, Extensions: types.Extensions{
+				watchQuietPeriodExtension: "3s",
+			}},
+		},
+	}, types.ServiceConfig{Name: "api"})
+	assert.NilError(t, err)
+
+	clock := clockwork.NewFakeClock()
+	s := &composeService{clock: clock}
+	input := make(chan watch.FileEvent)
+	batches := s.debounceWatchEvents(t.Context(), input, rules, 0)
+
+	input <- watch.NewFileEvent("/src/main.go")
+	input <- watch.NewFileEvent("/proto/api.proto")
+	// a ticker per quiet period, reset by each event
+	assert.NilError(t, clock.BlockUntilContext(t.Context(), 4))
+
+	clock.Advance(watch.QuietPeriod)
+	batch := <-batches
+	assert.DeepEqual(t, batch.events, []watch.FileEvent{"/src/main.go"})
+	assert.Equal(t, len(batch.rules), 1)
+	assert.Equal(t, batch.rules[0].Path, "/src")
+
+	clock.Advance(3*time.Second - watch.QuietPeriod)
+	batch = <-batches
+	assert.DeepEqual(t, batch.events, []watch.FileEvent{"/proto/api.proto"})
+	assert.Equal(t, batch.rules[0].Path, "/proto")
+}
+
+func TestRebuildThrottle(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	throttle := newRebuildThrottle(clock, []watchRule{
+		{Trigger: types.Trigger{Action: types.WatchActionRebuild}, service: "api", rebuildInterval: 10 * time.Second},
+		{Trigger: types.Trigger{Action: types.WatchActionRebuild}, service: "web"},
+	}, time.Second)
+	assert.Assert(t, throttle.C() == nil)
+
+	allowed, delayed := throttle.allow([]string{"api", "web"})
+	assert.DeepEqual(t, allowed, []string{"api", "web"})
+	assert.Assert(t, delayed == nil)
+	throttle.rebuilt(allowed)
+
+	clock.Advance(2 * time.Second)
+	allowed, delayed = throttle.allow([]string{"api", "web"})
+	assert.DeepEqual(t, allowed, []string{"web"})
+	assert.DeepEqual(t, delayed, map[string]time.Duration{"api": 8 * time.Second})
+	throttle.rebuilt(allowed)
+
+	// requested again while pending: still a single rebuild, once due
+	_, delayed = throttle.allow([]string{"api"})
+	assert.DeepEqual(t, delayed, map[string]time.Duration{"api": 8 * time.Second})
+	clock.Advance(8 * time.Second)
+	<-throttle.C()
+	assert.DeepEqual(t, throttle.due(), []string{"api"})
+	assert.Assert(t, throttle.C() == nil)
+}
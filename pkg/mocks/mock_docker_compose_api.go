@@ -41,6 +41,20 @@ func (m *MockCompose) EXPECT() *MockComposeMockRecorder {
 	return m.recorder
 }
 
+// Apply mocks base method.
+func (m *MockCompose) Apply(ctx context.Context, plan *api.Plan, options api.ApplyOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, plan, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockComposeMockRecorder) Apply(ctx, plan, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockCompose)(nil).Apply), ctx, plan, options)
+}
+
 // Attach mocks base method.
 func (m *MockCompose) Attach(ctx context.Context, projectName string, options api.AttachOptions) error {
 	m.ctrl.T.Helper()
@@ -125,6 +139,21 @@ func (mr *MockComposeMockRecorder) Down(ctx, projectName, options any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Down", reflect.TypeOf((*MockCompose)(nil).Down), ctx, projectName, options)
 }
 
+// Drift mocks base method.
+func (m *MockCompose) Drift(ctx context.Context, project *types.Project, options api.DriftOptions) ([]api.Drift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drift", ctx, project, options)
+	ret0, _ := ret[0].([]api.Drift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drift indicates an expected call of Drift.
+func (mr *MockComposeMockRecorder) Drift(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drift", reflect.TypeOf((*MockCompose)(nil).Drift), ctx, project, options)
+}
+
 // Events mocks base method.
 func (m *MockCompose) Events(ctx context.Context, projectName string, options api.EventsOptions) error {
 	m.ctrl.T.Helper()
@@ -227,6 +256,21 @@ func (mr *MockComposeMockRecorder) List(ctx, options any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCompose)(nil).List), ctx, options)
 }
 
+// LoadBundle mocks base method.
+func (m *MockCompose) LoadBundle(ctx context.Context, options api.BundleLoadOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBundle", ctx, options)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadBundle indicates an expected call of LoadBundle.
+func (mr *MockComposeMockRecorder) LoadBundle(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBundle", reflect.TypeOf((*MockCompose)(nil).LoadBundle), ctx, options)
+}
+
 // LoadProject mocks base method.
 func (m *MockCompose) LoadProject(ctx context.Context, options api.ProjectLoadOptions) (*types.Project, error) {
 	m.ctrl.T.Helper()
@@ -270,6 +314,21 @@ func (mr *MockComposeMockRecorder) Pause(ctx, projectName, options any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockCompose)(nil).Pause), ctx, projectName, options)
 }
 
+// Plan mocks base method.
+func (m *MockCompose) Plan(ctx context.Context, project *types.Project, options api.PlanOptions) (*api.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", ctx, project, options)
+	ret0, _ := ret[0].(*api.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockComposeMockRecorder) Plan(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockCompose)(nil).Plan), ctx, project, options)
+}
+
 // Port mocks base method.
 func (m *MockCompose) Port(ctx context.Context, projectName, service string, port uint16, options api.PortOptions) (string, int, error) {
 	m.ctrl.T.Helper()
@@ -386,6 +445,20 @@ func (mr *MockComposeMockRecorder) RunOneOffContainer(ctx, project, opts any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunOneOffContainer", reflect.TypeOf((*MockCompose)(nil).RunOneOffContainer), ctx, project, opts)
 }
 
+// SaveBundle mocks base method.
+func (m *MockCompose) SaveBundle(ctx context.Context, project *types.Project, options api.BundleSaveOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBundle", ctx, project, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBundle indicates an expected call of SaveBundle.
+func (mr *MockComposeMockRecorder) SaveBundle(ctx, project, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBundle", reflect.TypeOf((*MockCompose)(nil).SaveBundle), ctx, project, options)
+}
+
 // Scale mocks base method.
 func (m *MockCompose) Scale(ctx context.Context, project *types.Project, options api.ScaleOptions) error {
 	m.ctrl.T.Helper()
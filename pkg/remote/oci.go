@@ -219,17 +219,26 @@ func (g *ociRemoteLoader) Dir(path string) string {
 }
 
 func (g *ociRemoteLoader) pullComposeFiles(ctx context.Context, local string, manifest spec.Manifest, ref reference.Named, resolver remotes.Resolver) error {
+	return WriteComposeArtifact(local, ref.String(), manifest, func(layer spec.Descriptor) ([]byte, error) {
+		return oci.GetBlob(ctx, resolver, ref, layer)
+	})
+}
+
+// WriteComposeArtifact writes the compose files and env files of the compose
+// project OCI artifact name to the local directory, getting the content of
+// its layers with blob.
+func WriteComposeArtifact(local string, name string, manifest spec.Manifest, blob func(spec.Descriptor) ([]byte, error)) error {
 	err := os.MkdirAll(local, 0o700)
 	if err != nil {
 		return err
 	}
 	if (manifest.ArtifactType != "" && manifest.ArtifactType != oci.ComposeProjectArtifactType) ||
 		(manifest.ArtifactType == "" && manifest.Config.MediaType != oci.ComposeEmptyConfigMediaType) {
-		return fmt.Errorf("%s is not a compose project OCI artifact, but %s", ref.String(), manifest.ArtifactType)
+		return fmt.Errorf("%s is not a compose project OCI artifact, but %s", name, manifest.ArtifactType)
 	}
 
 	for i, layer := range manifest.Layers {
-		content, err := oci.GetBlob(ctx, resolver, ref, layer)
+		content, err := blob(layer)
 		if err != nil {
 			return err
 		}
@@ -24,6 +24,7 @@ import (
 	"github.com/docker/compose/v5/pkg/utils"
 )
 
+// QuietPeriod is the default time window without file events closing a batch
 const QuietPeriod = 500 * time.Millisecond
 
 // BatchDebounceEvents groups identical file events within a sliding time window and writes the results to the returned
@@ -31,6 +32,11 @@ const QuietPeriod = 500 * time.Millisecond
 //
 // The returned channel is closed when the debouncer is stopped via context cancellation or by closing the input channel.
 func BatchDebounceEvents(ctx context.Context, clock clockwork.Clock, input <-chan FileEvent) <-chan []FileEvent {
+	return BatchDebounceEventsWithPeriod(ctx, clock, input, QuietPeriod)
+}
+
+// BatchDebounceEventsWithPeriod is BatchDebounceEvents with a quiet period other than QuietPeriod.
+func BatchDebounceEventsWithPeriod(ctx context.Context, clock clockwork.Clock, input <-chan FileEvent, quietPeriod time.Duration) <-chan []FileEvent {
 	out := make(chan []FileEvent)
 	go func() {
 		defer close(out)
@@ -49,7 +55,7 @@ func BatchDebounceEvents(ctx context.Context, clock clockwork.Clock, input <-cha
 			seen = utils.Set[FileEvent]{}
 		}
 
-		t := clock.NewTicker(QuietPeriod)
+		t := clock.NewTicker(quietPeriod)
 		defer t.Stop()
 		for {
 			select {
@@ -66,7 +72,7 @@ func BatchDebounceEvents(ctx context.Context, clock clockwork.Clock, input <-cha
 				if _, ok := seen[e]; !ok {
 					seen.Add(e)
 				}
-				t.Reset(QuietPeriod)
+				t.Reset(quietPeriod)
 			}
 		}
 	}()
@@ -64,3 +64,31 @@ func Test_BatchDebounceEvents(t *testing.T) {
 		// channel is empty
 	}
 }
+
+func Test_BatchDebounceEventsWithPeriod(t *testing.T) {
+	ch := make(chan FileEvent)
+	clock := clockwork.NewFakeClock()
+	ctx, stop := context.WithCancel(t.Context())
+	t.Cleanup(stop)
+
+	eventBatchCh := BatchDebounceEventsWithPeriod(ctx, clock, ch, 2*time.Second)
+	ch <- FileEvent("/a")
+	err := clock.BlockUntilContext(ctx, 2)
+	assert.NilError(t, err)
+
+	// the default quiet period is not enough
+	clock.Advance(QuietPeriod)
+	select {
+	case batch := <-eventBatchCh:
+		t.Fatalf("unexpected events: %v", batch)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	clock.Advance(2*time.Second - QuietPeriod)
+	select {
+	case batch := <-eventBatchCh:
+		assert.DeepEqual(t, batch, []FileEvent{"/a"})
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("timed out waiting for events")
+	}
+}
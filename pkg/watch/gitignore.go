@@ -0,0 +1,264 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose/v5/internal/paths"
+)
+
+// gitIgnoreMatcher matches the paths git ignores according to the .gitignore
+// files of the directories from the repository root down to the path, and
+// the repository .git/info/exclude file. Deeper files take precedence, the
+// last matching pattern of a file wins and, as with git, a path can't be
+// re-included when one of its parent directories is ignored.
+type gitIgnoreMatcher struct {
+	// top is the repository root, or the watched directory outside a repository
+	top string
+
+	mu    sync.Mutex
+	files map[string]*gitIgnoreFile // by directory
+}
+
+// gitIgnoreFile holds the patterns of an ignore file, reloaded when modified.
+type gitIgnoreFile struct {
+	modTime  time.Time
+	patterns []gitIgnorePattern
+}
+
+type gitIgnorePattern struct {
+	regexp   *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	basename bool // matches the base name at any depth, the pattern has no slash
+}
+
+// NewGitIgnoreMatcher returns a PathMatcher honoring the .gitignore files of
+// the git repository holding root, or of root and its subdirectories when
+// root is not in a repository.
+func NewGitIgnoreMatcher(root string) (PathMatcher, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := os.Stat(root); err == nil && !fi.IsDir() {
+		root = filepath.Dir(root)
+	}
+	top := root
+	for dir := root; ; dir = filepath.Dir(dir) {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			top = dir
+			break
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return &gitIgnoreMatcher{top: top, files: map[string]*gitIgnoreFile{}}, nil
+}
+
+func (m *gitIgnoreMatcher) Matches(f string) (bool, error) {
+	f, err := filepath.Abs(f)
+	if err != nil {
+		return false, err
+	}
+	if !paths.IsChild(m.top, f) || f == m.top {
+		return false, nil
+	}
+	fi, err := os.Lstat(f)
+	isDir := err == nil && fi.IsDir()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rel, err := filepath.Rel(m.top, f)
+	if err != nil {
+		return false, err
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	// a path in an ignored directory is ignored
+	for i := 1; i < len(segments); i++ {
+		if m.ignored(segments[:i], true) {
+			return true, nil
+		}
+	}
+	return m.ignored(segments, isDir), nil
+}
+
+// MatchesEntireDir matches the ignored directories: git doesn't re-include
+// the files of an ignored directory.
+func (m *gitIgnoreMatcher) MatchesEntireDir(f string) (bool, error) {
+	return m.Matches(f)
+}
+
+// ignored evaluates the patterns applying to a path, given as its segments
+// relative to the top directory, from the top ignore file down to the one of
+// its parent directory.
+func (m *gitIgnoreMatcher) ignored(segments []string, isDir bool) bool {
+	ignored := false
+	for depth := 0; depth < len(segments); depth++ {
+		dir := filepath.Join(m.top, filepath.Join(segments[:depth]...))
+		rel := strings.Join(segments[depth:], "/")
+		for _, p := range m.patterns(dir) {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			subject := rel
+			if p.basename {
+				subject = segments[len(segments)-1]
+			}
+			if p.regexp.MatchString(subject) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// patterns returns the patterns of the ignore files of dir, the repository
+// exclude file coming first for the top directory.
+func (m *gitIgnoreMatcher) patterns(dir string) []gitIgnorePattern {
+	patterns := m.load(filepath.Join(dir, ".gitignore"))
+	if dir == m.top {
+		patterns = append(m.load(filepath.Join(dir, ".git", "info", "exclude")), patterns...)
+	}
+	return patterns
+}
+
+func (m *gitIgnoreMatcher) load(name string) []gitIgnorePattern {
+	fi, err := os.Stat(name)
+	if err != nil {
+		delete(m.files, name)
+		return nil
+	}
+	if f, ok := m.files[name]; ok && f.modTime.Equal(fi.ModTime()) {
+		return f.patterns
+	}
+	content, err := os.ReadFile(name)
+	if err != nil {
+		logrus.Debugf("cannot read %s: %v", name, err)
+		return nil
+	}
+	f := &gitIgnoreFile{modTime: fi.ModTime(), patterns: parseGitIgnore(content)}
+	m.files[name] = f
+	return f.patterns
+}
+
+// parseGitIgnore parses the content of a .gitignore file, skipping invalid
+// patterns.
+func parseGitIgnore(content []byte) []gitIgnorePattern {
+	var patterns []gitIgnorePattern
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := trimGitIgnoreSpaces(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p gitIgnorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			p.basename = true
+		}
+		re, err := regexp.Compile(gitIgnoreRegexp(line))
+		if err != nil {
+			logrus.Debugf("skipping invalid .gitignore pattern %q: %v", line, err)
+			continue
+		}
+		p.regexp = re
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// trimGitIgnoreSpaces removes the trailing spaces of a line, unless escaped.
+func trimGitIgnoreSpaces(line string) string {
+	trimmed := strings.TrimRight(line, " ")
+	if strings.HasSuffix(trimmed, `\`) && len(trimmed) < len(line) {
+		trimmed = trimmed[:len(trimmed)-1] + " "
+	}
+	return trimmed
+}
+
+// gitIgnoreRegexp converts a gitignore glob into an anchored regexp.
+func gitIgnoreRegexp(pattern string) string {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			// leading or middle **/ matches any directories, or none
+			if i == 0 || pattern[i-1] == '/' {
+				re.WriteString("(?:.*/)?")
+				i += 2
+			} else {
+				re.WriteString("[^/]*")
+				i++
+			}
+		case strings.HasPrefix(pattern[i:], "**") && i+2 == len(pattern) && (i == 0 || pattern[i-1] == '/'):
+			// trailing /** matches everything inside
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			re.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	return re.String()
+}
@@ -0,0 +1,124 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NilError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestGitIgnoreMatcher(t *testing.T) {
+	repo := t.TempDir()
+	writeTree(t, repo, map[string]string{
+		".git/info/exclude":     "*.swp\n",
+		".gitignore":            "# build artifacts\n*.log\n!keep.log\n/dist\nbuild/\ndocs/**/*.html\n",
+		"api/.gitignore":        "generated/\n!*.pb.go\ntmp?.txt\n",
+		"api/main.go":           "package main",
+		"api/debug.log":         "",
+		"api/keep.log":          "",
+		"api/main.go.swp":       "",
+		"api/tmp1.txt":          "",
+		"api/dist/app":          "",
+		"api/build/app":         "",
+		"api/generated/a.pb.go": "",
+		"dist/app":              "",
+		"docs/api/index.html":   "",
+		"docs/index.html":       "",
+	})
+
+	// the matcher of a service directory honors the repository ignore files
+	m, err := NewGitIgnoreMatcher(filepath.Join(repo, "api"))
+	assert.NilError(t, err)
+	matches := func(name string) bool {
+		t.Helper()
+		ignored, err := m.Matches(filepath.Join(repo, filepath.FromSlash(name)))
+		assert.NilError(t, err)
+		return ignored
+	}
+
+	assert.Assert(t, !matches("api/main.go"))
+	assert.Assert(t, matches("api/debug.log"))
+	assert.Assert(t, !matches("api/keep.log"), "negated pattern")
+	assert.Assert(t, matches("api/main.go.swp"), "info/exclude")
+	assert.Assert(t, matches("api/tmp1.txt"), "nested .gitignore")
+	assert.Assert(t, !matches("api/dist/app"), "anchored to the repository root")
+	assert.Assert(t, matches("dist/app"))
+	assert.Assert(t, matches("api/build/app"), "directory pattern")
+	assert.Assert(t, matches("api/generated/a.pb.go"), "can't re-include a file of an ignored directory")
+	assert.Assert(t, matches("docs/api/index.html"))
+	assert.Assert(t, matches("docs/index.html"), "**/ matches no directory")
+
+	entireDir, err := m.MatchesEntireDir(filepath.Join(repo, "api", "build"))
+	assert.NilError(t, err)
+	assert.Assert(t, entireDir)
+
+	// ignore files are reloaded when modified
+	later := time.Now().Add(time.Minute)
+	writeTree(t, repo, map[string]string{"api/.gitignore": "main.go\n"})
+	assert.NilError(t, os.Chtimes(filepath.Join(repo, "api/.gitignore"), later, later))
+	assert.Assert(t, matches("api/main.go"))
+	assert.Assert(t, !matches("api/tmp1.txt"))
+}
+
+func TestGitIgnoreMatcherOutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".gitignore":     "node_modules\n",
+		"src/.gitignore": "*.gen.ts\n",
+	})
+	m, err := NewGitIgnoreMatcher(dir)
+	assert.NilError(t, err)
+
+	for name, expected := range map[string]bool{
+		"node_modules/lib/index.js": true,
+		"src/node_modules":          true,
+		"src/api.gen.ts":            true,
+		"api.gen.ts":                false,
+		"src/main.ts":               false,
+	} {
+		ignored, err := m.Matches(filepath.Join(dir, filepath.FromSlash(name)))
+		assert.NilError(t, err)
+		assert.Equal(t, ignored, expected, name)
+	}
+}
+
+func TestGitIgnoreRegexp(t *testing.T) {
+	for pattern, expected := range map[string]string{
+		"*.log":        `^[^/]*\.log$`,
+		"**/foo":       `^(?:.*/)?foo$`,
+		"abc/**":       `^abc/.*$`,
+		"a/**/b":       `^a/(?:.*/)?b$`,
+		"[!a-c]?.txt":  `^[^a-c][^/]\.txt$`,
+		`\#not-a-tag*`: `^#not-a-tag[^/]*$`,
+	} {
+		assert.Equal(t, gitIgnoreRegexp(pattern), expected, pattern)
+	}
+	assert.Equal(t, trimGitIgnoreSpaces(`trailing\ `+"  "), "trailing ")
+}
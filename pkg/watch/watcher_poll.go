@@ -0,0 +1,283 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+
+	pathutil "github.com/docker/compose/v5/internal/paths"
+)
+
+// File watcher backends
+const (
+	// BackendNative relies on the OS file notifications (inotify, FSEvents,
+	// ReadDirectoryChangesW)
+	BackendNative = "native"
+	// BackendPoll periodically scans the watched paths, for filesystems which
+	// don't deliver notifications (NFS, SMB, 9p, VM shared folders)
+	BackendPoll = "poll"
+)
+
+// DefaultPollInterval is the delay between two scans of the polling watcher
+const DefaultPollInterval = time.Second
+
+// PollOptions configures the polling watcher
+type PollOptions struct {
+	// Interval between two scans of the watched paths, DefaultPollInterval if
+	// not set
+	Interval time.Duration
+	// Hash compares the files content, so that changes which preserve both
+	// the modification time and the size are detected. All the watched files
+	// are read on every scan.
+	Hash bool
+	// Clock drives the scans, the real clock if not set
+	Clock clockwork.Clock
+}
+
+// A file watcher which periodically scans the watched trees and compares
+// the files modification time and size (and optionally content) with the
+// previous scan. Slower to report changes than the native watchers, but it
+// works on filesystems which don't deliver notifications.
+type pollNotify struct {
+	roots   []string
+	ignore  PathMatcher
+	options PollOptions
+
+	// snapshot is the state of the watched files at the last scan, by path.
+	// Only accessed by the loop once started.
+	snapshot map[string]fileState
+
+	events    chan FileEvent
+	errors    chan error
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type fileState struct {
+	isDir   bool
+	modTime time.Time
+	size    int64
+	mode    fs.FileMode
+	hash    [sha256.Size]byte
+}
+
+// NewPollingWatcher returns a Notify which scans the paths every
+// options.Interval. Paths the ignore matcher matches are not scanned: a
+// directory it matches entirely is skipped with its content.
+func NewPollingWatcher(paths []string, ignore PathMatcher, options PollOptions) (Notify, error) {
+	if options.Interval <= 0 {
+		options.Interval = DefaultPollInterval
+	}
+	if options.Clock == nil {
+		options.Clock = clockwork.NewRealClock()
+	}
+	if ignore == nil {
+		ignore = EmptyMatcher{}
+	}
+	roots := make([]string, 0, len(paths))
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("newPollingWatcher: %w", err)
+		}
+		roots = append(roots, path)
+	}
+	return &pollNotify{
+		roots:   pathutil.EncompassingPaths(roots),
+		ignore:  ignore,
+		options: options,
+		events:  make(chan FileEvent),
+		errors:  make(chan error),
+		stop:    make(chan struct{}),
+	}, nil
+}
+
+func (d *pollNotify) Start() error {
+	snapshot, err := d.scan()
+	if err != nil {
+		return err
+	}
+	d.snapshot = snapshot
+	numberOfWatches.Add(int64(len(d.roots)))
+	go d.loop()
+	return nil
+}
+
+func (d *pollNotify) Close() error {
+	d.closeOnce.Do(func() {
+		numberOfWatches.Add(int64(-len(d.roots)))
+		close(d.stop)
+	})
+	return nil
+}
+
+func (d *pollNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *pollNotify) Errors() chan error {
+	return d.errors
+}
+
+func (d *pollNotify) loop() {
+	defer close(d.events)
+	defer close(d.errors)
+	ticker := d.options.Clock.NewTicker(d.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.Chan():
+		}
+
+		snapshot, err := d.scan()
+		if err != nil {
+			// network filesystems fail transiently, compare with the next scan
+			logrus.Warnf("Scanning watched files: %v", err)
+			continue
+		}
+		for _, path := range changedPaths(d.snapshot, snapshot) {
+			select {
+			case d.events <- NewFileEvent(path):
+			case <-d.stop:
+				return
+			}
+		}
+		d.snapshot = snapshot
+	}
+}
+
+// changedPaths lists the paths created, modified or removed between two
+// scans, sorted. Directories are only reported when created or removed:
+// their modification time changes with their content, which is reported.
+func changedPaths(before, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		previous, ok := before[path]
+		switch {
+		case !ok:
+			changed = append(changed, path)
+		case state.isDir && previous.isDir:
+		case state != previous:
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// scan walks the watched paths and records the state of the files and
+// directories which are not ignored. Paths which don't exist (yet) are
+// skipped, and so are directories we are not allowed to read.
+func (d *pollNotify) scan() (map[string]fileState, error) {
+	snapshot := map[string]fileState{}
+	for _, root := range d.roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				if os.IsPermission(err) {
+					logrus.Debugf("Not watching %s: %v", path, err)
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if path != root {
+				if skip, err := d.skip(path, entry.IsDir()); skip || err != nil {
+					return err
+				}
+			}
+			info, err := entry.Info()
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			state := fileState{
+				isDir: entry.IsDir(),
+				mode:  info.Mode(),
+			}
+			if !state.isDir {
+				state.modTime = info.ModTime()
+				state.size = info.Size()
+				if d.options.Hash && info.Mode().IsRegular() {
+					if state.hash, err = hashFile(path); err != nil && !os.IsNotExist(err) {
+						return err
+					}
+				}
+			}
+			snapshot[path] = state
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", root, err)
+		}
+	}
+	return snapshot, nil
+}
+
+// skip tells whether the ignore matcher excludes path, returning
+// filepath.SkipDir for directories it matches entirely.
+func (d *pollNotify) skip(path string, isDir bool) (bool, error) {
+	if isDir {
+		entireDir, err := d.ignore.MatchesEntireDir(path)
+		if err != nil {
+			return true, err
+		}
+		if entireDir {
+			logrus.Debugf("Ignoring directory and its contents (recursively): %s", path)
+			return true, filepath.SkipDir
+		}
+	}
+	return d.ignore.Matches(path)
+}
+
+func hashFile(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	f, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+var _ Notify = &pollNotify{}
@@ -0,0 +1,150 @@
+/*
+   Copyright 2026 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"gotest.tools/v3/assert"
+)
+
+// pollFixture runs a polling watcher on a temporary directory, scanning
+// whenever tick is called.
+type pollFixture struct {
+	t       *testing.T
+	dir     string
+	clock   *clockwork.FakeClock
+	watcher Notify
+}
+
+func newPollFixture(t *testing.T, hash bool, ignores ...string) *pollFixture {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NilError(t, err)
+	f := &pollFixture{t: t, dir: dir, clock: clockwork.NewFakeClock()}
+	f.write("src/main.go", "package main")
+	f.write("node_modules/lib/index.js", "module.exports = {}")
+
+	ignore, err := NewDockerPatternMatcher(dir, ignores)
+	assert.NilError(t, err)
+	f.watcher, err = NewPollingWatcher([]string{dir}, ignore, PollOptions{Hash: hash, Clock: f.clock})
+	assert.NilError(t, err)
+	assert.NilError(t, f.watcher.Start())
+	t.Cleanup(func() { _ = f.watcher.Close() })
+	return f
+}
+
+func (f *pollFixture) path(name string) string {
+	return filepath.Join(f.dir, filepath.FromSlash(name))
+}
+
+func (f *pollFixture) write(name, content string) {
+	f.t.Helper()
+	assert.NilError(f.t, os.MkdirAll(filepath.Dir(f.path(name)), 0o755))
+	assert.NilError(f.t, os.WriteFile(f.path(name), []byte(content), 0o644))
+}
+
+// tick triggers a scan and returns the events it reports.
+func (f *pollFixture) tick() []FileEvent {
+	f.t.Helper()
+	ctx, cancel := context.WithTimeout(f.t.Context(), 5*time.Second)
+	defer cancel()
+	assert.NilError(f.t, f.clock.BlockUntilContext(ctx, 1))
+	f.clock.Advance(DefaultPollInterval)
+
+	var events []FileEvent
+	for {
+		select {
+		case e := <-f.watcher.Events():
+			events = append(events, e)
+		case <-time.After(100 * time.Millisecond):
+			return events
+		case <-ctx.Done():
+			f.t.Fatal("timeout waiting for scan")
+		}
+	}
+}
+
+func (f *pollFixture) events(names ...string) []FileEvent {
+	events := make([]FileEvent, 0, len(names))
+	for _, name := range names {
+		events = append(events, NewFileEvent(f.path(name)))
+	}
+	return events
+}
+
+func TestPollingWatcher(t *testing.T) {
+	f := newPollFixture(t, false)
+	assert.Assert(t, f.tick() == nil)
+
+	f.write("src/main.go", "package main // changed")
+	assert.DeepEqual(t, f.tick(), f.events("src/main.go"))
+
+	f.write("src/lib/util.go", "package lib")
+	assert.DeepEqual(t, f.tick(), f.events("src/lib", "src/lib/util.go"))
+
+	assert.NilError(t, os.RemoveAll(f.path("src/lib")))
+	assert.DeepEqual(t, f.tick(), f.events("src/lib", "src/lib/util.go"))
+}
+
+func TestPollingWatcherIgnores(t *testing.T) {
+	f := newPollFixture(t, false, "node_modules", "**/*.tmp")
+
+	f.write("node_modules/lib/index.js", "changed")
+	f.write("src/build.tmp", "ignored")
+	f.write("src/main.go", "package main // changed")
+	assert.DeepEqual(t, f.tick(), f.events("src/main.go"))
+}
+
+func TestPollingWatcherHash(t *testing.T) {
+	f := newPollFixture(t, true)
+	info, err := os.Stat(f.path("src/main.go"))
+	assert.NilError(t, err)
+
+	// same size, same modification time: only the content tells
+	f.write("src/main.go", "package mian")
+	assert.NilError(t, os.Chtimes(f.path("src/main.go"), info.ModTime(), info.ModTime()))
+	assert.DeepEqual(t, f.tick(), f.events("src/main.go"))
+}
+
+func TestPollingWatcherMissingPath(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NilError(t, err)
+	clock := clockwork.NewFakeClock()
+	watcher, err := NewPollingWatcher([]string{filepath.Join(dir, "later")}, nil, PollOptions{Clock: clock})
+	assert.NilError(t, err)
+	assert.NilError(t, watcher.Start())
+	f := &pollFixture{t: t, dir: dir, clock: clock, watcher: watcher}
+	t.Cleanup(func() { _ = watcher.Close() })
+
+	f.write("later/file.txt", "created")
+	assert.DeepEqual(t, f.tick(), f.events("later", "later/file.txt"))
+}
+
+func TestPollingWatcherClose(t *testing.T) {
+	f := newPollFixture(t, false)
+	assert.NilError(t, f.watcher.Close())
+	assert.NilError(t, f.watcher.Close())
+
+	_, open := <-f.watcher.Events()
+	assert.Assert(t, !open)
+}